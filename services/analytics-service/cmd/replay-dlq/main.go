@@ -0,0 +1,27 @@
+// Command replay-dlq moves dead-lettered events back to the analytics events exchange,
+// e.g. after the bug that made them fail has been fixed.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/config"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/messaging"
+)
+
+func main() {
+	limit := flag.Int("limit", 0, "maximum number of messages to replay (0 replays all)")
+	flag.Parse()
+
+	// Load configuration
+	cfg := config.Load()
+
+	replayed, err := messaging.ReplayDeadLetters(context.Background(), cfg.RabbitMQ, *limit)
+	if err != nil {
+		log.Fatalf("Failed to replay dead letters after %d message(s): %v", replayed, err)
+	}
+
+	log.Printf("Replayed %d message(s) from %s", replayed, cfg.RabbitMQ.DeadLetterQueue)
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/messaging"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/repository"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/service"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/proto/analytics.v1"
+	"github.com/spbu-ds-practicum-2025/example-project/services/common/health"
 )
 
 func main() {
@@ -23,6 +25,14 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	// Run the migrate subcommand instead of the server, e.g. "analytics-service migrate up"
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), cfg.ClickHouse, os.Args[2:]); err != nil {
+			log.Fatalf("Migrate: %v", err)
+		}
+		return
+	}
+
 	log.Printf("Configuration loaded: ClickHouse=%s:%s, RabbitMQ=%s, gRPC=:%s",
 		cfg.ClickHouse.Host, cfg.ClickHouse.Database, cfg.RabbitMQ.Exchange, cfg.GRPCPort)
 
@@ -34,6 +44,9 @@ func main() {
 	defer clickhouseClient.Close()
 	log.Println("Successfully connected to ClickHouse")
 
+	// Apply pending migrations if enabled
+	migrateOnStart(context.Background(), cfg, clickhouseClient)
+
 	// Initialize repository
 	repo := repository.NewOperationRepository(clickhouseClient)
 	log.Println("Repository initialized")
@@ -42,6 +55,28 @@ func main() {
 	analyticsService := service.NewAnalyticsServiceWithRepo(repo)
 	log.Println("Analytics service initialized")
 
+	// Create RabbitMQ consumer
+	consumer, err := messaging.NewRabbitMQConsumer(cfg.RabbitMQ, repo)
+	if err != nil {
+		log.Fatalf("Failed to create RabbitMQ consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	// Create health checker reporting the state of the ClickHouse and RabbitMQ connections.
+	// RabbitMQ is optional, as queries are served from ClickHouse while events wait in the queue.
+	healthChecker := health.NewChecker(
+		[]string{pb.AnalyticsService_ServiceDesc.ServiceName},
+		[]health.Dependency{
+			{Name: "clickhouse", Check: clickhouseClient.Ping},
+			{
+				Name:     "rabbitmq",
+				Check:    func(ctx context.Context) error { return consumer.CheckConnection() },
+				Optional: true,
+			},
+		},
+		cfg.Health.CheckTimeout,
+	)
+
 	// Create wait group for graceful shutdown
 	var wg sync.WaitGroup
 
@@ -49,11 +84,18 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Start health checks
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		healthChecker.Run(ctx, cfg.Health.CheckInterval)
+	}()
+
 	// Start gRPC server
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := startGRPCServer(cfg, analyticsService); err != nil {
+		if err := startGRPCServer(ctx, cfg, analyticsService, healthChecker); err != nil {
 			log.Printf("gRPC server error: %v", err)
 			cancel() // Signal shutdown on error
 		}
@@ -63,7 +105,7 @@ func main() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := startRabbitMQConsumer(ctx, cfg, repo); err != nil {
+		if err := startRabbitMQConsumer(ctx, consumer); err != nil {
 			log.Printf("RabbitMQ consumer error: %v", err)
 			cancel() // Signal shutdown on error
 		}
@@ -90,8 +132,8 @@ func main() {
 	log.Println("Analytics Service stopped gracefully")
 }
 
-// startGRPCServer starts the gRPC server
-func startGRPCServer(cfg *config.Config, analyticsService *service.AnalyticsService) error {
+// startGRPCServer starts the gRPC server and stops it gracefully when the context is cancelled
+func startGRPCServer(ctx context.Context, cfg *config.Config, analyticsService *service.AnalyticsService, healthChecker *health.Checker) error {
 	// Create TCP listener
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
 	if err != nil {
@@ -104,25 +146,34 @@ func startGRPCServer(cfg *config.Config, analyticsService *service.AnalyticsServ
 	// Register analytics service
 	grpcserver.RegisterAnalyticsServer(grpcServer, analyticsService)
 
+	// Register health service
+	healthChecker.Register(grpcServer)
+
 	log.Printf("gRPC server listening on port %s", cfg.GRPCPort)
 
-	// Start serving (blocking)
-	if err := grpcServer.Serve(listener); err != nil {
+	// Start serving
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
 		return fmt.Errorf("gRPC server failed: %w", err)
+	case <-ctx.Done():
 	}
 
+	// Report NOT_SERVING first, so that clients stop sending new calls while in-flight ones finish
+	log.Println("Stopping gRPC server...")
+	healthChecker.Shutdown()
+	grpcServer.GracefulStop()
+	log.Println("gRPC server stopped")
+
 	return nil
 }
 
-// startRabbitMQConsumer starts the RabbitMQ consumer
-func startRabbitMQConsumer(ctx context.Context, cfg *config.Config, repo *repository.OperationRepository) error {
-	// Create consumer
-	consumer, err := messaging.NewRabbitMQConsumer(cfg.RabbitMQ, repo)
-	if err != nil {
-		return fmt.Errorf("failed to create RabbitMQ consumer: %w", err)
-	}
-	defer consumer.Close()
-
+// startRabbitMQConsumer runs the RabbitMQ consumer
+func startRabbitMQConsumer(ctx context.Context, consumer *messaging.RabbitMQConsumer) error {
 	log.Println("RabbitMQ consumer starting...")
 
 	// Start consuming (blocking until context is cancelled)
@@ -44,6 +44,11 @@ func (c *ClickHouseClient) Conn() driver.Conn {
 	return c.conn
 }
 
+// Ping checks that ClickHouse is reachable
+func (c *ClickHouseClient) Ping(ctx context.Context) error {
+	return c.conn.Ping(ctx)
+}
+
 // Close closes the ClickHouse connection
 func (c *ClickHouseClient) Close() error {
 	if c.conn != nil {
@@ -0,0 +1,59 @@
+package messaging
+
+import (
+	"context"
+	"log"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+)
+
+// operationBatch accumulates decoded messages until their operations are inserted
+type operationBatch struct {
+	deliveries []amqp.Delivery
+	operations []*models.Operation
+}
+
+// add adds the message and its operations to the batch
+func (b *operationBatch) add(msg amqp.Delivery, operations []*models.Operation) {
+	b.deliveries = append(b.deliveries, msg)
+	b.operations = append(b.operations, operations...)
+}
+
+// len returns the number of messages in the batch
+func (b *operationBatch) len() int {
+	return len(b.deliveries)
+}
+
+// reset empties the batch
+func (b *operationBatch) reset() {
+	b.deliveries = nil
+	b.operations = nil
+}
+
+// flush inserts the operations of the batch in a single block and acknowledges its messages.
+// If the insert fails, each message is retried or dead-lettered on its own.
+func (c *RabbitMQConsumer) flush(ctx context.Context, batch *operationBatch) {
+	if batch.len() == 0 {
+		return
+	}
+	defer batch.reset()
+
+	if err := c.repo.InsertOperations(ctx, batch.operations); err != nil {
+		log.Printf("Failed to insert batch of %d messages: %v", batch.len(), err)
+		for _, msg := range batch.deliveries {
+			c.handleFailure(ctx, msg, err)
+		}
+		return
+	}
+
+	// Acknowledge all messages of the batch at once: every earlier delivery
+	// is either in the batch or has already been acknowledged or rejected
+	last := batch.deliveries[batch.len()-1]
+	if err := last.Ack(true); err != nil {
+		log.Printf("Failed to acknowledge batch of %d messages: %v", batch.len(), err)
+		return
+	}
+
+	log.Printf("Inserted %d operations from %d messages", len(batch.operations), batch.len())
+}
@@ -0,0 +1,152 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/config"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+)
+
+// fakeAcknowledger records acknowledgements of deliveries
+type fakeAcknowledger struct {
+	mu   sync.Mutex
+	acks []ack
+}
+
+type ack struct {
+	tag      uint64
+	multiple bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.acks = append(a.acks, ack{tag: tag, multiple: multiple})
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error { return nil }
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error { return nil }
+
+// fakeOperationWriter records inserted batches
+type fakeOperationWriter struct {
+	mu       sync.Mutex
+	batches  [][]*models.Operation
+	inserted chan struct{}
+}
+
+func (w *fakeOperationWriter) InsertOperations(ctx context.Context, ops []*models.Operation) error {
+	w.mu.Lock()
+	w.batches = append(w.batches, ops)
+	w.mu.Unlock()
+	w.inserted <- struct{}{}
+	return nil
+}
+
+func transferDelivery(tag uint64, acknowledger amqp.Acknowledger) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: acknowledger,
+		DeliveryTag:  tag,
+		Body: []byte(`{"eventType":"transfer.completed","operationId":"op","senderId":"a","recipientId":"b",` +
+			`"amount":{"value":"1.00","currencyCode":"RUB"},"status":"SUCCESS","timestamp":"2025-10-12T14:48:00Z"}`),
+	}
+}
+
+// startConsume runs consume in the background and returns the message channel and a stop function
+// that cancels the consumer and waits for it to return
+func startConsume(t *testing.T, consumer *RabbitMQConsumer) (chan<- amqp.Delivery, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs := make(chan amqp.Delivery)
+	done := make(chan error)
+
+	go func() {
+		done <- consumer.consume(ctx, msgs, nil)
+	}()
+
+	return msgs, func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestConsume_FlushesOnBatchSize(t *testing.T) {
+	writer := &fakeOperationWriter{inserted: make(chan struct{}, 10)}
+	acknowledger := &fakeAcknowledger{}
+	consumer := &RabbitMQConsumer{
+		config: config.RabbitMQConfig{BatchSize: 2, FlushInterval: time.Hour},
+		repo:   writer,
+	}
+
+	msgs, stop := startConsume(t, consumer)
+	for tag := uint64(1); tag <= 3; tag++ {
+		msgs <- transferDelivery(tag, acknowledger)
+	}
+	stop()
+
+	if len(writer.batches) != 1 {
+		t.Fatalf("expected 1 batch, got %d", len(writer.batches))
+	}
+	if len(writer.batches[0]) != 4 {
+		t.Errorf("expected 4 operations (2 per transfer), got %d", len(writer.batches[0]))
+	}
+
+	// The first two messages are acknowledged together; the third waits for the next batch
+	if len(acknowledger.acks) != 1 || acknowledger.acks[0] != (ack{tag: 2, multiple: true}) {
+		t.Errorf("expected a single multiple ack of tag 2, got %v", acknowledger.acks)
+	}
+}
+
+func TestConsume_FlushesOnInterval(t *testing.T) {
+	writer := &fakeOperationWriter{inserted: make(chan struct{}, 10)}
+	acknowledger := &fakeAcknowledger{}
+	consumer := &RabbitMQConsumer{
+		config: config.RabbitMQConfig{BatchSize: 100, FlushInterval: 10 * time.Millisecond},
+		repo:   writer,
+	}
+
+	msgs, stop := startConsume(t, consumer)
+	msgs <- transferDelivery(1, acknowledger)
+
+	select {
+	case <-writer.inserted:
+	case <-time.After(time.Second):
+		t.Fatal("expected incomplete batch to be inserted after the flush interval")
+	}
+	stop()
+
+	acknowledger.mu.Lock()
+	defer acknowledger.mu.Unlock()
+	if len(acknowledger.acks) != 1 || acknowledger.acks[0] != (ack{tag: 1, multiple: true}) {
+		t.Errorf("expected ack of tag 1, got %v", acknowledger.acks)
+	}
+}
+
+func TestConsume_DoesNotAckBeforeInsert(t *testing.T) {
+	writer := &fakeOperationWriter{inserted: make(chan struct{}, 10)}
+	acknowledger := &fakeAcknowledger{}
+	consumer := &RabbitMQConsumer{
+		config: config.RabbitMQConfig{BatchSize: 10, FlushInterval: time.Hour},
+		repo:   writer,
+	}
+
+	msgs, stop := startConsume(t, consumer)
+	for tag := uint64(1); tag <= 5; tag++ {
+		msgs <- transferDelivery(tag, acknowledger)
+	}
+	stop()
+
+	// Pending messages are left unacknowledged, so the broker redelivers them
+	if len(writer.batches) != 0 {
+		t.Errorf("expected no inserts, got %d", len(writer.batches))
+	}
+	if len(acknowledger.acks) != 0 {
+		t.Errorf("expected no acks, got %v", acknowledger.acks)
+	}
+}
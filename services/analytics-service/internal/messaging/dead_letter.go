@@ -0,0 +1,233 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/config"
+)
+
+// Headers set on retried and dead-lettered messages
+const (
+	headerRetryCount         = "x-retry-count"
+	headerFailureReason      = "x-failure-reason"
+	headerFailedAt           = "x-failed-at"
+	headerOriginalExchange   = "x-original-exchange"
+	headerOriginalRoutingKey = "x-original-routing-key"
+)
+
+// permanentError marks an error that redelivering the message cannot fix,
+// e.g. a malformed body or an event that fails validation
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent marks err as permanent, so the message is dead-lettered without retries
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// isPermanent reports whether err is marked as permanent
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// failureRoute decides what to do with a message that failed with err after the given number of retries.
+// Returns true with the failure reason if the message must be dead-lettered, false if it must be retried.
+func failureRoute(err error, retries, maxRetries int) (bool, string) {
+	switch {
+	case isPermanent(err):
+		return true, err.Error()
+	case retries >= maxRetries:
+		return true, fmt.Sprintf("retries exhausted after %d attempts: %v", retries+1, err)
+	default:
+		return false, err.Error()
+	}
+}
+
+// handleFailure retries or dead-letters a message that failed with err.
+// The message is acknowledged only once it is stored in the retry or dead letter queue;
+// if that fails, it is requeued so it is never lost.
+func (c *RabbitMQConsumer) handleFailure(ctx context.Context, msg amqp.Delivery, err error) {
+	retries := retryCount(msg.Headers)
+
+	var publishErr error
+	if deadLetter, reason := failureRoute(err, retries, c.config.MaxRetries); deadLetter {
+		log.Printf("Dead-lettering message to %s: %s", c.config.DeadLetterQueue, reason)
+		publishing := republish(msg, failureHeaders(msg, reason, retries))
+		publishErr = publishConfirmed(ctx, c.channel, c.config.DeadLetterExchange, c.config.Queue, publishing)
+	} else {
+		log.Printf("Retrying message in %s (retry %d of %d): %s", c.config.RetryDelay, retries+1, c.config.MaxRetries, reason)
+		publishing := republish(msg, failureHeaders(msg, reason, retries+1))
+		publishing.Expiration = fmt.Sprintf("%d", c.config.RetryDelay.Milliseconds())
+		publishErr = publishConfirmed(ctx, c.channel, "", c.config.RetryQueue, publishing)
+	}
+
+	if publishErr != nil {
+		log.Printf("Failed to route failed message, requeueing: %v", publishErr)
+		msg.Nack(false, true)
+		return
+	}
+	msg.Ack(false)
+}
+
+// retryCount returns the number of times the message has been retried
+func retryCount(headers amqp.Table) int {
+	switch v := headers[headerRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// failureHeaders returns the message headers with the failure reason and retry count set.
+// The exchange and routing key the message was originally published with are kept
+// across retries, so the message can be replayed to them from the dead letter queue.
+func failureHeaders(msg amqp.Delivery, reason string, retries int) amqp.Table {
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	if _, ok := headers[headerOriginalRoutingKey]; !ok {
+		headers[headerOriginalExchange] = msg.Exchange
+		headers[headerOriginalRoutingKey] = msg.RoutingKey
+	}
+	headers[headerFailureReason] = reason
+	headers[headerFailedAt] = time.Now().UTC().Format(time.RFC3339)
+	headers[headerRetryCount] = int32(retries)
+	return headers
+}
+
+// replayHeaders returns the message headers without failure headers,
+// so a replayed message is processed as a new one
+func replayHeaders(headers amqp.Table) amqp.Table {
+	replayed := amqp.Table{}
+	for k, v := range headers {
+		switch k {
+		case headerRetryCount, headerFailureReason, headerFailedAt,
+			headerOriginalExchange, headerOriginalRoutingKey, "x-death":
+			continue
+		}
+		replayed[k] = v
+	}
+	return replayed
+}
+
+// replayTarget returns the exchange and routing key the dead-lettered message was originally published with.
+// Messages without them are published directly to the events queue.
+func replayTarget(headers amqp.Table, cfg config.RabbitMQConfig) (string, string) {
+	exchange, _ := headers[headerOriginalExchange].(string)
+	routingKey, ok := headers[headerOriginalRoutingKey].(string)
+	if !ok || routingKey == "" {
+		return "", cfg.Queue
+	}
+	return exchange, routingKey
+}
+
+// republish returns a persistent copy of the delivered message with the given headers
+func republish(msg amqp.Delivery, headers amqp.Table) amqp.Publishing {
+	return amqp.Publishing{
+		Headers:         headers,
+		ContentType:     msg.ContentType,
+		ContentEncoding: msg.ContentEncoding,
+		DeliveryMode:    amqp.Persistent,
+		CorrelationId:   msg.CorrelationId,
+		MessageId:       msg.MessageId,
+		Timestamp:       msg.Timestamp,
+		Type:            msg.Type,
+		Body:            msg.Body,
+	}
+}
+
+// publishConfirmed publishes the message and waits for the broker to confirm it.
+// The channel must be in confirm mode.
+func publishConfirmed(ctx context.Context, channel *amqp.Channel, exchange, routingKey string, msg amqp.Publishing) error {
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, msg)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for publish confirmation: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("message was rejected by the broker")
+	}
+
+	return nil
+}
+
+// ReplayDeadLetters moves up to limit messages from the dead letter queue back to the exchange
+// and routing key they were originally published with. A limit of 0 replays all messages
+// that are in the queue when the replay starts. Failure headers are removed, so replayed
+// messages get the full number of retries again. Returns the number of replayed messages.
+func ReplayDeadLetters(ctx context.Context, cfg config.RabbitMQConfig, limit int) (int, error) {
+	conn, err := amqp.Dial(cfg.URL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer channel.Close()
+
+	if err := declareTopology(channel, cfg); err != nil {
+		return 0, err
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		return 0, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	// Bound the replay by the current queue length, so messages that fail again
+	// and return to the dead letter queue are not replayed in a loop
+	queue, err := channel.QueueDeclarePassive(cfg.DeadLetterQueue, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect dead letter queue: %w", err)
+	}
+	if limit <= 0 || limit > queue.Messages {
+		limit = queue.Messages
+	}
+
+	replayed := 0
+	for replayed < limit {
+		msg, ok, err := channel.Get(cfg.DeadLetterQueue, false)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to get dead-lettered message: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		exchange, routingKey := replayTarget(msg.Headers, cfg)
+		if err := publishConfirmed(ctx, channel, exchange, routingKey, republish(msg, replayHeaders(msg.Headers))); err != nil {
+			msg.Nack(false, true)
+			return replayed, fmt.Errorf("failed to replay message: %w", err)
+		}
+
+		if err := msg.Ack(false); err != nil {
+			return replayed, fmt.Errorf("failed to acknowledge dead-lettered message: %w", err)
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
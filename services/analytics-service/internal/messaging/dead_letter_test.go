@@ -0,0 +1,181 @@
+package messaging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/config"
+)
+
+func TestDecodeMessage_PermanentErrors(t *testing.T) {
+	// The repository is never reached for these messages
+	consumer := &RabbitMQConsumer{}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed JSON", `{"eventType":`},
+		{"unsupported event type", `{"eventType":"account.created"}`},
+		{"transfer without operation ID", `{"eventType":"transfer.completed","senderId":"a","recipientId":"b","amount":{"value":"1.00","currencyCode":"RUB"},"status":"SUCCESS","timestamp":"2025-10-12T14:48:00Z"}`},
+		{"transfer with FAILED status", `{"eventType":"transfer.completed","operationId":"op","senderId":"a","recipientId":"b","amount":{"value":"1.00","currencyCode":"RUB"},"status":"FAILED","timestamp":"2025-10-12T14:48:00Z"}`},
+		{"transfer with invalid timestamp", `{"eventType":"transfer.completed","operationId":"op","senderId":"a","recipientId":"b","amount":{"value":"1.00","currencyCode":"RUB"},"status":"SUCCESS","timestamp":"yesterday"}`},
+		{"top-up with invalid amount type", `{"eventType":"topup.completed","operationId":"op","accountId":"a","amount":{"value":1}}`},
+		{"top-up without account ID", `{"eventType":"topup.completed","operationId":"op","amount":{"value":"1.00","currencyCode":"RUB"},"status":"SUCCESS","timestamp":"2025-10-12T14:48:00Z"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := consumer.decodeMessage(amqp.Delivery{Body: []byte(tt.body)})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !isPermanent(err) {
+				t.Errorf("expected permanent error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFailureRoute(t *testing.T) {
+	transient := errors.New("clickhouse unavailable")
+
+	tests := []struct {
+		name             string
+		err              error
+		retries          int
+		expectDeadLetter bool
+		expectReason     string
+	}{
+		{"transient error is retried", transient, 0, false, "clickhouse unavailable"},
+		{"transient error is retried until the limit", transient, 2, false, "clickhouse unavailable"},
+		{"transient error is dead-lettered after the limit", transient, 3, true, "retries exhausted after 4 attempts: clickhouse unavailable"},
+		{"permanent error is dead-lettered at once", permanent(errors.New("invalid event")), 0, true, "invalid event"},
+		{"wrapped permanent error is dead-lettered", errors.Join(permanent(errors.New("invalid event"))), 0, true, "invalid event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadLetter, reason := failureRoute(tt.err, tt.retries, 3)
+			if deadLetter != tt.expectDeadLetter {
+				t.Errorf("expected dead letter %v, got %v", tt.expectDeadLetter, deadLetter)
+			}
+			if reason != tt.expectReason {
+				t.Errorf("expected reason %q, got %q", tt.expectReason, reason)
+			}
+		})
+	}
+}
+
+func TestRetryCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  amqp.Table
+		expected int
+	}{
+		{"no headers", nil, 0},
+		{"int32", amqp.Table{headerRetryCount: int32(2)}, 2},
+		{"int64", amqp.Table{headerRetryCount: int64(3)}, 3},
+		{"unexpected type", amqp.Table{headerRetryCount: "4"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryCount(tt.headers); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFailureHeaders(t *testing.T) {
+	msg := amqp.Delivery{
+		Exchange:   "bank.operations",
+		RoutingKey: "bank.operations.transfer.completed",
+		Headers:    amqp.Table{"trace-id": "abc"},
+	}
+
+	headers := failureHeaders(msg, "invalid event", 0)
+
+	if headers[headerFailureReason] != "invalid event" {
+		t.Errorf("expected failure reason, got %v", headers[headerFailureReason])
+	}
+	if headers[headerRetryCount] != int32(0) {
+		t.Errorf("expected retry count 0, got %v", headers[headerRetryCount])
+	}
+	if headers[headerOriginalExchange] != "bank.operations" || headers[headerOriginalRoutingKey] != "bank.operations.transfer.completed" {
+		t.Errorf("expected original exchange and routing key, got %v", headers)
+	}
+	if headers["trace-id"] != "abc" {
+		t.Errorf("expected existing headers to be kept, got %v", headers)
+	}
+	if _, ok := msg.Headers[headerFailureReason]; ok {
+		t.Error("expected delivery headers to be left unchanged")
+	}
+
+	// A retried message arrives from the retry queue through the default exchange
+	retried := amqp.Delivery{
+		Exchange:   "",
+		RoutingKey: "analytics.transfer.completed",
+		Headers:    headers,
+	}
+
+	headers = failureHeaders(retried, "clickhouse unavailable", 1)
+
+	if headers[headerOriginalExchange] != "bank.operations" || headers[headerOriginalRoutingKey] != "bank.operations.transfer.completed" {
+		t.Errorf("expected original exchange and routing key to be kept across retries, got %v", headers)
+	}
+	if retryCount(headers) != 1 {
+		t.Errorf("expected retry count 1, got %d", retryCount(headers))
+	}
+}
+
+func TestReplayTargetAndHeaders(t *testing.T) {
+	cfg := config.RabbitMQConfig{Queue: "analytics.transfer.completed"}
+
+	msg := amqp.Delivery{
+		Exchange:   "bank.operations",
+		RoutingKey: "bank.operations.topup.completed",
+		Headers:    amqp.Table{"trace-id": "abc"},
+	}
+	headers := failureHeaders(msg, "invalid event", 2)
+	headers["x-death"] = []interface{}{}
+
+	exchange, routingKey := replayTarget(headers, cfg)
+	if exchange != "bank.operations" || routingKey != "bank.operations.topup.completed" {
+		t.Errorf("expected original target, got %q %q", exchange, routingKey)
+	}
+
+	replayed := replayHeaders(headers)
+	for k := range replayed {
+		if k != "trace-id" {
+			t.Errorf("expected header %s to be removed on replay", k)
+		}
+	}
+	if replayed["trace-id"] != "abc" {
+		t.Errorf("expected trace-id to be kept, got %v", replayed)
+	}
+
+	// Messages without the original target go straight to the events queue
+	exchange, routingKey = replayTarget(amqp.Table{}, cfg)
+	if exchange != "" || routingKey != cfg.Queue {
+		t.Errorf("expected default exchange and events queue, got %q %q", exchange, routingKey)
+	}
+}
+
+func TestPermanentError(t *testing.T) {
+	cause := errors.New("cause")
+	err := permanent(cause)
+
+	if !errors.Is(err, cause) {
+		t.Error("expected permanent error to wrap its cause")
+	}
+	if !strings.Contains(err.Error(), "cause") {
+		t.Errorf("expected message of the cause, got %q", err.Error())
+	}
+	if isPermanent(cause) {
+		t.Error("expected plain error not to be permanent")
+	}
+}
@@ -3,41 +3,103 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/config"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
-	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/repository"
 )
 
-// RabbitMQConsumer consumes transfer events from RabbitMQ
+// OperationWriter stores the operations decoded from events
+type OperationWriter interface {
+	InsertOperations(ctx context.Context, ops []*models.Operation) error
+}
+
+// RabbitMQConsumer consumes bank operation events (transfers and top-ups) from RabbitMQ.
+// If the connection is lost, it reconnects and resumes consuming.
 type RabbitMQConsumer struct {
+	mu      sync.Mutex // Guards conn and channel, which are replaced on reconnect
 	conn    *amqp.Connection
 	channel *amqp.Channel
+	closed  <-chan *amqp.Error // Receives an error when the channel or its connection is closed
 	config  config.RabbitMQConfig
-	repo    *repository.OperationRepository
+	repo    OperationWriter
 }
 
 // NewRabbitMQConsumer creates a new RabbitMQ consumer
-func NewRabbitMQConsumer(cfg config.RabbitMQConfig, repo *repository.OperationRepository) (*RabbitMQConsumer, error) {
+func NewRabbitMQConsumer(cfg config.RabbitMQConfig, repo OperationWriter) (*RabbitMQConsumer, error) {
+	c := &RabbitMQConsumer{
+		config: cfg,
+		repo:   repo,
+	}
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("RabbitMQ consumer initialized: exchange=%s, queue=%s, routing_keys=%s,%s, dead_letter_queue=%s",
+		cfg.Exchange, cfg.Queue, cfg.RoutingKey, cfg.TopUpRoutingKey, cfg.DeadLetterQueue)
+
+	return c, nil
+}
+
+// connect dials RabbitMQ, opens a channel and declares the topology,
+// replacing the previous connection if any
+func (c *RabbitMQConsumer) connect() error {
 	// Connect to RabbitMQ
-	conn, err := amqp.Dial(cfg.URL)
+	conn, err := amqp.Dial(c.config.URL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
 
 	// Open channel
 	channel, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return fmt.Errorf("failed to open channel: %w", err)
 	}
 
+	// Declare exchanges and queues
+	if err := declareTopology(channel, c.config); err != nil {
+		channel.Close()
+		conn.Close()
+		return err
+	}
+
+	// Enable publisher confirms, so retried and dead-lettered messages are acked only once stored
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		conn.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	// Watch for the channel being closed, e.g. by a broker restart; closing the connection
+	// closes the channel too. The notification channel is buffered, so the library never blocks on it.
+	closed := channel.NotifyClose(make(chan *amqp.Error, 1))
+
+	c.mu.Lock()
+	previous := c.conn
+	c.conn = conn
+	c.channel = channel
+	c.closed = closed
+	c.mu.Unlock()
+
+	if previous != nil && !previous.IsClosed() {
+		previous.Close()
+	}
+
+	return nil
+}
+
+// declareTopology declares the events exchange and queue with their bindings,
+// and the retry and dead letter queues used for failed messages
+func declareTopology(channel *amqp.Channel, cfg config.RabbitMQConfig) error {
 	// Declare exchange (topic exchange for routing)
-	err = channel.ExchangeDeclare(
+	err := channel.ExchangeDeclare(
 		cfg.Exchange, // name
 		"topic",      // type
 		true,         // durable
@@ -47,9 +109,7 @@ func NewRabbitMQConsumer(cfg config.RabbitMQConfig, repo *repository.OperationRe
 		nil,          // arguments
 	)
 	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+		return fmt.Errorf("failed to declare exchange: %w", err)
 	}
 
 	// Declare queue
@@ -62,38 +122,63 @@ func NewRabbitMQConsumer(cfg config.RabbitMQConfig, repo *repository.OperationRe
 		nil,       // arguments
 	)
 	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to declare queue: %w", err)
+		return fmt.Errorf("failed to declare queue: %w", err)
 	}
 
-	// Bind queue to exchange with routing key
-	err = channel.QueueBind(
-		queue.Name,     // queue name
-		cfg.RoutingKey, // routing key
-		cfg.Exchange,   // exchange
-		false,
-		nil,
-	)
+	// Bind queue to exchange with the routing key of each consumed event type
+	for _, routingKey := range []string{cfg.RoutingKey, cfg.TopUpRoutingKey} {
+		if routingKey == "" {
+			continue
+		}
+		err = channel.QueueBind(
+			queue.Name,   // queue name
+			routingKey,   // routing key
+			cfg.Exchange, // exchange
+			false,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to bind queue to %s: %w", routingKey, err)
+		}
+	}
+
+	// Declare dead letter exchange and queue; dead-lettered messages are routed by the source queue name
+	err = channel.ExchangeDeclare(cfg.DeadLetterExchange, "direct", true, false, false, false, nil)
 	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to bind queue: %w", err)
+		return fmt.Errorf("failed to declare dead letter exchange: %w", err)
+	}
+	if _, err := channel.QueueDeclare(cfg.DeadLetterQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter queue: %w", err)
+	}
+	if err := channel.QueueBind(cfg.DeadLetterQueue, cfg.Queue, cfg.DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead letter queue: %w", err)
 	}
 
-	log.Printf("RabbitMQ consumer initialized: exchange=%s, queue=%s, routing_key=%s",
-		cfg.Exchange, cfg.Queue, cfg.RoutingKey)
+	// Declare retry queue; expired messages are dead-lettered back to the events queue
+	_, err = channel.QueueDeclare(cfg.RetryQueue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": cfg.Queue,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to declare retry queue: %w", err)
+	}
 
-	return &RabbitMQConsumer{
-		conn:    conn,
-		channel: channel,
-		config:  cfg,
-		repo:    repo,
-	}, nil
+	return nil
 }
 
-// Start begins consuming messages from the queue
+// Start begins consuming messages from the queue and blocks until the context is cancelled.
+// When the connection is lost, it reconnects with exponential backoff and resumes consuming.
 func (c *RabbitMQConsumer) Start(ctx context.Context) error {
+	return supervise(ctx, c.config.ReconnectMinDelay, c.config.ReconnectMaxDelay, c.run, c.connect)
+}
+
+// run consumes messages on the current channel until the context is cancelled or the channel is closed
+func (c *RabbitMQConsumer) run(ctx context.Context) error {
+	// Limit unacknowledged deliveries; messages stay unacknowledged until their batch is inserted
+	if err := c.channel.Qos(c.config.PrefetchCount, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch count: %w", err)
+	}
+
 	// Register consumer
 	msgs, err := c.channel.Consume(
 		c.config.Queue, // queue
@@ -108,39 +193,86 @@ func (c *RabbitMQConsumer) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to register consumer: %w", err)
 	}
 
-	log.Printf("RabbitMQ consumer started, waiting for messages on queue: %s", c.config.Queue)
+	log.Printf("RabbitMQ consumer started, waiting for messages on queue: %s (prefetch=%d, batch_size=%d, flush_interval=%s)",
+		c.config.Queue, c.config.PrefetchCount, c.config.BatchSize, c.config.FlushInterval)
+
+	return c.consume(ctx, msgs, c.closed)
+}
+
+// consume decodes delivered messages into operations and inserts them in batches.
+// A batch is inserted once it holds BatchSize messages or FlushInterval has passed,
+// and its messages are acknowledged only after the insert succeeds.
+// Returns an error when the channel is closed; messages of the pending batch are then
+// redelivered by the broker.
+func (c *RabbitMQConsumer) consume(ctx context.Context, msgs <-chan amqp.Delivery, closed <-chan *amqp.Error) error {
+	batch := &operationBatch{}
+	batchSize := max(c.config.BatchSize, 1)
+
+	flushInterval := c.config.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
 
-	// Process messages
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Context cancelled, stopping RabbitMQ consumer")
+			// Unacknowledged messages of the pending batch are redelivered once the channel is closed
+			log.Printf("Context cancelled, stopping RabbitMQ consumer (%d pending messages left unacknowledged)", batch.len())
 			return nil
 
+		case err := <-closed:
+			return fmt.Errorf("channel closed: %v", err)
+
 		case msg, ok := <-msgs:
 			if !ok {
 				return fmt.Errorf("message channel closed")
 			}
 
-			// Handle message
-			if err := c.handleMessage(ctx, msg); err != nil {
+			// Decode message
+			operations, err := c.decodeMessage(msg)
+			if err != nil {
 				log.Printf("Error handling message: %v", err)
-				// Negative acknowledgement with requeue on error
-				msg.Nack(false, true)
-			} else {
-				// Acknowledge successful processing
-				msg.Ack(false)
+				// Retry or dead-letter the message depending on the error
+				c.handleFailure(ctx, msg, err)
+				continue
 			}
+
+			batch.add(msg, operations)
+			if batch.len() >= batchSize {
+				c.flush(ctx, batch)
+			}
+
+		case <-ticker.C:
+			c.flush(ctx, batch)
 		}
 	}
 }
 
-// handleMessage processes a single transfer event message
-func (c *RabbitMQConsumer) handleMessage(ctx context.Context, msg amqp.Delivery) error {
+// decodeMessage decodes a single event message into operations by its event type
+func (c *RabbitMQConsumer) decodeMessage(msg amqp.Delivery) ([]*models.Operation, error) {
+	var envelope models.EventEnvelope
+	if err := json.Unmarshal(msg.Body, &envelope); err != nil {
+		return nil, permanent(fmt.Errorf("failed to unmarshal event: %w", err))
+	}
+
+	switch envelope.EventType {
+	case models.EventTypeTransferCompleted:
+		return c.decodeTransferEvent(msg)
+	case models.EventTypeTopUpCompleted:
+		return c.decodeTopUpEvent(msg)
+	default:
+		return nil, permanent(fmt.Errorf("unsupported event type: %q", envelope.EventType))
+	}
+}
+
+// decodeTransferEvent decodes a transfer event message into the operations of the sender and the recipient
+func (c *RabbitMQConsumer) decodeTransferEvent(msg amqp.Delivery) ([]*models.Operation, error) {
 	// Deserialize event from JSON
 	var event models.TransferCompletedEvent
 	if err := json.Unmarshal(msg.Body, &event); err != nil {
-		return fmt.Errorf("failed to unmarshal event: %w", err)
+		return nil, permanent(fmt.Errorf("failed to unmarshal event: %w", err))
 	}
 
 	log.Printf("Received transfer event: eventId=%s, operationId=%s, sender=%s, recipient=%s",
@@ -148,13 +280,13 @@ func (c *RabbitMQConsumer) handleMessage(ctx context.Context, msg amqp.Delivery)
 
 	// Validate event
 	if err := c.validateEvent(&event); err != nil {
-		return fmt.Errorf("invalid event: %w", err)
+		return nil, permanent(fmt.Errorf("invalid event: %w", err))
 	}
 
 	// Parse timestamp from ISO 8601
 	timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
 	if err != nil {
-		return fmt.Errorf("failed to parse timestamp: %w", err)
+		return nil, permanent(fmt.Errorf("failed to parse timestamp: %w", err))
 	}
 
 	// Create operation for sender (outgoing transfer)
@@ -185,19 +317,8 @@ func (c *RabbitMQConsumer) handleMessage(ctx context.Context, msg amqp.Delivery)
 		RecipientID: event.RecipientID,
 	}
 
-	// Insert sender operation
-	if err := c.repo.InsertOperation(ctx, senderOperation); err != nil {
-		return fmt.Errorf("failed to insert sender operation: %w", err)
-	}
-
-	// Insert recipient operation
-	if err := c.repo.InsertOperation(ctx, recipientOperation); err != nil {
-		return fmt.Errorf("failed to insert recipient operation: %w", err)
-	}
-
-	log.Printf("Successfully processed transfer event: operationId=%s", event.OperationID)
-
-	return nil
+	// Both operations are inserted in the same batch, so a failure never leaves only one side stored
+	return []*models.Operation{senderOperation, recipientOperation}, nil
 }
 
 // validateEvent validates the transfer event structure
@@ -227,14 +348,89 @@ func (c *RabbitMQConsumer) validateEvent(event *models.TransferCompletedEvent) e
 	return nil
 }
 
+// decodeTopUpEvent decodes a top-up event message into the operation of the account
+func (c *RabbitMQConsumer) decodeTopUpEvent(msg amqp.Delivery) ([]*models.Operation, error) {
+	// Deserialize event from JSON
+	var event models.TopUpCompletedEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		return nil, permanent(fmt.Errorf("failed to unmarshal event: %w", err))
+	}
+
+	log.Printf("Received top-up event: eventId=%s, operationId=%s, account=%s",
+		event.EventID, event.OperationID, event.AccountID)
+
+	// Validate event
+	if err := c.validateTopUpEvent(&event); err != nil {
+		return nil, permanent(fmt.Errorf("invalid event: %w", err))
+	}
+
+	// Parse timestamp from ISO 8601
+	timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
+	if err != nil {
+		return nil, permanent(fmt.Errorf("failed to parse timestamp: %w", err))
+	}
+
+	operation := &models.Operation{
+		ID:            event.OperationID,
+		AccountID:     event.AccountID,
+		OperationType: models.OperationTypeTopup,
+		Timestamp:     timestamp,
+		Amount: models.Amount{
+			Value:        event.Amount.Value,
+			CurrencyCode: event.Amount.CurrencyCode,
+		},
+	}
+
+	return []*models.Operation{operation}, nil
+}
+
+// validateTopUpEvent validates the top-up event structure
+func (c *RabbitMQConsumer) validateTopUpEvent(event *models.TopUpCompletedEvent) error {
+	if event.OperationID == "" {
+		return fmt.Errorf("operation ID is required")
+	}
+	if event.AccountID == "" {
+		return fmt.Errorf("account ID is required")
+	}
+	if event.Amount.Value == "" {
+		return fmt.Errorf("amount value is required")
+	}
+	if event.Amount.CurrencyCode == "" {
+		return fmt.Errorf("currency code is required")
+	}
+	if event.Timestamp == "" {
+		return fmt.Errorf("timestamp is required")
+	}
+	if event.Status != "SUCCESS" {
+		return fmt.Errorf("only SUCCESS status events are processed, got: %s", event.Status)
+	}
+
+	return nil
+}
+
+// CheckConnection returns an error unless the consumer has an open channel to RabbitMQ,
+// e.g. while it reconnects after the connection was lost
+func (c *RabbitMQConsumer) CheckConnection() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channel == nil || c.channel.IsClosed() {
+		return errors.New("not connected to RabbitMQ")
+	}
+	return nil
+}
+
 // Close closes the RabbitMQ connection and channel
 func (c *RabbitMQConsumer) Close() error {
-	if c.channel != nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channel != nil && !c.channel.IsClosed() {
 		if err := c.channel.Close(); err != nil {
 			log.Printf("Error closing channel: %v", err)
 		}
 	}
-	if c.conn != nil {
+	if c.conn != nil && !c.conn.IsClosed() {
 		return c.conn.Close()
 	}
 	return nil
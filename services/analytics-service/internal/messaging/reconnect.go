@@ -0,0 +1,52 @@
+package messaging
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// supervise calls run until the context is cancelled. Whenever run returns before that,
+// e.g. because the connection was lost, connect is retried with exponential backoff
+// from minDelay up to maxDelay, and run is called again once it succeeds.
+func supervise(
+	ctx context.Context,
+	minDelay, maxDelay time.Duration,
+	run func(ctx context.Context) error,
+	connect func() error,
+) error {
+	if minDelay <= 0 {
+		minDelay = time.Second
+	}
+	if maxDelay < minDelay {
+		maxDelay = minDelay
+	}
+
+	for {
+		err := run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Printf("RabbitMQ consumer interrupted: %v", err)
+
+		delay := minDelay
+		for attempt := 1; ; attempt++ {
+			log.Printf("Reconnecting to RabbitMQ in %s (attempt %d)", delay, attempt)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(delay):
+			}
+
+			if err := connect(); err != nil {
+				log.Printf("Failed to reconnect to RabbitMQ: %v", err)
+				delay = min(delay*2, maxDelay)
+				continue
+			}
+
+			log.Println("Reconnected to RabbitMQ")
+			break
+		}
+	}
+}
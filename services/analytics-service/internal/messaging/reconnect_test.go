@@ -0,0 +1,97 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/config"
+)
+
+func TestSupervise_ReconnectsWithBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	run := func(ctx context.Context) error {
+		runs++
+		if runs == 3 {
+			// Consume until shutdown after the second reconnect
+			cancel()
+			<-ctx.Done()
+			return nil
+		}
+		return errors.New("channel closed")
+	}
+
+	var connectTimes []time.Time
+	connect := func() error {
+		connectTimes = append(connectTimes, time.Now())
+		// The broker is back on the second attempt after each interruption
+		if len(connectTimes)%2 == 1 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	start := time.Now()
+	if err := supervise(ctx, 10*time.Millisecond, 15*time.Millisecond, run, connect); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+	if len(connectTimes) != 4 {
+		t.Fatalf("expected 4 connect attempts, got %d", len(connectTimes))
+	}
+
+	// Each interruption waits 10ms, then 15ms (doubled and capped) before the successful attempt
+	if elapsed := connectTimes[1].Sub(start); elapsed < 25*time.Millisecond {
+		t.Errorf("expected backoff of at least 25ms before reconnecting, got %s", elapsed)
+	}
+}
+
+func TestSupervise_StopsWhileWaitingToReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	run := func(ctx context.Context) error {
+		return errors.New("channel closed")
+	}
+	connect := func() error {
+		t.Error("expected no reconnect after the context is cancelled")
+		return nil
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- supervise(ctx, time.Hour, time.Hour, run, connect)
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected supervise to return after the context is cancelled")
+	}
+}
+
+func TestConsume_ReturnsWhenChannelIsClosed(t *testing.T) {
+	consumer := &RabbitMQConsumer{
+		config: config.RabbitMQConfig{BatchSize: 10, FlushInterval: time.Hour},
+		repo:   &fakeOperationWriter{inserted: make(chan struct{}, 10)},
+	}
+
+	closed := make(chan *amqp.Error, 1)
+	closed <- &amqp.Error{Code: amqp.ConnectionForced, Reason: "broker restart"}
+
+	err := consumer.consume(context.Background(), make(chan amqp.Delivery), closed)
+	if err == nil {
+		t.Fatal("expected error when the channel is closed, got nil")
+	}
+}
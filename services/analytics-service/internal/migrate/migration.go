@@ -0,0 +1,181 @@
+// Package migrate applies the SQL migrations of the analytics-service ClickHouse database.
+//
+// Applied versions are tracked in the schema_migrations table created by run-migrations.sh,
+// so databases migrated with the script are picked up as they are.
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fileNamePattern matches migration file names, e.g. 001_create_operations_table.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change with the SQL applying and reverting it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String returns the file name prefix of the migration, e.g. 001_create_operations_table.
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d_%s", m.Version, m.Name)
+}
+
+// Load reads the migrations in the root of fsys ordered by version.
+// Every migration must have both an up and a down file; other files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrations %03d_%s and %03d_%s have the same version", version, migration.Name, version, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s must have both up and down files", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// step is a migration applied (up) or reverted during a run
+type step struct {
+	migration Migration
+	up        bool
+}
+
+// plan returns the steps migrating the database from the current version to the target one.
+// Version 0 means that no migration is applied.
+func plan(migrations []Migration, current, target int) ([]step, error) {
+	currentIndex, err := indexOf(migrations, current)
+	if err != nil {
+		return nil, fmt.Errorf("database version: %w", err)
+	}
+	targetIndex, err := indexOf(migrations, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []step
+	for i := currentIndex + 1; i <= targetIndex; i++ {
+		steps = append(steps, step{migration: migrations[i], up: true})
+	}
+	for i := currentIndex; i > targetIndex; i-- {
+		steps = append(steps, step{migration: migrations[i], up: false})
+	}
+
+	return steps, nil
+}
+
+// indexOf returns the index of the migration with the version, or -1 for version 0.
+func indexOf(migrations []Migration, version int) (int, error) {
+	if version == 0 {
+		return -1, nil
+	}
+	for i, migration := range migrations {
+		if migration.Version == version {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown migration version %d", version)
+}
+
+// splitStatements splits SQL into the statements separated by semicolons.
+// ClickHouse runs one statement per query, unlike clickhouse-client --multiquery.
+// Semicolons in string literals, quoted identifiers and comments do not separate statements;
+// comments are dropped.
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Copy the quoted text up to the closing quote, skipping escaped characters
+			end := i + 1
+			for end < len(sql) && sql[end] != c {
+				if sql[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end, len(sql)-1)
+			current.WriteString(sql[i : end+1])
+			i = end
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end - 1
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
@@ -0,0 +1,173 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int
+		wantErr  bool
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"010_add_index.up.sql":      {Data: []byte("ALTER TABLE")},
+				"010_add_index.down.sql":    {Data: []byte("ALTER TABLE")},
+				"002_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"002_create_table.down.sql": {Data: []byte("DROP TABLE")},
+				"README.md":                 {Data: []byte("# Migrations")},
+			},
+			versions: []int{2, 10},
+		},
+		{
+			name: "missing up file",
+			files: fstest.MapFS{
+				"001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same version with different names",
+			files: fstest.MapFS{
+				"001_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+				"001_add_index.up.sql":      {Data: []byte("ALTER TABLE")},
+				"001_add_index.down.sql":    {Data: []byte("ALTER TABLE")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := Load(tt.files)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d migrations", len(loaded))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(loaded) != len(tt.versions) {
+				t.Fatalf("expected %d migrations, got %d", len(tt.versions), len(loaded))
+			}
+			for i, version := range tt.versions {
+				if loaded[i].Version != version {
+					t.Errorf("expected migration %d to have version %d, got %d", i, version, loaded[i].Version)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loaded) == 0 || loaded[0].String() != "001_create_operations_table" {
+		t.Errorf("expected embedded migrations starting with 001_create_operations_table, got %v", loaded)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	loaded := []Migration{{Version: 1, Name: "a"}, {Version: 2, Name: "b"}, {Version: 5, Name: "c"}}
+
+	type expectedStep struct {
+		version int
+		up      bool
+	}
+	tests := []struct {
+		name    string
+		current int
+		target  int
+		steps   []expectedStep
+		wantErr bool
+	}{
+		{name: "up from empty", current: 0, target: 5, steps: []expectedStep{{1, true}, {2, true}, {5, true}}},
+		{name: "up from version", current: 2, target: 5, steps: []expectedStep{{5, true}}},
+		{name: "down to empty", current: 2, target: 0, steps: []expectedStep{{2, false}, {1, false}}},
+		{name: "nothing to do", current: 5, target: 5},
+		{name: "unknown target", current: 2, target: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := plan(loaded, tt.current, tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d steps", len(steps))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(steps) != len(tt.steps) {
+				t.Fatalf("expected %d steps, got %d", len(tt.steps), len(steps))
+			}
+			for i, expected := range tt.steps {
+				if steps[i].migration.Version != expected.version || steps[i].up != expected.up {
+					t.Errorf("step %d: expected %+v, got migration %d up=%v", i, expected, steps[i].migration.Version, steps[i].up)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected []string
+	}{
+		{
+			name:     "single statement without semicolon",
+			sql:      "DROP TABLE IF EXISTS operations",
+			expected: []string{"DROP TABLE IF EXISTS operations"},
+		},
+		{
+			name: "statements and comments",
+			sql: `-- Recreate the table; copying rows
+CREATE TABLE t (id String);
+/* copy; then swap */ INSERT INTO t SELECT * FROM s;
+
+RENAME TABLE s TO s_old, t TO s;`,
+			expected: []string{
+				"CREATE TABLE t (id String)",
+				"INSERT INTO t SELECT * FROM s",
+				"RENAME TABLE s TO s_old, t TO s",
+			},
+		},
+		{
+			name:     "semicolons in quotes",
+			sql:      "INSERT INTO t VALUES ('a;b', 'it''s; \\'quoted\\''); SELECT `odd;name` FROM t",
+			expected: []string{"INSERT INTO t VALUES ('a;b', 'it''s; \\'quoted\\'')", "SELECT `odd;name` FROM t"},
+		},
+		{
+			name:     "only comments",
+			sql:      "-- nothing to do\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements := splitStatements(tt.sql)
+			if !reflect.DeepEqual(statements, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, statements)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+const (
+	// lockTTL is how long a lock is held at most, so that a crashed run does not block migrations forever
+	lockTTL = time.Hour
+	// lockPollInterval is the delay between attempts to acquire a lock held by another run
+	lockPollInterval = time.Second
+)
+
+// ErrDirty is returned when a previous run failed in the middle of a migration,
+// which leaves the schema in an unknown state. Fix the schema manually and set the version with Force.
+var ErrDirty = errors.New("database is dirty")
+
+// Status describes the schema version of the database.
+type Status struct {
+	Version int  // Version of the last applied migration, 0 if none is applied
+	Dirty   bool // Whether a migration failed in the middle
+
+	Applied []Migration
+	Pending []Migration
+}
+
+// Migrator applies migrations to a ClickHouse database.
+type Migrator struct {
+	conn       driver.Conn
+	migrations []Migration
+}
+
+// New creates a new Migrator of the migrations in the root of fsys.
+func New(conn driver.Conn, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		conn:       conn,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(current int) (int, error) {
+		if len(m.migrations) == 0 {
+			return 0, nil
+		}
+		return m.migrations[len(m.migrations)-1].Version, nil
+	})
+}
+
+// Down reverts the given number of the last applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("number of migrations to revert must be positive, got %d", steps)
+	}
+
+	return m.run(ctx, func(current int) (int, error) {
+		index, err := indexOf(m.migrations, current)
+		if err != nil {
+			return 0, fmt.Errorf("database version: %w", err)
+		}
+		if index-steps < 0 {
+			return 0, nil
+		}
+		return m.migrations[index-steps].Version, nil
+	})
+}
+
+// To applies or reverts migrations until the database has the given version.
+// Version 0 reverts all migrations.
+func (m *Migrator) To(ctx context.Context, version int) error {
+	if _, err := indexOf(m.migrations, version); err != nil {
+		return err
+	}
+
+	return m.run(ctx, func(current int) (int, error) {
+		return version, nil
+	})
+}
+
+// Force marks the migrations up to the version as applied without running them
+// and clears the dirty flag.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if _, err := indexOf(m.migrations, version); err != nil {
+		return err
+	}
+
+	return m.withLock(ctx, func() error {
+		if err := m.conn.Exec(syncMutations(ctx), "ALTER TABLE schema_migrations DELETE WHERE 1 = 1"); err != nil {
+			return fmt.Errorf("failed to reset schema version: %w", err)
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if err := m.markApplied(ctx, migration.Version); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status returns the schema version of the database and the applied and pending migrations.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	var tables uint64
+	err := m.conn.QueryRow(ctx,
+		"SELECT count() FROM system.tables WHERE database = currentDatabase() AND name = 'schema_migrations'",
+	).Scan(&tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations table: %w", err)
+	}
+
+	status := &Status{}
+	applied := make(map[int]bool)
+	if tables > 0 {
+		if applied, status.Dirty, err = m.readVersions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			status.Version = migration.Version
+			status.Applied = append(status.Applied, migration)
+		} else {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+
+	return status, nil
+}
+
+// run migrates the database to the version returned by target for the current version.
+func (m *Migrator) run(ctx context.Context, target func(current int) (int, error)) error {
+	return m.withLock(ctx, func() error {
+		applied, dirty, err := m.readVersions(ctx)
+		if err != nil {
+			return err
+		}
+		current := 0
+		for version := range applied {
+			current = max(current, version)
+		}
+		if dirty {
+			return fmt.Errorf("%w after version %d", ErrDirty, current)
+		}
+
+		version, err := target(current)
+		if err != nil {
+			return err
+		}
+		steps, err := plan(m.migrations, current, version)
+		if err != nil {
+			return err
+		}
+
+		if len(steps) == 0 {
+			log.Printf("Database is at version %d, no migrations to run", current)
+			return nil
+		}
+
+		for _, s := range steps {
+			if err := m.applyStep(ctx, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applyStep runs the statements of the migration one by one.
+// ClickHouse has no transactional DDL, so the version is marked dirty while the statements run;
+// if one fails, the version stays dirty until the schema is fixed manually.
+func (m *Migrator) applyStep(ctx context.Context, s step) error {
+	action, done, sql := "apply", "Applied", s.migration.Up
+	if !s.up {
+		action, done, sql = "revert", "Reverted", s.migration.Down
+	}
+
+	if err := m.conn.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES (?, 1)", s.migration.Version); err != nil {
+		return fmt.Errorf("failed to mark migration %s dirty: %w", s.migration, err)
+	}
+
+	for _, statement := range splitStatements(sql) {
+		if err := m.conn.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("failed to %s migration %s: %w", action, s.migration, err)
+		}
+	}
+
+	if s.up {
+		if err := m.markApplied(ctx, s.migration.Version); err != nil {
+			return err
+		}
+	} else {
+		err := m.conn.Exec(syncMutations(ctx), "ALTER TABLE schema_migrations DELETE WHERE version = ?", s.migration.Version)
+		if err != nil {
+			return fmt.Errorf("failed to unmark migration %s: %w", s.migration, err)
+		}
+	}
+
+	log.Printf("%s migration %s", done, s.migration)
+	return nil
+}
+
+// markApplied replaces the rows of the version with a clean one.
+func (m *Migrator) markApplied(ctx context.Context, version int) error {
+	if err := m.conn.Exec(syncMutations(ctx), "ALTER TABLE schema_migrations DELETE WHERE version = ?", version); err != nil {
+		return fmt.Errorf("failed to mark version %d applied: %w", version, err)
+	}
+	if err := m.conn.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES (?, 0)", version); err != nil {
+		return fmt.Errorf("failed to mark version %d applied: %w", version, err)
+	}
+	return nil
+}
+
+// readVersions returns the applied versions and whether a migration is dirty.
+func (m *Migrator) readVersions(ctx context.Context) (map[int]bool, bool, error) {
+	rows, err := m.conn.Query(ctx, "SELECT version, dirty FROM schema_migrations")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read schema versions: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	dirty := false
+	for rows.Next() {
+		var version uint32
+		var versionDirty uint8
+		if err := rows.Scan(&version, &versionDirty); err != nil {
+			return nil, false, fmt.Errorf("failed to scan schema version: %w", err)
+		}
+		if versionDirty != 0 {
+			dirty = true
+		} else {
+			applied[int(version)] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to read schema versions: %w", err)
+	}
+
+	return applied, dirty, nil
+}
+
+// withLock creates the tracking tables and runs fn holding the migration lock.
+//
+// ClickHouse has no advisory locks, so runs insert a row into schema_migrations_lock
+// and the run with the earliest unexpired row holds the lock; the others remove their rows and retry.
+// Insertion times are assigned by the server, so a run inserting after the holder checked never wins.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	err := m.conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version UInt32,
+		dirty UInt8,
+		applied_at DateTime DEFAULT now()
+	) ENGINE = MergeTree()
+	ORDER BY version
+	PRIMARY KEY version`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	err = m.conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+		owner String,
+		acquired_at DateTime64(9) DEFAULT now64(9),
+		expires_at DateTime
+	) ENGINE = MergeTree()
+	ORDER BY acquired_at`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations_lock table: %w", err)
+	}
+
+	owner, err := newLockOwner()
+	if err != nil {
+		return err
+	}
+
+	for {
+		acquired, err := m.tryLock(ctx, owner)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			break
+		}
+
+		log.Printf("Waiting for another migration run to finish...")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to lock migrations: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+	defer func() {
+		// Unlock even if ctx is cancelled; an unreleased lock expires after lockTTL
+		if err := m.unlock(context.Background(), owner); err != nil {
+			log.Printf("Failed to unlock migrations: %v", err)
+		}
+	}()
+
+	return fn()
+}
+
+// tryLock inserts the lock row of the owner and reports whether it holds the lock.
+// The row is removed again if another run holds the lock.
+func (m *Migrator) tryLock(ctx context.Context, owner string) (bool, error) {
+	err := m.conn.Exec(ctx,
+		"INSERT INTO schema_migrations_lock (owner, expires_at) SELECT ?, now() + ?",
+		owner, int64(lockTTL/time.Second))
+	if err != nil {
+		return false, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+
+	var holder string
+	err = m.conn.QueryRow(ctx,
+		"SELECT owner FROM schema_migrations_lock WHERE expires_at > now() ORDER BY acquired_at, owner LIMIT 1",
+	).Scan(&holder)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+
+	if holder == owner {
+		return true, nil
+	}
+	if err := m.unlock(ctx, owner); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// unlock removes the lock row of the owner.
+func (m *Migrator) unlock(ctx context.Context, owner string) error {
+	if err := m.conn.Exec(syncMutations(ctx), "ALTER TABLE schema_migrations_lock DELETE WHERE owner = ?", owner); err != nil {
+		return fmt.Errorf("failed to unlock migrations: %w", err)
+	}
+	return nil
+}
+
+// newLockOwner returns a random identifier of the migration run
+func newLockOwner() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock owner: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// syncMutations makes ALTER TABLE ... DELETE queries in ctx wait until the rows are deleted,
+// as mutations run in the background by default.
+func syncMutations(ctx context.Context) context.Context {
+	return clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+		"mutations_sync": 1,
+	}))
+}
@@ -1,5 +1,20 @@
 package models
 
+const (
+	// EventTypeTransferCompleted is the eventType of transfer completion events
+	EventTypeTransferCompleted = "transfer.completed"
+
+	// EventTypeTopUpCompleted is the eventType of top-up completion events
+	EventTypeTopUpCompleted = "topup.completed"
+)
+
+// EventEnvelope holds the fields shared by all bank operation events.
+// It is used to determine the event type before decoding the full payload.
+type EventEnvelope struct {
+	EventID   string `json:"eventId"`
+	EventType string `json:"eventType"`
+}
+
 // TransferCompletedEvent represents the event payload when a transfer is completed
 // This matches the AsyncAPI schema defined in services/common/analytics-service-kafka-spec/asyncapi.yaml
 type TransferCompletedEvent struct {
@@ -15,3 +30,20 @@ type TransferCompletedEvent struct {
 	Timestamp      string `json:"timestamp"`
 	Message        string `json:"message,omitempty"` // Optional field
 }
+
+// TopUpCompletedEvent represents the event payload when a top-up is completed
+// This matches the AsyncAPI schema defined in services/common/analytics-service-kafka-spec/asyncapi.yaml
+type TopUpCompletedEvent struct {
+	EventID               string `json:"eventId"`
+	EventType             string `json:"eventType"`
+	EventTimestamp        string `json:"eventTimestamp"`
+	OperationID           string `json:"operationId"`
+	AccountID             string `json:"accountId"`
+	Amount                Amount `json:"amount"`
+	IdempotencyKey        string `json:"idempotencyKey"`
+	Source                string `json:"source,omitempty"`                // Optional field
+	ExternalTransactionID string `json:"externalTransactionId,omitempty"` // Optional field
+	Status                string `json:"status"`
+	Timestamp             string `json:"timestamp"`
+	Message               string `json:"message,omitempty"` // Optional field
+}
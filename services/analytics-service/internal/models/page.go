@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// PageDirection selects the operations on one side of a cursor.
+// Operations are listed most recent first, so AFTER returns older and BEFORE returns newer operations.
+type PageDirection string
+
+const (
+	PageDirectionAfter  PageDirection = "AFTER"
+	PageDirectionBefore PageDirection = "BEFORE"
+)
+
+// OperationCursor is the position of an operation in the (timestamp, id) order of an account's operations
+type OperationCursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+// ListOperationsQuery selects a page of an account's operations
+type ListOperationsQuery struct {
+	AccountID string
+	Limit     int32
+	Cursor    *OperationCursor // nil for the first (most recent) page
+	Direction PageDirection
+	Filter    OperationFilter
+}
+
+// OperationFilter narrows down the listed operations; zero-valued fields do not filter
+type OperationFilter struct {
+	OperationType  OperationType
+	From           time.Time // inclusive
+	To             time.Time // exclusive
+	MinAmount      string    // decimal, inclusive
+	MaxAmount      string    // decimal, inclusive
+	CounterpartyID string    // matches transfers with this account on the other side
+	CurrencyCode   string
+}
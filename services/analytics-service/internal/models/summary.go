@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// SummaryBucket represents the time bucket used to break down an account summary
+type SummaryBucket string
+
+const (
+	SummaryBucketNone  SummaryBucket = ""
+	SummaryBucketDay   SummaryBucket = "DAY"
+	SummaryBucketWeek  SummaryBucket = "WEEK" // Weeks start on Monday
+	SummaryBucketMonth SummaryBucket = "MONTH"
+)
+
+// OperationsSummary represents aggregated statistics of an account's operations
+type OperationsSummary struct {
+	TotalIn         string // Sum of top-ups and incoming transfers
+	TotalOut        string // Sum of outgoing transfers
+	IncomingCount   uint64
+	OutgoingCount   uint64
+	TopUpCount      uint64
+	TransferCount   uint64
+	AverageTransfer string // Average amount of incoming and outgoing transfers, rounded down
+	CurrencyCode    string // Empty if there are no operations
+}
+
+// SummaryBucketStats represents the summary of a single time bucket
+type SummaryBucketStats struct {
+	Start   time.Time // Start date of the bucket
+	Summary OperationsSummary
+}
+
+// Counterparty represents aggregated transfers between an account and another account
+type Counterparty struct {
+	AccountID     string
+	TransferCount uint64
+	TotalIn       string // Received from the counterparty
+	TotalOut      string // Sent to the counterparty
+}
@@ -3,13 +3,17 @@ package repository
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/db"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
 )
 
-// OperationRepository handles operations data persistence in ClickHouse
+// OperationRepository handles operations data persistence in ClickHouse.
+// The operations table is a ReplacingMergeTree keyed by (account_id, timestamp, id),
+// so redelivered events are deduplicated; all reads use FINAL to see deduplicated rows.
 type OperationRepository struct {
 	db *db.ClickHouseClient
 }
@@ -46,41 +50,87 @@ func (r *OperationRepository) InsertOperation(ctx context.Context, op *models.Op
 	return nil
 }
 
-// ListAccountOperations retrieves operations for a specific account with pagination
+// InsertOperations inserts the operations into the database as a single block.
+// A single block insert is atomic, so either all operations are stored or none.
+func (r *OperationRepository) InsertOperations(ctx context.Context, ops []*models.Operation) error {
+	batch, err := r.db.Conn().PrepareBatch(ctx, `
+		INSERT INTO operations (
+			id, account_id, operation_type, timestamp,
+			amount_value, amount_currency, sender_id, recipient_id
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare operations batch: %w", err)
+	}
+	defer batch.Abort()
+
+	for _, op := range ops {
+		err := batch.Append(
+			op.ID,
+			op.AccountID,
+			string(op.OperationType),
+			op.Timestamp,
+			op.Amount.Value,
+			op.Amount.CurrencyCode,
+			op.SenderID,
+			op.RecipientID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append operation %s: %w", op.ID, err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to insert operations: %w", err)
+	}
+
+	return nil
+}
+
+// ListAccountOperations retrieves a page of the account's operations matching the query filter, most recent first.
+// Operations are ordered by (timestamp, id), so pages neither skip nor repeat operations
+// with equal timestamps. Returns whether there are more operations in the requested direction.
 func (r *OperationRepository) ListAccountOperations(
 	ctx context.Context,
-	accountID string,
-	limit int32,
-	afterID string,
-) ([]*models.Operation, error) {
-	query := `
+	query models.ListOperationsQuery,
+) ([]*models.Operation, bool, error) {
+	accountID := query.AccountID
+
+	sql := `
 		SELECT 
 			id, account_id, operation_type, timestamp,
-			toString(amount_value) as amount_value, amount_currency, sender_id, recipient_id
-		FROM operations
+			amount_value, amount_currency, sender_id, recipient_id
+		FROM operations FINAL
 		WHERE account_id = ?
 	`
 
 	args := []interface{}{accountID}
 
-	// Add pagination filter if afterID is provided
-	if afterID != "" {
-		query += " AND id > ?"
-		args = append(args, afterID)
+	filterSQL, filterArgs := operationFilterClause(query.Filter)
+	sql += filterSQL
+	args = append(args, filterArgs...)
+
+	// Newer operations are read in ascending order starting at the cursor, then reversed
+	order := "DESC"
+	if query.Cursor != nil {
+		if query.Direction == models.PageDirectionBefore {
+			sql += " AND (timestamp, id) > (?, ?)"
+			order = "ASC"
+		} else {
+			sql += " AND (timestamp, id) < (?, ?)"
+		}
+		args = append(args, query.Cursor.Timestamp, query.Cursor.ID)
 	}
 
-	// Order by timestamp descending (most recent first)
-	query += " ORDER BY timestamp DESC"
+	sql += " ORDER BY timestamp " + order + ", id " + order
 
-	// Apply limit if provided
-	if limit > 0 {
-		query += " LIMIT ?"
-		args = append(args, limit)
-	}
+	// Read one extra operation to know whether there are more
+	sql += " LIMIT ?"
+	args = append(args, query.Limit+1)
 
-	rows, err := r.db.Conn().Query(ctx, query, args...)
+	rows, err := r.db.Conn().Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query operations for account %s: %w", accountID, err)
+		return nil, false, fmt.Errorf("failed to query operations for account %s: %w", accountID, err)
 	}
 	defer rows.Close()
 
@@ -90,7 +140,7 @@ func (r *OperationRepository) ListAccountOperations(
 		var op models.Operation
 		var timestamp time.Time
 		var operationType string
-		var amountValue string
+		var amountValue decimal.Decimal
 
 		err := rows.Scan(
 			&op.ID,
@@ -104,31 +154,299 @@ func (r *OperationRepository) ListAccountOperations(
 		)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan operation row: %w", err)
+			return nil, false, fmt.Errorf("failed to scan operation row: %w", err)
 		}
 
 		op.Timestamp = timestamp
 		op.OperationType = models.OperationType(operationType)
 
-		// Format the amount to always have 2 decimal places
-		// Parse and reformat to ensure consistent decimal places
-		if amountValue != "" {
-			// ClickHouse toString() may return "150.5" instead of "150.50"
-			// We need to ensure 2 decimal places
-			var amountFloat float64
-			if _, err := fmt.Sscanf(amountValue, "%f", &amountFloat); err == nil {
-				op.Amount.Value = fmt.Sprintf("%.2f", amountFloat)
-			} else {
-				op.Amount.Value = amountValue
-			}
-		}
+		// Format the amount to always have 2 decimal places, e.g. "150.50" rather than "150.5"
+		op.Amount.Value = amountValue.StringFixed(2)
 
 		operations = append(operations, &op)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating operation rows: %w", err)
+		return nil, false, fmt.Errorf("error iterating operation rows: %w", err)
+	}
+
+	hasMore := len(operations) > int(query.Limit)
+	if hasMore {
+		operations = operations[:query.Limit]
+	}
+
+	if order == "ASC" {
+		slices.Reverse(operations)
+	}
+
+	return operations, hasMore, nil
+}
+
+// operationFilterClause returns the WHERE conditions of the filter, each starting with AND, and their arguments.
+// Amounts are compared as decimals, so "100" and "100.00" match the same operations.
+func operationFilterClause(filter models.OperationFilter) (string, []interface{}) {
+	var sql string
+	var args []interface{}
+
+	if filter.OperationType != "" {
+		sql += " AND operation_type = ?"
+		args = append(args, string(filter.OperationType))
+	}
+	if !filter.From.IsZero() {
+		sql += " AND timestamp >= ?"
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		sql += " AND timestamp < ?"
+		args = append(args, filter.To)
+	}
+	if filter.MinAmount != "" {
+		sql += " AND amount_value >= toDecimal64(?, 2)"
+		args = append(args, filter.MinAmount)
+	}
+	if filter.MaxAmount != "" {
+		sql += " AND amount_value <= toDecimal64(?, 2)"
+		args = append(args, filter.MaxAmount)
+	}
+	if filter.CounterpartyID != "" {
+		sql += " AND operation_type = 'TRANSFER' AND if(sender_id = account_id, recipient_id, sender_id) = ?"
+		args = append(args, filter.CounterpartyID)
+	}
+	if filter.CurrencyCode != "" {
+		sql += " AND amount_currency = ?"
+		args = append(args, filter.CurrencyCode)
+	}
+
+	return sql, args
+}
+
+// GetAccountBalance returns the net amount of the account's operations before the given time.
+// Top-ups and incoming transfers are added, outgoing transfers are subtracted.
+// The currency code is empty if the account has no operations before that time.
+func (r *OperationRepository) GetAccountBalance(ctx context.Context, accountID string, before time.Time) (models.Amount, error) {
+	query := `
+		SELECT
+			toString(
+				sumIf(amount_value, operation_type = 'TOPUP' OR recipient_id = account_id)
+				- sumIf(amount_value, operation_type = 'TRANSFER' AND sender_id = account_id)
+			) AS balance,
+			any(amount_currency) AS currency
+		FROM operations FINAL
+		WHERE account_id = ? AND timestamp < ?
+	`
+
+	var balance models.Amount
+	row := r.db.Conn().QueryRow(ctx, query, accountID, before)
+	if err := row.Scan(&balance.Value, &balance.CurrencyCode); err != nil {
+		return models.Amount{}, fmt.Errorf("failed to get balance for account %s: %w", accountID, err)
+	}
+
+	return balance, nil
+}
+
+// StreamAccountOperations calls fn for each operation of the account in the [from, to) time range
+// in chronological order. Rows are read one by one, so the whole range is never held in memory.
+// Amount values are returned as stored, without reformatting.
+func (r *OperationRepository) StreamAccountOperations(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+	fn func(op *models.Operation) error,
+) error {
+	query := `
+		SELECT
+			id, account_id, operation_type, timestamp,
+			toString(amount_value) as amount_value, amount_currency, sender_id, recipient_id
+		FROM operations FINAL
+		WHERE account_id = ? AND timestamp >= ? AND timestamp < ?
+		ORDER BY timestamp ASC, id ASC
+	`
+
+	rows, err := r.db.Conn().Query(ctx, query, accountID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to query operations for account %s: %w", accountID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var op models.Operation
+		var operationType string
+
+		err := rows.Scan(
+			&op.ID,
+			&op.AccountID,
+			&operationType,
+			&op.Timestamp,
+			&op.Amount.Value,
+			&op.Amount.CurrencyCode,
+			&op.SenderID,
+			&op.RecipientID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan operation row: %w", err)
+		}
+		op.OperationType = models.OperationType(operationType)
+
+		if err := fn(&op); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating operation rows: %w", err)
+	}
+
+	return nil
+}
+
+// summaryAggregates is the select list shared by the account summary queries.
+// The account_id column equals the queried account, so it tells incoming and outgoing transfers apart.
+// The average is computed as a Decimal division, which rounds down to 2 decimal places.
+const summaryAggregates = `
+	toString(sumIf(amount_value, operation_type = 'TOPUP' OR recipient_id = account_id)) AS total_in,
+	toString(sumIf(amount_value, operation_type = 'TRANSFER' AND sender_id = account_id)) AS total_out,
+	countIf(operation_type = 'TOPUP' OR recipient_id = account_id) AS incoming_count,
+	countIf(operation_type = 'TRANSFER' AND sender_id = account_id) AS outgoing_count,
+	countIf(operation_type = 'TOPUP') AS topup_count,
+	countIf(operation_type = 'TRANSFER') AS transfer_count,
+	toString(sumIf(amount_value, operation_type = 'TRANSFER') / greatest(countIf(operation_type = 'TRANSFER'), 1)) AS average_transfer,
+	any(amount_currency) AS currency
+`
+
+// summaryBucketExpressions maps summary buckets to the ClickHouse expressions of the bucket start date
+var summaryBucketExpressions = map[models.SummaryBucket]string{
+	models.SummaryBucketDay:   "toDate(timestamp)",
+	models.SummaryBucketWeek:  "toMonday(timestamp)",
+	models.SummaryBucketMonth: "toStartOfMonth(timestamp)",
+}
+
+// GetAccountSummary aggregates the account's operations in the [from, to) time range
+func (r *OperationRepository) GetAccountSummary(ctx context.Context, accountID string, from, to time.Time) (*models.OperationsSummary, error) {
+	query := `
+		SELECT ` + summaryAggregates + `
+		FROM operations FINAL
+		WHERE account_id = ? AND timestamp >= ? AND timestamp < ?
+	`
+
+	var summary models.OperationsSummary
+	row := r.db.Conn().QueryRow(ctx, query, accountID, from, to)
+	if err := row.Scan(
+		&summary.TotalIn,
+		&summary.TotalOut,
+		&summary.IncomingCount,
+		&summary.OutgoingCount,
+		&summary.TopUpCount,
+		&summary.TransferCount,
+		&summary.AverageTransfer,
+		&summary.CurrencyCode,
+	); err != nil {
+		return nil, fmt.Errorf("failed to get summary for account %s: %w", accountID, err)
+	}
+
+	return &summary, nil
+}
+
+// GetAccountSummaryBuckets aggregates the account's operations in the [from, to) time range
+// per time bucket. Only buckets with operations are returned, in chronological order.
+func (r *OperationRepository) GetAccountSummaryBuckets(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+	bucket models.SummaryBucket,
+) ([]*models.SummaryBucketStats, error) {
+	bucketExpression, ok := summaryBucketExpressions[bucket]
+	if !ok {
+		return nil, fmt.Errorf("unsupported summary bucket: %q", bucket)
+	}
+
+	query := `
+		SELECT ` + bucketExpression + ` AS bucket_start, ` + summaryAggregates + `
+		FROM operations FINAL
+		WHERE account_id = ? AND timestamp >= ? AND timestamp < ?
+		GROUP BY bucket_start
+		ORDER BY bucket_start
+	`
+
+	rows, err := r.db.Conn().Query(ctx, query, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query summary buckets for account %s: %w", accountID, err)
+	}
+	defer rows.Close()
+
+	var buckets []*models.SummaryBucketStats
+
+	for rows.Next() {
+		var stats models.SummaryBucketStats
+		err := rows.Scan(
+			&stats.Start,
+			&stats.Summary.TotalIn,
+			&stats.Summary.TotalOut,
+			&stats.Summary.IncomingCount,
+			&stats.Summary.OutgoingCount,
+			&stats.Summary.TopUpCount,
+			&stats.Summary.TransferCount,
+			&stats.Summary.AverageTransfer,
+			&stats.Summary.CurrencyCode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan summary bucket row: %w", err)
+		}
+		buckets = append(buckets, &stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating summary bucket rows: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// GetTopCounterparties returns the accounts the account transferred the most money with
+// in the [from, to) time range, ordered by transferred volume in both directions
+func (r *OperationRepository) GetTopCounterparties(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+	limit int32,
+) ([]*models.Counterparty, error) {
+	query := `
+		SELECT
+			if(sender_id = account_id, recipient_id, sender_id) AS counterparty_id,
+			count() AS transfer_count,
+			toString(sumIf(amount_value, recipient_id = account_id)) AS total_in,
+			toString(sumIf(amount_value, sender_id = account_id)) AS total_out
+		FROM operations FINAL
+		WHERE account_id = ? AND operation_type = 'TRANSFER' AND timestamp >= ? AND timestamp < ?
+		GROUP BY counterparty_id
+		ORDER BY sum(amount_value) DESC, transfer_count DESC, counterparty_id
+		LIMIT ?
+	`
+
+	rows, err := r.db.Conn().Query(ctx, query, accountID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query counterparties for account %s: %w", accountID, err)
+	}
+	defer rows.Close()
+
+	var counterparties []*models.Counterparty
+
+	for rows.Next() {
+		var counterparty models.Counterparty
+		err := rows.Scan(
+			&counterparty.AccountID,
+			&counterparty.TransferCount,
+			&counterparty.TotalIn,
+			&counterparty.TotalOut,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan counterparty row: %w", err)
+		}
+		counterparties = append(counterparties, &counterparty)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating counterparty rows: %w", err)
 	}
 
-	return operations, nil
+	return counterparties, nil
 }
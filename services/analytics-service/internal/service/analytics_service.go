@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/repository"
@@ -14,7 +16,12 @@ import (
 // OperationRepository defines the interface for operation data access
 type OperationRepository interface {
 	InsertOperation(ctx context.Context, op *models.Operation) error
-	ListAccountOperations(ctx context.Context, accountID string, limit int32, afterID string) ([]*models.Operation, error)
+	ListAccountOperations(ctx context.Context, query models.ListOperationsQuery) ([]*models.Operation, bool, error)
+	GetAccountBalance(ctx context.Context, accountID string, before time.Time) (models.Amount, error)
+	StreamAccountOperations(ctx context.Context, accountID string, from, to time.Time, fn func(op *models.Operation) error) error
+	GetAccountSummary(ctx context.Context, accountID string, from, to time.Time) (*models.OperationsSummary, error)
+	GetAccountSummaryBuckets(ctx context.Context, accountID string, from, to time.Time, bucket models.SummaryBucket) ([]*models.SummaryBucketStats, error)
+	GetTopCounterparties(ctx context.Context, accountID string, from, to time.Time, limit int32) ([]*models.Counterparty, error)
 }
 
 // AnalyticsService implements the gRPC AnalyticsService interface
@@ -37,30 +44,25 @@ func NewAnalyticsServiceWithRepo(repo *repository.OperationRepository) *Analytic
 	}
 }
 
-// ListAccountOperations returns operation history for a specific account with pagination
+// ListAccountOperations returns a page of the operation history of a specific account, most recent first
 func (s *AnalyticsService) ListAccountOperations(
 	ctx context.Context,
 	req *pb.ListAccountOperationsRequest,
 ) (*pb.ListAccountOperationsResponse, error) {
 	// Validate request
-	if err := s.validateListRequest(req); err != nil {
+	query, err := s.validateListRequest(req)
+	if err != nil {
 		return nil, err
 	}
 
 	// Query operations from repository
-	operations, err := s.repo.ListAccountOperations(
-		ctx,
-		req.AccountId,
-		req.Limit,
-		req.AfterId,
-	)
+	operations, hasMore, err := s.repo.ListAccountOperations(ctx, query)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list operations: %v", err)
 	}
 
 	// Convert domain models to protobuf messages
 	pbOperations := make([]*pb.Operation, 0, len(operations))
-	var lastID string
 
 	for _, op := range operations {
 		pbOp, err := s.convertToProto(op)
@@ -69,26 +71,150 @@ func (s *AnalyticsService) ListAccountOperations(
 		}
 
 		pbOperations = append(pbOperations, pbOp)
-		lastID = op.ID
 	}
 
-	return &pb.ListAccountOperationsResponse{
+	resp := &pb.ListAccountOperationsResponse{
 		Content: pbOperations,
-		AfterId: lastID,
-	}, nil
+		HasMore: hasMore,
+	}
+
+	if len(operations) > 0 {
+		resp.PrevCursor = encodeCursor(operations[0])
+		resp.NextCursor = encodeCursor(operations[len(operations)-1])
+	}
+
+	return resp, nil
 }
 
-// validateListRequest validates the ListAccountOperations request
-func (s *AnalyticsService) validateListRequest(req *pb.ListAccountOperationsRequest) error {
+// validateListRequest validates the ListAccountOperations request and returns the page query
+func (s *AnalyticsService) validateListRequest(req *pb.ListAccountOperationsRequest) (models.ListOperationsQuery, error) {
 	if req.AccountId == "" {
-		return status.Error(codes.InvalidArgument, "account_id is required")
+		return models.ListOperationsQuery{}, status.Error(codes.InvalidArgument, "account_id is required")
 	}
 
 	if req.Limit < 0 {
-		return status.Error(codes.InvalidArgument, "limit cannot be negative")
+		return models.ListOperationsQuery{}, status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
+
+	query := models.ListOperationsQuery{
+		AccountID: req.AccountId,
+		Limit:     req.Limit,
+		Direction: models.PageDirectionAfter,
+	}
+
+	// Apply default and max page size
+	if query.Limit == 0 {
+		query.Limit = defaultOperationsPageSize
+	}
+	query.Limit = min(query.Limit, maxOperationsPageSize)
+
+	switch req.Direction {
+	case pb.PageDirection_PAGE_DIRECTION_UNSPECIFIED, pb.PageDirection_PAGE_DIRECTION_AFTER:
+	case pb.PageDirection_PAGE_DIRECTION_BEFORE:
+		query.Direction = models.PageDirectionBefore
+	default:
+		return models.ListOperationsQuery{}, status.Errorf(codes.InvalidArgument, "unsupported direction: %v", req.Direction)
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return models.ListOperationsQuery{}, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+		query.Cursor = cursor
+	}
+
+	filter, err := s.validateOperationFilter(req)
+	if err != nil {
+		return models.ListOperationsQuery{}, err
+	}
+	query.Filter = filter
+
+	return query, nil
+}
+
+// validateOperationFilter validates the filters of the ListAccountOperations request
+func (s *AnalyticsService) validateOperationFilter(req *pb.ListAccountOperationsRequest) (models.OperationFilter, error) {
+	filter := models.OperationFilter{
+		CounterpartyID: req.CounterpartyId,
+		CurrencyCode:   req.CurrencyCode,
+	}
+
+	switch req.Type {
+	case pb.OperationType_OPERATION_TYPE_UNSPECIFIED:
+	case pb.OperationType_TOPUP:
+		filter.OperationType = models.OperationTypeTopup
+	case pb.OperationType_TRANSFER:
+		filter.OperationType = models.OperationTypeTransfer
+	default:
+		return models.OperationFilter{}, status.Errorf(codes.InvalidArgument, "unsupported type: %v", req.Type)
+	}
+
+	if req.From != "" {
+		from, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return models.OperationFilter{}, status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+		}
+		filter.From = from
+	}
+
+	if req.To != "" {
+		to, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return models.OperationFilter{}, status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+		}
+		filter.To = to
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return models.OperationFilter{}, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	minAmount, err := parseFilterAmount(req.MinAmount)
+	if err != nil {
+		return models.OperationFilter{}, status.Errorf(codes.InvalidArgument, "invalid min_amount: %v", err)
+	}
+
+	maxAmount, err := parseFilterAmount(req.MaxAmount)
+	if err != nil {
+		return models.OperationFilter{}, status.Errorf(codes.InvalidArgument, "invalid max_amount: %v", err)
+	}
+
+	if minAmount != nil && maxAmount != nil && minAmount.Cmp(maxAmount) > 0 {
+		return models.OperationFilter{}, status.Error(codes.InvalidArgument, "min_amount cannot be greater than max_amount")
+	}
+
+	if minAmount != nil {
+		filter.MinAmount = minAmount.FloatString(2)
+	}
+	if maxAmount != nil {
+		filter.MaxAmount = maxAmount.FloatString(2)
+	}
+
+	return filter, nil
+}
+
+// parseFilterAmount parses a non-negative amount with at most 2 decimal places.
+// Returns nil if the amount is empty.
+func parseFilterAmount(value string) (*big.Rat, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := parseDecimal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount.Sign() < 0 {
+		return nil, fmt.Errorf("amount cannot be negative")
+	}
+
+	if !new(big.Rat).Mul(amount, big.NewRat(100, 1)).IsInt() {
+		return nil, fmt.Errorf("amount cannot have more than 2 decimal places")
 	}
 
-	return nil
+	return amount, nil
 }
 
 // convertToProto converts a domain Operation model to protobuf Operation
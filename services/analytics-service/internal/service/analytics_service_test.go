@@ -13,8 +13,20 @@ import (
 
 // MockOperationRepository is a mock implementation of the repository for testing
 type MockOperationRepository struct {
-	operations []*models.Operation
-	err        error
+	operations     []*models.Operation
+	balance        models.Amount
+	summary        models.OperationsSummary
+	buckets        []*models.SummaryBucketStats
+	counterparties []*models.Counterparty
+	hasMore        bool
+	err            error
+
+	// Query of the last ListAccountOperations call
+	lastQuery models.ListOperationsQuery
+
+	// Arguments of the last summary queries
+	lastBucket models.SummaryBucket
+	lastLimit  int32
 }
 
 func (m *MockOperationRepository) InsertOperation(ctx context.Context, op *models.Operation) error {
@@ -26,15 +38,72 @@ func (m *MockOperationRepository) InsertOperation(ctx context.Context, op *model
 }
 
 func (m *MockOperationRepository) ListAccountOperations(
+	ctx context.Context,
+	query models.ListOperationsQuery,
+) ([]*models.Operation, bool, error) {
+	m.lastQuery = query
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	return m.operations, m.hasMore, nil
+}
+
+func (m *MockOperationRepository) GetAccountBalance(ctx context.Context, accountID string, before time.Time) (models.Amount, error) {
+	if m.err != nil {
+		return models.Amount{}, m.err
+	}
+	return m.balance, nil
+}
+
+func (m *MockOperationRepository) StreamAccountOperations(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+	fn func(op *models.Operation) error,
+) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, op := range m.operations {
+		if err := fn(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockOperationRepository) GetAccountSummary(ctx context.Context, accountID string, from, to time.Time) (*models.OperationsSummary, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	summary := m.summary
+	return &summary, nil
+}
+
+func (m *MockOperationRepository) GetAccountSummaryBuckets(
 	ctx context.Context,
 	accountID string,
+	from, to time.Time,
+	bucket models.SummaryBucket,
+) ([]*models.SummaryBucketStats, error) {
+	m.lastBucket = bucket
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.buckets, nil
+}
+
+func (m *MockOperationRepository) GetTopCounterparties(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
 	limit int32,
-	afterID string,
-) ([]*models.Operation, error) {
+) ([]*models.Counterparty, error) {
+	m.lastLimit = limit
 	if m.err != nil {
 		return nil, m.err
 	}
-	return m.operations, nil
+	return m.counterparties, nil
 }
 
 func TestListAccountOperations_Success(t *testing.T) {
@@ -73,8 +142,12 @@ func TestListAccountOperations_Success(t *testing.T) {
 		t.Errorf("expected 1 operation, got %d", len(resp.Content))
 	}
 
-	if resp.AfterId != "op-1" {
-		t.Errorf("expected afterId to be 'op-1', got %s", resp.AfterId)
+	if resp.NextCursor == "" || resp.NextCursor != resp.PrevCursor {
+		t.Errorf("expected next and prev cursors of the only operation, got %q and %q", resp.NextCursor, resp.PrevCursor)
+	}
+
+	if resp.HasMore {
+		t.Error("expected has_more to be false")
 	}
 
 	op := resp.Content[0]
@@ -137,6 +210,188 @@ func TestListAccountOperations_NegativeLimit(t *testing.T) {
 	}
 }
 
+func TestListAccountOperations_Cursors(t *testing.T) {
+	newer := &models.Operation{
+		ID:            "op-2",
+		OperationType: models.OperationTypeTopup,
+		Timestamp:     time.Date(2025, 11, 12, 11, 0, 0, 0, time.UTC),
+	}
+	older := &models.Operation{
+		ID:            "op-1",
+		OperationType: models.OperationTypeTopup,
+		Timestamp:     time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC),
+	}
+	mockRepo := &MockOperationRepository{
+		operations: []*models.Operation{newer, older},
+		hasMore:    true,
+	}
+	service := NewAnalyticsService(mockRepo)
+
+	resp, err := service.ListAccountOperations(context.Background(), &pb.ListAccountOperationsRequest{
+		AccountId: "acc-1",
+		Limit:     2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !resp.HasMore {
+		t.Error("expected has_more to be true")
+	}
+	if mockRepo.lastQuery.Cursor != nil {
+		t.Errorf("expected first page query without cursor, got %+v", mockRepo.lastQuery.Cursor)
+	}
+
+	// The next page starts after the last (oldest) operation
+	_, err = service.ListAccountOperations(context.Background(), &pb.ListAccountOperationsRequest{
+		AccountId: "acc-1",
+		Limit:     2,
+		Cursor:    resp.NextCursor,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mockRepo.lastQuery.Direction != models.PageDirectionAfter {
+		t.Errorf("expected direction AFTER by default, got %s", mockRepo.lastQuery.Direction)
+	}
+	if c := mockRepo.lastQuery.Cursor; c == nil || c.ID != "op-1" || !c.Timestamp.Equal(older.Timestamp) {
+		t.Errorf("expected cursor of op-1, got %+v", c)
+	}
+
+	// The previous page ends before the first (newest) operation
+	_, err = service.ListAccountOperations(context.Background(), &pb.ListAccountOperationsRequest{
+		AccountId: "acc-1",
+		Limit:     2,
+		Cursor:    resp.PrevCursor,
+		Direction: pb.PageDirection_PAGE_DIRECTION_BEFORE,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mockRepo.lastQuery.Direction != models.PageDirectionBefore {
+		t.Errorf("expected direction BEFORE, got %s", mockRepo.lastQuery.Direction)
+	}
+	if c := mockRepo.lastQuery.Cursor; c == nil || c.ID != "op-2" || !c.Timestamp.Equal(newer.Timestamp) {
+		t.Errorf("expected cursor of op-2, got %+v", c)
+	}
+}
+
+func TestListAccountOperations_PageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int32
+		expected int32
+	}{
+		{"default", 0, defaultOperationsPageSize},
+		{"requested", 10, 10},
+		{"clamped", maxOperationsPageSize + 1, maxOperationsPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockOperationRepository{}
+			service := NewAnalyticsService(mockRepo)
+
+			_, err := service.ListAccountOperations(context.Background(), &pb.ListAccountOperationsRequest{
+				AccountId: "acc-1",
+				Limit:     tt.limit,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mockRepo.lastQuery.Limit != tt.expected {
+				t.Errorf("expected limit %d, got %d", tt.expected, mockRepo.lastQuery.Limit)
+			}
+		})
+	}
+}
+
+func TestListAccountOperations_InvalidCursor(t *testing.T) {
+	service := NewAnalyticsService(&MockOperationRepository{})
+
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "YWJjOm9wLTE"} {
+		_, err := service.ListAccountOperations(context.Background(), &pb.ListAccountOperationsRequest{
+			AccountId: "acc-1",
+			Cursor:    cursor,
+		})
+
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for cursor %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestListAccountOperations_Filters(t *testing.T) {
+	mockRepo := &MockOperationRepository{}
+	service := NewAnalyticsService(mockRepo)
+
+	_, err := service.ListAccountOperations(context.Background(), &pb.ListAccountOperationsRequest{
+		AccountId:      "acc-1",
+		Type:           pb.OperationType_TRANSFER,
+		From:           "2025-10-01T00:00:00Z",
+		To:             "2025-11-01T00:00:00+03:00",
+		MinAmount:      "10",
+		MaxAmount:      "100.5",
+		CounterpartyId: "acc-2",
+		CurrencyCode:   "RUB",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := models.OperationFilter{
+		OperationType:  models.OperationTypeTransfer,
+		From:           time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2025, 10, 31, 21, 0, 0, 0, time.UTC),
+		MinAmount:      "10.00",
+		MaxAmount:      "100.50",
+		CounterpartyID: "acc-2",
+		CurrencyCode:   "RUB",
+	}
+
+	filter := mockRepo.lastQuery.Filter
+	if filter.OperationType != expected.OperationType ||
+		!filter.From.Equal(expected.From) ||
+		!filter.To.Equal(expected.To) ||
+		filter.MinAmount != expected.MinAmount ||
+		filter.MaxAmount != expected.MaxAmount ||
+		filter.CounterpartyID != expected.CounterpartyID ||
+		filter.CurrencyCode != expected.CurrencyCode {
+		t.Errorf("expected filter %+v, got %+v", expected, filter)
+	}
+}
+
+func TestListAccountOperations_InvalidFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.ListAccountOperationsRequest
+	}{
+		{"unknown type", &pb.ListAccountOperationsRequest{AccountId: "acc-1", Type: pb.OperationType(42)}},
+		{"invalid from", &pb.ListAccountOperationsRequest{AccountId: "acc-1", From: "yesterday"}},
+		{"invalid to", &pb.ListAccountOperationsRequest{AccountId: "acc-1", To: "2025-10-01"}},
+		{"from after to", &pb.ListAccountOperationsRequest{AccountId: "acc-1", From: "2025-11-01T00:00:00Z", To: "2025-10-01T00:00:00Z"}},
+		{"invalid min amount", &pb.ListAccountOperationsRequest{AccountId: "acc-1", MinAmount: "ten"}},
+		{"negative max amount", &pb.ListAccountOperationsRequest{AccountId: "acc-1", MaxAmount: "-1.00"}},
+		{"too precise amount", &pb.ListAccountOperationsRequest{AccountId: "acc-1", MinAmount: "10.005"}},
+		{"min amount above max amount", &pb.ListAccountOperationsRequest{AccountId: "acc-1", MinAmount: "100", MaxAmount: "10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAnalyticsService(&MockOperationRepository{})
+
+			_, err := service.ListAccountOperations(context.Background(), tt.req)
+
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
 func TestConvertToProto_Transfer(t *testing.T) {
 	mockRepo := &MockOperationRepository{}
 	service := NewAnalyticsService(mockRepo)
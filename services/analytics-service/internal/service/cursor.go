@@ -0,0 +1,48 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+)
+
+// Page sizes of ListAccountOperations
+const (
+	defaultOperationsPageSize = 50
+	maxOperationsPageSize     = 1000
+)
+
+// encodeCursor returns an opaque cursor pointing at the operation.
+// The cursor encodes the (timestamp, id) position of the operation; timestamps are stored
+// with millisecond precision, so milliseconds identify them exactly.
+func encodeCursor(op *models.Operation) string {
+	raw := strconv.FormatInt(op.Timestamp.UnixMilli(), 10) + ":" + op.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor decodes a cursor created by encodeCursor
+func decodeCursor(cursor string) (*models.OperationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	millis, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	timestamp, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor timestamp: %w", err)
+	}
+
+	return &models.OperationCursor{
+		Timestamp: time.UnixMilli(timestamp).UTC(),
+		ID:        id,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	op := &models.Operation{
+		ID:        "123e4567-e89b-12d3-a456-426614174000",
+		Timestamp: time.Date(2025, 11, 12, 10, 30, 45, 123000000, time.UTC),
+	}
+
+	cursor, err := decodeCursor(encodeCursor(op))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cursor.ID != op.ID {
+		t.Errorf("expected ID %s, got %s", op.ID, cursor.ID)
+	}
+	if !cursor.Timestamp.Equal(op.Timestamp) {
+		t.Errorf("expected timestamp %s, got %s", op.Timestamp, cursor.Timestamp)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not base64!"},
+		{"no separator", "bm8tc2VwYXJhdG9y"}, // "no-separator"
+		{"invalid timestamp", "YWJjOm9wLTE"}, // "abc:op-1"
+		{"empty id", "MTczMTQwNzQ0NTEyMzo"},  // "1731407445123:"
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor); err == nil {
+				t.Errorf("expected error for cursor %q", tt.cursor)
+			}
+		})
+	}
+}
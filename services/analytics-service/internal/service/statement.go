@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/proto/analytics.v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statementChunkSize is the approximate size of a streamed statement chunk in bytes
+const statementChunkSize = 32 * 1024
+
+// statementTimeFormat is the ISO 8601 format of statement timestamps
+const statementTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// Statement record types
+const (
+	recordTypeOpeningBalance = "OPENING_BALANCE"
+	recordTypeOperation      = "OPERATION"
+	recordTypeClosingBalance = "CLOSING_BALANCE"
+)
+
+// statementCSVHeader is the header row of CSV statements
+var statementCSVHeader = []string{
+	"record_type", "operation_id", "timestamp", "operation_type", "counterparty_id",
+	"amount", "currency_code", "balance", "total_in", "total_out",
+}
+
+// statementRecord is a single line of an account statement.
+// Amount is signed: positive for incoming and negative for outgoing operations.
+// Balance, TotalIn and TotalOut are the running values after the record.
+type statementRecord struct {
+	RecordType     string `json:"recordType"`
+	OperationID    string `json:"operationId,omitempty"`
+	Timestamp      string `json:"timestamp"`
+	OperationType  string `json:"operationType,omitempty"`
+	CounterpartyID string `json:"counterpartyId,omitempty"`
+	Amount         string `json:"amount,omitempty"`
+	CurrencyCode   string `json:"currencyCode"`
+	Balance        string `json:"balance"`
+	TotalIn        string `json:"totalIn"`
+	TotalOut       string `json:"totalOut"`
+}
+
+// csvFields returns the record as a CSV row in statementCSVHeader order
+func (r *statementRecord) csvFields() []string {
+	return []string{
+		r.RecordType, r.OperationID, r.Timestamp, r.OperationType, r.CounterpartyID,
+		r.Amount, r.CurrencyCode, r.Balance, r.TotalIn, r.TotalOut,
+	}
+}
+
+// ExportAccountStatement streams the account statement for the requested time range
+func (s *AnalyticsService) ExportAccountStatement(
+	req *pb.ExportAccountStatementRequest,
+	stream pb.AnalyticsService_ExportAccountStatementServer,
+) error {
+	// Validate request
+	from, to, err := s.validateExportRequest(req)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+
+	opening, err := s.repo.GetAccountBalance(ctx, req.AccountId, from)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get opening balance: %v", err)
+	}
+
+	totals, err := newStatementTotals(opening.Value)
+	if err != nil {
+		return status.Errorf(codes.Internal, "invalid opening balance: %v", err)
+	}
+	currencyCode := opening.CurrencyCode
+
+	writer := newStatementWriter(req.Format, func(data []byte) error {
+		return stream.Send(&pb.StatementChunk{Data: data})
+	})
+
+	// Opening balance
+	if err := writer.write(totals.record(recordTypeOpeningBalance, from, currencyCode)); err != nil {
+		return status.Errorf(codes.Internal, "failed to write statement: %v", err)
+	}
+
+	// Operations with running balance and totals
+	err = s.repo.StreamAccountOperations(ctx, req.AccountId, from, to, func(op *models.Operation) error {
+		record, err := totals.apply(req.AccountId, op)
+		if err != nil {
+			return err
+		}
+		if currencyCode == "" {
+			currencyCode = op.Amount.CurrencyCode
+		}
+		return writer.write(record)
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to export operations: %v", err)
+	}
+
+	// Closing balance
+	if err := writer.write(totals.record(recordTypeClosingBalance, to, currencyCode)); err != nil {
+		return status.Errorf(codes.Internal, "failed to write statement: %v", err)
+	}
+
+	if err := writer.flush(); err != nil {
+		return status.Errorf(codes.Internal, "failed to write statement: %v", err)
+	}
+
+	return nil
+}
+
+// validateExportRequest validates the ExportAccountStatement request and returns the time range
+func (s *AnalyticsService) validateExportRequest(req *pb.ExportAccountStatementRequest) (time.Time, time.Time, error) {
+	if req.AccountId == "" {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+	}
+
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	switch req.Format {
+	case pb.StatementFormat_STATEMENT_FORMAT_UNSPECIFIED, pb.StatementFormat_STATEMENT_FORMAT_CSV, pb.StatementFormat_STATEMENT_FORMAT_JSONL:
+	default:
+		return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "unsupported format: %v", req.Format)
+	}
+
+	return from, to, nil
+}
+
+// statementTotals tracks the running balance and totals of a statement.
+// Amounts are exact decimals, so no rounding happens however many operations are added.
+type statementTotals struct {
+	balance  *big.Rat
+	totalIn  *big.Rat
+	totalOut *big.Rat
+}
+
+// newStatementTotals creates totals starting from the given opening balance
+func newStatementTotals(openingBalance string) (*statementTotals, error) {
+	balance, err := parseDecimal(openingBalance)
+	if err != nil {
+		return nil, err
+	}
+	return &statementTotals{
+		balance:  balance,
+		totalIn:  new(big.Rat),
+		totalOut: new(big.Rat),
+	}, nil
+}
+
+// apply adds the operation to the totals and returns its statement record
+func (t *statementTotals) apply(accountID string, op *models.Operation) (statementRecord, error) {
+	amount, err := parseDecimal(op.Amount.Value)
+	if err != nil {
+		return statementRecord{}, fmt.Errorf("invalid amount of operation %s: %w", op.ID, err)
+	}
+
+	record := statementRecord{
+		RecordType:    recordTypeOperation,
+		OperationID:   op.ID,
+		Timestamp:     op.Timestamp.UTC().Format(statementTimeFormat),
+		OperationType: string(op.OperationType),
+		CurrencyCode:  op.Amount.CurrencyCode,
+	}
+
+	switch {
+	case op.OperationType == models.OperationTypeTopup:
+		t.credit(amount)
+	case op.OperationType == models.OperationTypeTransfer && op.RecipientID == accountID:
+		record.CounterpartyID = op.SenderID
+		t.credit(amount)
+	case op.OperationType == models.OperationTypeTransfer && op.SenderID == accountID:
+		record.CounterpartyID = op.RecipientID
+		t.debit(amount)
+		amount.Neg(amount)
+	default:
+		return statementRecord{}, fmt.Errorf("unknown operation type: %s", op.OperationType)
+	}
+
+	record.Amount = amount.FloatString(2)
+	record.Balance = t.balance.FloatString(2)
+	record.TotalIn = t.totalIn.FloatString(2)
+	record.TotalOut = t.totalOut.FloatString(2)
+	return record, nil
+}
+
+// record returns a balance record with the current totals
+func (t *statementTotals) record(recordType string, timestamp time.Time, currencyCode string) statementRecord {
+	return statementRecord{
+		RecordType:   recordType,
+		Timestamp:    timestamp.UTC().Format(statementTimeFormat),
+		CurrencyCode: currencyCode,
+		Balance:      t.balance.FloatString(2),
+		TotalIn:      t.totalIn.FloatString(2),
+		TotalOut:     t.totalOut.FloatString(2),
+	}
+}
+
+func (t *statementTotals) credit(amount *big.Rat) {
+	t.balance.Add(t.balance, amount)
+	t.totalIn.Add(t.totalIn, amount)
+}
+
+func (t *statementTotals) debit(amount *big.Rat) {
+	t.balance.Sub(t.balance, amount)
+	t.totalOut.Add(t.totalOut, amount)
+}
+
+// parseDecimal parses a decimal string (e.g., "100.50") exactly
+func parseDecimal(value string) (*big.Rat, error) {
+	if value == "" {
+		return new(big.Rat), nil
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal: %q", value)
+	}
+	return r, nil
+}
+
+// statementWriter encodes statement records and sends them in chunks of about statementChunkSize bytes
+type statementWriter struct {
+	buf  bytes.Buffer
+	csv  *csv.Writer // nil for JSON Lines
+	send func(data []byte) error
+}
+
+// newStatementWriter creates a writer for the given format; CSV is used by default
+func newStatementWriter(format pb.StatementFormat, send func(data []byte) error) *statementWriter {
+	w := &statementWriter{send: send}
+	if format != pb.StatementFormat_STATEMENT_FORMAT_JSONL {
+		w.csv = csv.NewWriter(&w.buf)
+		// Writing to a bytes.Buffer cannot fail
+		_ = w.csv.Write(statementCSVHeader)
+	}
+	return w
+}
+
+// write encodes the record and sends a chunk once enough data is buffered
+func (w *statementWriter) write(record statementRecord) error {
+	if w.csv != nil {
+		if err := w.csv.Write(record.csvFields()); err != nil {
+			return fmt.Errorf("failed to encode CSV record: %w", err)
+		}
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return fmt.Errorf("failed to encode CSV record: %w", err)
+		}
+	} else if err := json.NewEncoder(&w.buf).Encode(record); err != nil {
+		return fmt.Errorf("failed to encode JSON record: %w", err)
+	}
+
+	if w.buf.Len() >= statementChunkSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush sends the buffered data, if any
+func (w *statementWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	if err := w.send(bytes.Clone(w.buf.Bytes())); err != nil {
+		return fmt.Errorf("failed to send statement chunk: %w", err)
+	}
+	w.buf.Reset()
+	return nil
+}
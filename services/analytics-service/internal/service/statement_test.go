@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/proto/analytics.v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockStatementStream collects the chunks sent by ExportAccountStatement
+type mockStatementStream struct {
+	grpc.ServerStream
+	chunks [][]byte
+}
+
+func (m *mockStatementStream) Send(chunk *pb.StatementChunk) error {
+	m.chunks = append(m.chunks, chunk.Data)
+	return nil
+}
+
+func (m *mockStatementStream) Context() context.Context {
+	return context.Background()
+}
+
+func (m *mockStatementStream) data() string {
+	return string(bytes.Join(m.chunks, nil))
+}
+
+// statementOperations returns a top-up, an incoming and an outgoing transfer of acc-1
+func statementOperations() []*models.Operation {
+	return []*models.Operation{
+		{
+			ID:            "op-1",
+			AccountID:     "acc-1",
+			OperationType: models.OperationTypeTopup,
+			Timestamp:     time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC),
+			Amount:        models.Amount{Value: "100.10", CurrencyCode: "RUB"},
+		},
+		{
+			ID:            "op-2",
+			AccountID:     "acc-1",
+			OperationType: models.OperationTypeTransfer,
+			Timestamp:     time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC),
+			Amount:        models.Amount{Value: "0.2", CurrencyCode: "RUB"},
+			SenderID:      "acc-2",
+			RecipientID:   "acc-1",
+		},
+		{
+			ID:            "op-3",
+			AccountID:     "acc-1",
+			OperationType: models.OperationTypeTransfer,
+			Timestamp:     time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC),
+			Amount:        models.Amount{Value: "50.00", CurrencyCode: "RUB"},
+			SenderID:      "acc-1",
+			RecipientID:   "acc-3",
+		},
+	}
+}
+
+func statementRequest(format pb.StatementFormat) *pb.ExportAccountStatementRequest {
+	return &pb.ExportAccountStatementRequest{
+		AccountId: "acc-1",
+		From:      "2025-11-01T00:00:00Z",
+		To:        "2025-12-01T00:00:00Z",
+		Format:    format,
+	}
+}
+
+func TestExportAccountStatement_CSV(t *testing.T) {
+	mockRepo := &MockOperationRepository{
+		operations: statementOperations(),
+		balance:    models.Amount{Value: "10.5", CurrencyCode: "RUB"},
+	}
+	service := NewAnalyticsService(mockRepo)
+	stream := &mockStatementStream{}
+
+	if err := service.ExportAccountStatement(statementRequest(pb.StatementFormat_STATEMENT_FORMAT_CSV), stream); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"record_type,operation_id,timestamp,operation_type,counterparty_id,amount,currency_code,balance,total_in,total_out",
+		"OPENING_BALANCE,,2025-11-01T00:00:00.000Z,,,,RUB,10.50,0.00,0.00",
+		"OPERATION,op-1,2025-11-01T10:00:00.000Z,TOPUP,,100.10,RUB,110.60,100.10,0.00",
+		"OPERATION,op-2,2025-11-02T10:00:00.000Z,TRANSFER,acc-2,0.20,RUB,110.80,100.30,0.00",
+		"OPERATION,op-3,2025-11-03T10:00:00.000Z,TRANSFER,acc-3,-50.00,RUB,60.80,100.30,50.00",
+		"CLOSING_BALANCE,,2025-12-01T00:00:00.000Z,,,,RUB,60.80,100.30,50.00",
+		"",
+	}, "\n")
+
+	if got := stream.data(); got != expected {
+		t.Errorf("unexpected statement:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestExportAccountStatement_JSONL(t *testing.T) {
+	mockRepo := &MockOperationRepository{operations: statementOperations()}
+	service := NewAnalyticsService(mockRepo)
+	stream := &mockStatementStream{}
+
+	if err := service.ExportAccountStatement(statementRequest(pb.StatementFormat_STATEMENT_FORMAT_JSONL), stream); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stream.data(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 records, got %d", len(lines))
+	}
+
+	var opening, last, closing statementRecord
+	for i, target := range map[int]*statementRecord{0: &opening, 3: &last, 4: &closing} {
+		if err := json.Unmarshal([]byte(lines[i]), target); err != nil {
+			t.Fatalf("failed to decode record %d: %v", i, err)
+		}
+	}
+
+	// Without earlier operations the opening balance is zero and the currency comes from the operations
+	if opening.RecordType != recordTypeOpeningBalance || opening.Balance != "0.00" {
+		t.Errorf("unexpected opening record: %+v", opening)
+	}
+	if last.OperationID != "op-3" || last.Amount != "-50.00" || last.CounterpartyID != "acc-3" {
+		t.Errorf("unexpected operation record: %+v", last)
+	}
+	if closing.RecordType != recordTypeClosingBalance || closing.Balance != "50.30" || closing.CurrencyCode != "RUB" {
+		t.Errorf("unexpected closing record: %+v", closing)
+	}
+}
+
+func TestExportAccountStatement_Chunking(t *testing.T) {
+	// Enough operations to exceed several chunks
+	var operations []*models.Operation
+	for i := 0; i < 2000; i++ {
+		operations = append(operations, &models.Operation{
+			ID:            fmt.Sprintf("op-%d", i),
+			AccountID:     "acc-1",
+			OperationType: models.OperationTypeTopup,
+			Timestamp:     time.Date(2025, 11, 1, 0, 0, i, 0, time.UTC),
+			Amount:        models.Amount{Value: "0.01", CurrencyCode: "RUB"},
+		})
+	}
+
+	service := NewAnalyticsService(&MockOperationRepository{operations: operations})
+	stream := &mockStatementStream{}
+
+	if err := service.ExportAccountStatement(statementRequest(pb.StatementFormat_STATEMENT_FORMAT_UNSPECIFIED), stream); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stream.chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(stream.chunks))
+	}
+	for i, chunk := range stream.chunks[:len(stream.chunks)-1] {
+		if len(chunk) < statementChunkSize {
+			t.Errorf("chunk %d is smaller than the chunk size: %d", i, len(chunk))
+		}
+	}
+	if !strings.HasSuffix(stream.data(), "CLOSING_BALANCE,,2025-12-01T00:00:00.000Z,,,,RUB,20.00,20.00,0.00\n") {
+		t.Error("expected exact closing balance of 20.00 after 2000 operations of 0.01")
+	}
+}
+
+func TestExportAccountStatement_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *pb.ExportAccountStatementRequest)
+	}{
+		{"missing account_id", func(req *pb.ExportAccountStatementRequest) { req.AccountId = "" }},
+		{"invalid from", func(req *pb.ExportAccountStatementRequest) { req.From = "2025-11-01" }},
+		{"invalid to", func(req *pb.ExportAccountStatementRequest) { req.To = "" }},
+		{"from after to", func(req *pb.ExportAccountStatementRequest) { req.From, req.To = req.To, req.From }},
+		{"unknown format", func(req *pb.ExportAccountStatementRequest) { req.Format = pb.StatementFormat(42) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAnalyticsService(&MockOperationRepository{})
+			req := statementRequest(pb.StatementFormat_STATEMENT_FORMAT_CSV)
+			tt.modify(req)
+
+			err := service.ExportAccountStatement(req, &mockStatementStream{})
+
+			st, ok := status.FromError(err)
+			if !ok || st.Code() != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
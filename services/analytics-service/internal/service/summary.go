@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/proto/analytics.v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultTopCounterpartiesLimit is used when the request does not specify a limit
+	defaultTopCounterpartiesLimit = 5
+
+	// maxTopCounterpartiesLimit is the maximum number of counterparties returned
+	maxTopCounterpartiesLimit = 50
+)
+
+// GetAccountSummary returns aggregated statistics of an account for a time range.
+// All aggregation is done by ClickHouse; the service only converts the results.
+func (s *AnalyticsService) GetAccountSummary(
+	ctx context.Context,
+	req *pb.GetAccountSummaryRequest,
+) (*pb.GetAccountSummaryResponse, error) {
+	// Validate request
+	from, to, bucket, err := s.validateSummaryRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.TopCounterpartiesLimit
+	if limit == 0 {
+		limit = defaultTopCounterpartiesLimit
+	}
+	if limit > maxTopCounterpartiesLimit {
+		limit = maxTopCounterpartiesLimit
+	}
+
+	total, err := s.repo.GetAccountSummary(ctx, req.AccountId, from, to)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account summary: %v", err)
+	}
+
+	resp := &pb.GetAccountSummaryResponse{
+		AccountId: req.AccountId,
+	}
+
+	resp.Total, err = convertSummaryToProto(total, total.CurrencyCode)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert summary: %v", err)
+	}
+
+	if bucket != models.SummaryBucketNone {
+		buckets, err := s.repo.GetAccountSummaryBuckets(ctx, req.AccountId, from, to, bucket)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get summary buckets: %v", err)
+		}
+
+		resp.Buckets = make([]*pb.SummaryBucketStats, 0, len(buckets))
+		for _, stats := range buckets {
+			summary, err := convertSummaryToProto(&stats.Summary, total.CurrencyCode)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to convert summary bucket: %v", err)
+			}
+			resp.Buckets = append(resp.Buckets, &pb.SummaryBucketStats{
+				Start:   stats.Start.Format("2006-01-02"),
+				Summary: summary,
+			})
+		}
+	}
+
+	counterparties, err := s.repo.GetTopCounterparties(ctx, req.AccountId, from, to, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get top counterparties: %v", err)
+	}
+
+	resp.TopCounterparties = make([]*pb.Counterparty, 0, len(counterparties))
+	for _, counterparty := range counterparties {
+		pbCounterparty, err := convertCounterpartyToProto(counterparty, total.CurrencyCode)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert counterparty: %v", err)
+		}
+		resp.TopCounterparties = append(resp.TopCounterparties, pbCounterparty)
+	}
+
+	return resp, nil
+}
+
+// validateSummaryRequest validates the GetAccountSummary request and returns the time range and bucket
+func (s *AnalyticsService) validateSummaryRequest(req *pb.GetAccountSummaryRequest) (time.Time, time.Time, models.SummaryBucket, error) {
+	if req.AccountId == "" {
+		return time.Time{}, time.Time{}, "", status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+	}
+
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, "", status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	if req.TopCounterpartiesLimit < 0 {
+		return time.Time{}, time.Time{}, "", status.Error(codes.InvalidArgument, "top_counterparties_limit cannot be negative")
+	}
+
+	var bucket models.SummaryBucket
+	switch req.Bucket {
+	case pb.SummaryBucket_SUMMARY_BUCKET_UNSPECIFIED:
+		bucket = models.SummaryBucketNone
+	case pb.SummaryBucket_SUMMARY_BUCKET_DAY:
+		bucket = models.SummaryBucketDay
+	case pb.SummaryBucket_SUMMARY_BUCKET_WEEK:
+		bucket = models.SummaryBucketWeek
+	case pb.SummaryBucket_SUMMARY_BUCKET_MONTH:
+		bucket = models.SummaryBucketMonth
+	default:
+		return time.Time{}, time.Time{}, "", status.Errorf(codes.InvalidArgument, "unsupported bucket: %v", req.Bucket)
+	}
+
+	return from, to, bucket, nil
+}
+
+// convertSummaryToProto converts a domain OperationsSummary to protobuf.
+// currencyCode of the whole range is used, since a bucket has the same currency.
+func convertSummaryToProto(summary *models.OperationsSummary, currencyCode string) (*pb.OperationsSummary, error) {
+	totalIn, err := formatDecimal(summary.TotalIn)
+	if err != nil {
+		return nil, err
+	}
+	totalOut, err := formatDecimal(summary.TotalOut)
+	if err != nil {
+		return nil, err
+	}
+	averageTransfer, err := formatDecimal(summary.AverageTransfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.OperationsSummary{
+		TotalIn:         &pb.Amount{Value: totalIn, CurrencyCode: currencyCode},
+		TotalOut:        &pb.Amount{Value: totalOut, CurrencyCode: currencyCode},
+		IncomingCount:   int64(summary.IncomingCount),
+		OutgoingCount:   int64(summary.OutgoingCount),
+		TopupCount:      int64(summary.TopUpCount),
+		TransferCount:   int64(summary.TransferCount),
+		AverageTransfer: &pb.Amount{Value: averageTransfer, CurrencyCode: currencyCode},
+	}, nil
+}
+
+// convertCounterpartyToProto converts a domain Counterparty to protobuf
+func convertCounterpartyToProto(counterparty *models.Counterparty, currencyCode string) (*pb.Counterparty, error) {
+	totalIn, err := formatDecimal(counterparty.TotalIn)
+	if err != nil {
+		return nil, err
+	}
+	totalOut, err := formatDecimal(counterparty.TotalOut)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Counterparty{
+		AccountId:     counterparty.AccountID,
+		TransferCount: int64(counterparty.TransferCount),
+		TotalIn:       &pb.Amount{Value: totalIn, CurrencyCode: currencyCode},
+		TotalOut:      &pb.Amount{Value: totalOut, CurrencyCode: currencyCode},
+	}, nil
+}
+
+// formatDecimal formats a decimal string returned by ClickHouse with exactly 2 decimal places.
+// ClickHouse drops trailing zeros (e.g., "150.5" instead of "150.50").
+func formatDecimal(value string) (string, error) {
+	r, err := parseDecimal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to format amount: %w", err)
+	}
+	return r.FloatString(2), nil
+}
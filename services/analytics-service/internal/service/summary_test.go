@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/proto/analytics.v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func summaryRequest(bucket pb.SummaryBucket) *pb.GetAccountSummaryRequest {
+	return &pb.GetAccountSummaryRequest{
+		AccountId: "acc-1",
+		From:      "2025-11-01T00:00:00Z",
+		To:        "2025-12-01T00:00:00Z",
+		Bucket:    bucket,
+	}
+}
+
+func TestGetAccountSummary_Success(t *testing.T) {
+	mockRepo := &MockOperationRepository{
+		summary: models.OperationsSummary{
+			TotalIn:         "150.5",
+			TotalOut:        "20",
+			IncomingCount:   2,
+			OutgoingCount:   1,
+			TopUpCount:      1,
+			TransferCount:   2,
+			AverageTransfer: "35.25",
+			CurrencyCode:    "RUB",
+		},
+		buckets: []*models.SummaryBucketStats{
+			{
+				Start:   time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC),
+				Summary: models.OperationsSummary{TotalIn: "150.5", TotalOut: "20", AverageTransfer: "35.25"},
+			},
+		},
+		counterparties: []*models.Counterparty{
+			{AccountID: "acc-2", TransferCount: 2, TotalIn: "50.5", TotalOut: "20"},
+		},
+	}
+	service := NewAnalyticsService(mockRepo)
+
+	resp, err := service.GetAccountSummary(context.Background(), summaryRequest(pb.SummaryBucket_SUMMARY_BUCKET_WEEK))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Total.TotalIn.Value != "150.50" || resp.Total.TotalOut.Value != "20.00" || resp.Total.AverageTransfer.Value != "35.25" {
+		t.Errorf("unexpected total: %+v", resp.Total)
+	}
+	if resp.Total.TotalIn.CurrencyCode != "RUB" || resp.Total.IncomingCount != 2 || resp.Total.TransferCount != 2 {
+		t.Errorf("unexpected total: %+v", resp.Total)
+	}
+
+	if mockRepo.lastBucket != models.SummaryBucketWeek {
+		t.Errorf("expected WEEK bucket, got %q", mockRepo.lastBucket)
+	}
+	if len(resp.Buckets) != 1 || resp.Buckets[0].Start != "2025-11-03" {
+		t.Fatalf("unexpected buckets: %+v", resp.Buckets)
+	}
+	if resp.Buckets[0].Summary.TotalIn.Value != "150.50" || resp.Buckets[0].Summary.TotalIn.CurrencyCode != "RUB" {
+		t.Errorf("unexpected bucket summary: %+v", resp.Buckets[0].Summary)
+	}
+
+	if mockRepo.lastLimit != defaultTopCounterpartiesLimit {
+		t.Errorf("expected default counterparties limit, got %d", mockRepo.lastLimit)
+	}
+	if len(resp.TopCounterparties) != 1 || resp.TopCounterparties[0].TotalIn.Value != "50.50" {
+		t.Errorf("unexpected counterparties: %+v", resp.TopCounterparties)
+	}
+}
+
+func TestGetAccountSummary_NoBuckets(t *testing.T) {
+	mockRepo := &MockOperationRepository{
+		summary: models.OperationsSummary{TotalIn: "0", TotalOut: "0", AverageTransfer: "0"},
+	}
+	service := NewAnalyticsService(mockRepo)
+
+	req := summaryRequest(pb.SummaryBucket_SUMMARY_BUCKET_UNSPECIFIED)
+	req.TopCounterpartiesLimit = 1000
+
+	resp, err := service.GetAccountSummary(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mockRepo.lastBucket != models.SummaryBucketNone || len(resp.Buckets) != 0 {
+		t.Errorf("expected no bucket query, got %q and %d buckets", mockRepo.lastBucket, len(resp.Buckets))
+	}
+	if mockRepo.lastLimit != maxTopCounterpartiesLimit {
+		t.Errorf("expected counterparties limit to be clamped to %d, got %d", maxTopCounterpartiesLimit, mockRepo.lastLimit)
+	}
+	if resp.Total.TotalIn.Value != "0.00" {
+		t.Errorf("expected zero total, got %s", resp.Total.TotalIn.Value)
+	}
+}
+
+func TestGetAccountSummary_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *pb.GetAccountSummaryRequest)
+	}{
+		{"missing account_id", func(req *pb.GetAccountSummaryRequest) { req.AccountId = "" }},
+		{"invalid from", func(req *pb.GetAccountSummaryRequest) { req.From = "yesterday" }},
+		{"from after to", func(req *pb.GetAccountSummaryRequest) { req.From, req.To = req.To, req.From }},
+		{"negative limit", func(req *pb.GetAccountSummaryRequest) { req.TopCounterpartiesLimit = -1 }},
+		{"unknown bucket", func(req *pb.GetAccountSummaryRequest) { req.Bucket = pb.SummaryBucket(42) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAnalyticsService(&MockOperationRepository{})
+			req := summaryRequest(pb.SummaryBucket_SUMMARY_BUCKET_DAY)
+			tt.modify(req)
+
+			_, err := service.GetAccountSummary(context.Background(), req)
+
+			st, ok := status.FromError(err)
+			if !ok || st.Code() != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
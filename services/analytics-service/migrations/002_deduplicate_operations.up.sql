@@ -0,0 +1,22 @@
+-- Recreate operations table as ReplacingMergeTree so that redelivered events are deduplicated.
+-- Rows with the same (account_id, timestamp, id) are collapsed on merge; queries use FINAL
+-- to get deduplicated results before the merge happens.
+CREATE TABLE IF NOT EXISTS operations_dedup (
+    id String,
+    account_id String,
+    operation_type Enum8('TOPUP' = 1, 'TRANSFER' = 2),
+    timestamp DateTime64(3),
+    amount_value Decimal(18, 2),
+    amount_currency String,
+    sender_id String,
+    recipient_id String,
+    created_at DateTime DEFAULT now()
+) ENGINE = ReplacingMergeTree()
+ORDER BY (account_id, timestamp, id)
+PRIMARY KEY (account_id, timestamp);
+
+INSERT INTO operations_dedup SELECT * FROM operations;
+
+RENAME TABLE operations TO operations_old, operations_dedup TO operations;
+
+DROP TABLE operations_old;
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations of the analytics-service ClickHouse database,
+// so that the service binary applies them without the migration files on disk.
+package migrations
+
+import "embed"
+
+// FS contains the NNN_name.up.sql and NNN_name.down.sql migration files.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -154,7 +154,7 @@ function apply_migration() {
     $cmd --query "INSERT INTO schema_migrations (version, dirty) VALUES ($version, 1)"
     
     # Apply migration
-    $cmd < "$file"
+    $cmd --multiquery < "$file"
     
     # Mark as clean
     $cmd --query "ALTER TABLE schema_migrations DELETE WHERE version = $version"
@@ -174,7 +174,7 @@ function rollback_migration() {
     $cmd --query "INSERT INTO schema_migrations (version, dirty) VALUES ($version, 1)"
     
     # Rollback migration
-    $cmd < "$file"
+    $cmd --multiquery < "$file"
     
     # Remove from tracking
     $cmd --query "ALTER TABLE schema_migrations DELETE WHERE version = $version"
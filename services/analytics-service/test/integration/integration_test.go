@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net"
 	"testing"
 	"time"
@@ -14,9 +15,11 @@ import (
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/db"
 	grpcserver "github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/grpc/server"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/messaging"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/migrate"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/models"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/repository"
 	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/internal/service"
+	"github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/migrations"
 	pb "github.com/spbu-ds-practicum-2025/example-project/services/analytics-service/proto/analytics.v1"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/clickhouse"
@@ -71,7 +74,7 @@ func TestFullIntegration(t *testing.T) {
 
 	// 1. Verify operation is stored in ClickHouse
 	t.Log("Step 1: Verifying operation is stored in ClickHouse")
-	operations, err := tc.repo.ListAccountOperations(tc.ctx, testSenderID, 10, "")
+	operations, _, err := tc.repo.ListAccountOperations(tc.ctx, models.ListOperationsQuery{AccountID: testSenderID, Limit: 10})
 	if err != nil {
 		t.Fatalf("Failed to query operations from ClickHouse: %v", err)
 	}
@@ -144,6 +147,364 @@ func TestFullIntegration(t *testing.T) {
 	t.Log("===== ✓ Integration test PASSED: RabbitMQ → ClickHouse → gRPC API =====")
 }
 
+func TestRedeliveredEventsAreDeduplicated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// ===== GIVEN: A transfer whose sender operation was stored before a failed delivery =====
+	tc, err := setupTestContext(t)
+	if err != nil {
+		t.Fatalf("Failed to setup test context: %v", err)
+	}
+	defer tc.cleanup()
+
+	senderID := uuid.New().String()
+	recipientID := uuid.New().String()
+	event := newTransferEvent(uuid.New().String(), uuid.New().String(), senderID, recipientID, uuid.New().String())
+
+	timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
+	if err != nil {
+		t.Fatalf("Failed to parse event timestamp: %v", err)
+	}
+
+	// Simulate a partial failure: only the sender side of the transfer was stored
+	err = tc.repo.InsertOperation(tc.ctx, &models.Operation{
+		ID:            event.OperationID,
+		AccountID:     senderID,
+		OperationType: models.OperationTypeTransfer,
+		Timestamp:     timestamp,
+		Amount:        event.Amount,
+		SenderID:      senderID,
+		RecipientID:   recipientID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert sender operation: %v", err)
+	}
+
+	// ===== WHEN: The same event is delivered twice =====
+	for i := 0; i < 2; i++ {
+		if err := publishEvent(tc.rabbitmqURL, event); err != nil {
+			t.Fatalf("Failed to publish event: %v", err)
+		}
+	}
+
+	// Wait for events to be processed
+	time.Sleep(3 * time.Second)
+
+	// ===== THEN: Each account has the operation exactly once =====
+	for _, accountID := range []string{senderID, recipientID} {
+		operations, _, err := tc.repo.ListAccountOperations(tc.ctx, models.ListOperationsQuery{AccountID: accountID, Limit: 10})
+		if err != nil {
+			t.Fatalf("Failed to query operations: %v", err)
+		}
+		if len(operations) != 1 {
+			t.Errorf("Expected 1 operation for account %s, got %d", accountID, len(operations))
+		}
+	}
+
+	from := timestamp.Add(-time.Hour)
+	to := timestamp.Add(time.Hour)
+
+	summary, err := tc.repo.GetAccountSummary(tc.ctx, senderID, from, to)
+	if err != nil {
+		t.Fatalf("Failed to get account summary: %v", err)
+	}
+	if summary.OutgoingCount != 1 {
+		t.Errorf("Expected 1 outgoing operation, got %d", summary.OutgoingCount)
+	}
+	if !equalDecimals(summary.TotalOut, event.Amount.Value) {
+		t.Errorf("Expected total out %s, got %s", event.Amount.Value, summary.TotalOut)
+	}
+
+	balance, err := tc.repo.GetAccountBalance(tc.ctx, recipientID, to)
+	if err != nil {
+		t.Fatalf("Failed to get account balance: %v", err)
+	}
+	if !equalDecimals(balance.Value, event.Amount.Value) {
+		t.Errorf("Expected recipient balance %s, got %s", event.Amount.Value, balance.Value)
+	}
+}
+
+// equalDecimals reports whether two decimal strings have the same value (e.g., "150.5" and "150.50")
+func equalDecimals(a, b string) bool {
+	x, okX := new(big.Rat).SetString(a)
+	y, okY := new(big.Rat).SetString(b)
+	return okX && okY && x.Cmp(y) == 0
+}
+
+func TestListAccountOperationsPagination(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// ===== GIVEN: An account with operations sharing timestamps =====
+	tc, err := setupTestContext(t)
+	if err != nil {
+		t.Fatalf("Failed to setup test context: %v", err)
+	}
+	defer tc.cleanup()
+
+	accountID := uuid.New().String()
+	base := time.Now().UTC().Truncate(time.Second)
+
+	var operations []*models.Operation
+	for i := 0; i < 7; i++ {
+		operations = append(operations, &models.Operation{
+			ID:            uuid.New().String(),
+			AccountID:     accountID,
+			OperationType: models.OperationTypeTopup,
+			// Pairs of operations have equal timestamps
+			Timestamp: base.Add(time.Duration(i/2) * time.Second),
+			Amount:    models.Amount{Value: "10.00", CurrencyCode: "RUB"},
+		})
+	}
+	if err := tc.repo.InsertOperations(tc.ctx, operations); err != nil {
+		t.Fatalf("Failed to insert operations: %v", err)
+	}
+
+	grpcClient, grpcConn := createGRPCClient(t, tc.grpcPort)
+	defer grpcConn.Close()
+
+	// ===== WHEN: All pages are read forward =====
+	var ids []string
+	var pages []*pb.ListAccountOperationsResponse
+	cursor := ""
+	for {
+		resp, err := grpcClient.ListAccountOperations(tc.ctx, &pb.ListAccountOperationsRequest{
+			AccountId: accountID,
+			Limit:     3,
+			Cursor:    cursor,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list operations: %v", err)
+		}
+		pages = append(pages, resp)
+		for _, op := range resp.Content {
+			ids = append(ids, op.Id)
+		}
+		if !resp.HasMore {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	// ===== THEN: Every operation is returned exactly once, most recent first =====
+	if len(pages) != 3 {
+		t.Errorf("Expected 3 pages, got %d", len(pages))
+	}
+	if len(ids) != len(operations) {
+		t.Fatalf("Expected %d operations, got %d: %v", len(operations), len(ids), ids)
+	}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("Operation %s returned twice", id)
+		}
+		seen[id] = true
+	}
+	for i := 1; i < len(pages[0].Content); i++ {
+		if pages[0].Content[i-1].Timestamp < pages[0].Content[i].Timestamp {
+			t.Errorf("Expected most recent operations first, got %v", pages[0].Content)
+		}
+	}
+
+	// Paging back from the second page returns the first page again
+	resp, err := grpcClient.ListAccountOperations(tc.ctx, &pb.ListAccountOperationsRequest{
+		AccountId: accountID,
+		Limit:     3,
+		Cursor:    pages[1].PrevCursor,
+		Direction: pb.PageDirection_PAGE_DIRECTION_BEFORE,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list previous page: %v", err)
+	}
+	if resp.HasMore {
+		t.Error("Expected no operations before the first page")
+	}
+	if len(resp.Content) != len(pages[0].Content) {
+		t.Fatalf("Expected %d operations on the previous page, got %d", len(pages[0].Content), len(resp.Content))
+	}
+	for i, op := range resp.Content {
+		if op.Id != pages[0].Content[i].Id {
+			t.Errorf("Expected operation %s at position %d, got %s", pages[0].Content[i].Id, i, op.Id)
+		}
+	}
+}
+
+func TestListAccountOperationsFilters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// ===== GIVEN: An account with top-ups and transfers to two counterparties =====
+	tc, err := setupTestContext(t)
+	if err != nil {
+		t.Fatalf("Failed to setup test context: %v", err)
+	}
+	defer tc.cleanup()
+
+	accountID := uuid.New().String()
+	counterpartyA := uuid.New().String()
+	counterpartyB := uuid.New().String()
+	base := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	newOperation := func(day int, opType models.OperationType, value, currency, senderID, recipientID string) *models.Operation {
+		return &models.Operation{
+			ID:            uuid.New().String(),
+			AccountID:     accountID,
+			OperationType: opType,
+			Timestamp:     base.AddDate(0, 0, day),
+			Amount:        models.Amount{Value: value, CurrencyCode: currency},
+			SenderID:      senderID,
+			RecipientID:   recipientID,
+		}
+	}
+
+	topUp := newOperation(0, models.OperationTypeTopup, "500.00", "RUB", "", "")
+	outgoingA := newOperation(1, models.OperationTypeTransfer, "50.00", "RUB", accountID, counterpartyA)
+	incomingA := newOperation(2, models.OperationTypeTransfer, "150.00", "RUB", counterpartyA, accountID)
+	outgoingB := newOperation(3, models.OperationTypeTransfer, "100.00", "RUB", accountID, counterpartyB)
+	topUpUSD := newOperation(4, models.OperationTypeTopup, "20.00", "USD", "", "")
+
+	operations := []*models.Operation{topUp, outgoingA, incomingA, outgoingB, topUpUSD}
+	if err := tc.repo.InsertOperations(tc.ctx, operations); err != nil {
+		t.Fatalf("Failed to insert operations: %v", err)
+	}
+
+	grpcClient, grpcConn := createGRPCClient(t, tc.grpcPort)
+	defer grpcConn.Close()
+
+	tests := []struct {
+		name     string
+		req      *pb.ListAccountOperationsRequest
+		expected []*models.Operation // most recent first
+	}{
+		{
+			name:     "operation type",
+			req:      &pb.ListAccountOperationsRequest{Type: pb.OperationType_TOPUP},
+			expected: []*models.Operation{topUpUSD, topUp},
+		},
+		{
+			name:     "date range",
+			req:      &pb.ListAccountOperationsRequest{From: "2025-10-02T12:00:00Z", To: "2025-10-04T12:00:00Z"},
+			expected: []*models.Operation{outgoingB, outgoingA},
+		},
+		{
+			name:     "amount range",
+			req:      &pb.ListAccountOperationsRequest{MinAmount: "50", MaxAmount: "150.00"},
+			expected: []*models.Operation{outgoingB, incomingA, outgoingA},
+		},
+		{
+			name:     "counterparty in both directions",
+			req:      &pb.ListAccountOperationsRequest{CounterpartyId: counterpartyA},
+			expected: []*models.Operation{incomingA, outgoingA},
+		},
+		{
+			name:     "currency",
+			req:      &pb.ListAccountOperationsRequest{CurrencyCode: "USD"},
+			expected: []*models.Operation{topUpUSD},
+		},
+		{
+			name:     "combined filters",
+			req:      &pb.ListAccountOperationsRequest{Type: pb.OperationType_TRANSFER, MinAmount: "100", CurrencyCode: "RUB"},
+			expected: []*models.Operation{outgoingB, incomingA},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ===== WHEN: Operations are listed with the filter =====
+			tt.req.AccountId = accountID
+			resp, err := grpcClient.ListAccountOperations(tc.ctx, tt.req)
+			if err != nil {
+				t.Fatalf("Failed to list operations: %v", err)
+			}
+
+			// ===== THEN: Only the matching operations are returned =====
+			if len(resp.Content) != len(tt.expected) {
+				t.Fatalf("Expected %d operations, got %d: %v", len(tt.expected), len(resp.Content), resp.Content)
+			}
+			for i, op := range resp.Content {
+				if op.Id != tt.expected[i].ID {
+					t.Errorf("Expected operation %s at position %d, got %s", tt.expected[i].ID, i, op.Id)
+				}
+			}
+		})
+	}
+}
+
+// TestMigrations applies and reverts the embedded migrations,
+// checking that every down migration reverts its up migration
+func TestMigrations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	clickhouseContainer, clickhouseHost, clickhousePassword, err := startClickHouseContainer(ctx)
+	if err != nil {
+		t.Fatalf("Failed to start ClickHouse: %v", err)
+	}
+	defer clickhouseContainer.Terminate(ctx)
+
+	clickhouseClient, err := db.NewClickHouseClient(config.ClickHouseConfig{
+		Host:     clickhouseHost,
+		Database: "default",
+		User:     "default",
+		Password: clickhousePassword,
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to ClickHouse: %v", err)
+	}
+	defer clickhouseClient.Close()
+
+	migrator, err := migrate.New(clickhouseClient.Conn(), migrations.FS)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	// Concurrent runs wait for each other and apply every migration once
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- migrator.Up(ctx) }()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Failed to apply migrations: %v", err)
+		}
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get migration status: %v", err)
+	}
+	if len(status.Pending) != 0 || status.Dirty {
+		t.Fatalf("Expected all migrations to be applied, got %+v", status)
+	}
+	latest := status.Version
+
+	if err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("Failed to revert migrations: %v", err)
+	}
+	var tables uint64
+	err = clickhouseClient.Conn().QueryRow(ctx,
+		"SELECT count() FROM system.tables WHERE database = currentDatabase() AND name = 'operations'",
+	).Scan(&tables)
+	if err != nil {
+		t.Fatalf("Failed to check operations table: %v", err)
+	}
+	if tables != 0 {
+		t.Error("Expected operations table to be dropped after reverting all migrations")
+	}
+
+	// Applying again after a full revert succeeds
+	if err := migrator.To(ctx, latest); err != nil {
+		t.Fatalf("Failed to apply migrations again: %v", err)
+	}
+}
+
 // testContext holds all the components needed for integration testing
 type testContext struct {
 	ctx                 context.Context
@@ -219,6 +580,16 @@ func setupTestContext(t *testing.T) (*testContext, error) {
 		Queue:      testQueue,
 		Exchange:   testExchange,
 		RoutingKey: testRoutingKey,
+
+		DeadLetterExchange: testExchange + ".dlx",
+		DeadLetterQueue:    testQueue + ".dlq",
+		RetryQueue:         testQueue + ".retry",
+		MaxRetries:         3,
+		RetryDelay:         time.Second,
+
+		PrefetchCount: 20,
+		BatchSize:     10,
+		FlushInterval: 200 * time.Millisecond,
 	}
 
 	consumer, err := messaging.NewRabbitMQConsumer(rabbitmqCfg, tc.repo)
@@ -302,24 +673,13 @@ func startRabbitMQContainer(ctx context.Context) (testcontainers.Container, stri
 	return rabbitmqContainer, connectionString, nil
 }
 
+// createSchema applies the service migrations, as the service does with MIGRATE_ON_START
 func createSchema(ctx context.Context, client *db.ClickHouseClient) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS operations (
-		id String,
-		account_id String,
-		operation_type Enum8('TOPUP' = 1, 'TRANSFER' = 2),
-		timestamp DateTime64(3),
-		amount_value Decimal(18, 2),
-		amount_currency String,
-		sender_id String,
-		recipient_id String,
-		created_at DateTime DEFAULT now()
-	) ENGINE = MergeTree()
-	ORDER BY (account_id, timestamp)
-	PRIMARY KEY (account_id, timestamp)
-	`
-
-	return client.Conn().Exec(ctx, query)
+	migrator, err := migrate.New(client.Conn(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	return migrator.Up(ctx)
 }
 
 func startGRPCServer(t *testing.T, repo *repository.OperationRepository) (*grpc.Server, string, error) {
@@ -350,21 +710,13 @@ func startGRPCServer(t *testing.T, repo *repository.OperationRepository) (*grpc.
 }
 
 func publishTransferEvent(rabbitmqURL, eventID, operationID, senderID, recipientID, idempotencyKey string) error {
-	conn, err := amqp.Dial(rabbitmqURL)
-	if err != nil {
-		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
-	}
-	defer conn.Close()
-
-	ch, err := conn.Channel()
-	if err != nil {
-		return fmt.Errorf("failed to open channel: %w", err)
-	}
-	defer ch.Close()
+	return publishEvent(rabbitmqURL, newTransferEvent(eventID, operationID, senderID, recipientID, idempotencyKey))
+}
 
-	// Create event according to AsyncAPI specification
+// newTransferEvent creates a transfer event of 150.50 RUB according to AsyncAPI specification
+func newTransferEvent(eventID, operationID, senderID, recipientID, idempotencyKey string) models.TransferCompletedEvent {
 	now := time.Now().Format(time.RFC3339)
-	event := models.TransferCompletedEvent{
+	return models.TransferCompletedEvent{
 		EventID:        eventID,
 		EventType:      "transfer.completed",
 		EventTimestamp: now,
@@ -379,6 +731,21 @@ func publishTransferEvent(rabbitmqURL, eventID, operationID, senderID, recipient
 		Status:         "SUCCESS",
 		Timestamp:      now,
 	}
+}
+
+// publishEvent publishes the event to the test exchange
+func publishEvent(rabbitmqURL string, event models.TransferCompletedEvent) error {
+	conn, err := amqp.Dial(rabbitmqURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
 
 	body, err := json.Marshal(event)
 	if err != nil {
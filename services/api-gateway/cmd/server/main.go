@@ -14,7 +14,13 @@ import (
 func main() {
 	// Get configuration from environment variables
 	bankServiceAddr := getEnv("BANK_SERVICE_ADDR", "localhost:50051")
-	analyticsServiceAddr := getEnv("ANALYTICS_SERVICE_ADDR", "localhost:50052")
+	analyticsServiceAddr := getEnv("ANALYTICS_SERVICE_ADDR", "localhost:50053")
+	cardAdapterAddr := getEnv("CARD_ADAPTER_ADDR", "localhost:50052")
+	// TOPUP_MODE selects how top-ups are processed:
+	// "card_adapter" charges the card through the bank card adapter,
+	// "direct" credits the account through BankService.TopUp without charging anything,
+	// e.g. for local development without the card adapter; requests must not contain card details
+	topUpMode := getEnv("TOPUP_MODE", "card_adapter")
 	port := getEnv("PORT", "8080")
 
 	// Create bank service client
@@ -31,11 +37,31 @@ func main() {
 	}
 	defer analyticsClient.Close()
 
+	// Create bank card adapter client unless top-ups go directly to the bank service
+	var cardAdapterClient *clients.CardAdapterClient
+	switch topUpMode {
+	case "card_adapter":
+		cardAdapterClient, err = clients.NewCardAdapterClient(cardAdapterAddr)
+		if err != nil {
+			log.Fatalf("Failed to create card adapter client: %v", err)
+		}
+		defer cardAdapterClient.Close()
+		log.Printf("Top-ups are processed by Bank Card Adapter at %s", cardAdapterAddr)
+	case "direct":
+		log.Printf("Top-ups are sent directly to Bank Service")
+	default:
+		log.Fatalf("Invalid TOPUP_MODE %q: must be card_adapter or direct", topUpMode)
+	}
+
 	// Create handler
-	handler := handlers.NewHandler(bankClient, analyticsClient)
+	handler := handlers.NewHandler(bankClient, analyticsClient, cardAdapterClient)
 
-	// Create HTTP server with generated router
-	httpHandler := server.Handler(handler)
+	// Create HTTP server with generated router and the health probes,
+	// which are served outside of the public API
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", handler.Livez)
+	mux.HandleFunc("/readyz", handler.Readyz)
+	mux.Handle("/", server.Handler(handler))
 
 	// Start server
 	addr := ":" + port
@@ -44,7 +70,7 @@ func main() {
 
 	httpServer := &http.Server{
 		Addr:    addr,
-		Handler: httpHandler,
+		Handler: mux,
 	}
 
 	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -48,6 +48,16 @@ func (c *AnalyticsClient) ListAccountOperations(ctx context.Context, req *analyt
 	return c.client.ListAccountOperations(ctx, req)
 }
 
+// ExportAccountStatement calls the ExportAccountStatement streaming RPC on the analytics service
+func (c *AnalyticsClient) ExportAccountStatement(ctx context.Context, req *analytics_v1.ExportAccountStatementRequest) (analytics_v1.AnalyticsService_ExportAccountStatementClient, error) {
+	return c.client.ExportAccountStatement(ctx, req)
+}
+
+// CheckHealth checks that the service is reachable and ready to serve calls
+func (c *AnalyticsClient) CheckHealth(ctx context.Context) error {
+	return checkHealth(ctx, c.conn, analytics_v1.AnalyticsService_ServiceDesc.ServiceName)
+}
+
 // Close closes the gRPC connection
 func (c *AnalyticsClient) Close() error {
 	return c.conn.Close()
@@ -58,6 +58,11 @@ func (c *BankClient) TopUp(ctx context.Context, req *bank_v1.TopUpRequest) (*ban
 	return c.client.TopUp(ctx, req)
 }
 
+// CheckHealth checks that the service is reachable and ready to serve calls
+func (c *BankClient) CheckHealth(ctx context.Context) error {
+	return checkHealth(ctx, c.conn, bank_v1.BankService_ServiceDesc.ServiceName)
+}
+
 // Close closes the gRPC connection
 func (c *BankClient) Close() error {
 	return c.conn.Close()
@@ -0,0 +1,59 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	bankcard_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/bankcard.v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// CardAdapterClient wraps the gRPC client for the Bank Card Adapter
+type CardAdapterClient struct {
+	client bankcard_v1.BankCardAdapterClient
+	conn   *grpc.ClientConn
+}
+
+// NewCardAdapterClient creates a new CardAdapterClient connected to the specified address
+func NewCardAdapterClient(cardAdapterAddr string) (*CardAdapterClient, error) {
+	conn, err := grpc.NewClient(
+		cardAdapterAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to bank card adapter: %w", err)
+	}
+
+	client := bankcard_v1.NewBankCardAdapterClient(conn)
+
+	return &CardAdapterClient{
+		client: client,
+		conn:   conn,
+	}, nil
+}
+
+// NewCardAdapterClientFromConn creates a new CardAdapterClient from an existing gRPC connection
+// This is useful for testing with mock servers
+func NewCardAdapterClientFromConn(conn *grpc.ClientConn) *CardAdapterClient {
+	client := bankcard_v1.NewBankCardAdapterClient(conn)
+	return &CardAdapterClient{
+		client: client,
+		conn:   conn,
+	}
+}
+
+// ProcessCardTopUp calls the ProcessCardTopUp RPC on the bank card adapter
+func (c *CardAdapterClient) ProcessCardTopUp(ctx context.Context, req *bankcard_v1.ProcessCardTopUpRequest) (*bankcard_v1.ProcessCardTopUpResponse, error) {
+	return c.client.ProcessCardTopUp(ctx, req)
+}
+
+// CheckHealth checks that the service is reachable and ready to serve calls
+func (c *CardAdapterClient) CheckHealth(ctx context.Context) error {
+	return checkHealth(ctx, c.conn, bankcard_v1.BankCardAdapter_ServiceDesc.ServiceName)
+}
+
+// Close closes the gRPC connection
+func (c *CardAdapterClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,21 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// checkHealth checks that the gRPC service is reachable and reports SERVING over the gRPC health checking protocol
+func checkHealth(ctx context.Context, conn *grpc.ClientConn, service string) error {
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return fmt.Errorf("health check of %s failed: %w", service, err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%s is %s", service, resp.GetStatus())
+	}
+	return nil
+}
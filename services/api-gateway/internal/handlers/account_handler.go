@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/models"
+	bank_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/bank.v1"
+	bankcard_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/bankcard.v1"
+)
+
+// directTopUpSource is the BankService top-up source used when the card adapter is bypassed.
+// No card is charged on that path, so the top-up must not be labelled as a card payment.
+const directTopUpSource = "direct"
+
+// GetAccount retrieves the account and its current balance
+func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request, accountId models.AccountIdParam) {
+	// Call bank service
+	grpcResp, err := h.bankClient.GetAccount(r.Context(), &bank_v1.GetAccountRequest{
+		AccountId: accountId.String(),
+	})
+	if err != nil {
+		handleGrpcError(w, err)
+		return
+	}
+
+	// Build response
+	accountID, err := uuid.Parse(grpcResp.AccountId)
+	if err != nil {
+		sendErrorResponse(w, http.StatusInternalServerError, "INVALID_RESPONSE", "Invalid account ID in response", err.Error())
+		return
+	}
+	if grpcResp.Balance == nil {
+		sendErrorResponse(w, http.StatusInternalServerError, "INVALID_RESPONSE", "Missing balance in response", "")
+		return
+	}
+
+	resp := models.Account{
+		AccountId: accountID,
+		Balance: models.Amount{
+			Value:        grpcResp.Balance.Value,
+			CurrencyCode: grpcResp.Balance.CurrencyCode,
+		},
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// TopUpAccount handles account top-up requests.
+// The card is charged through the bank card adapter, or, if no card adapter is configured
+// (TOPUP_MODE=direct), the account is credited directly through BankService.TopUp.
+// Direct top-ups charge nothing, so they do not accept card details.
+func (h *Handler) TopUpAccount(w http.ResponseWriter, r *http.Request, accountId models.AccountIdParam, params models.TopUpAccountParams) {
+	// Parse request body
+	var topUpReq models.TopUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&topUpReq); err != nil {
+		sendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Failed to parse request body", err.Error())
+		return
+	}
+
+	card, err := validateTopUpRequest(accountId, &topUpReq)
+	if err != nil {
+		sendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		return
+	}
+
+	if h.cardAdapterClient != nil && card == nil {
+		sendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", "method.cardDetails is required")
+		return
+	}
+	if h.cardAdapterClient == nil && card != nil {
+		sendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body",
+			"method.cardDetails is not accepted: top-ups are credited directly without charging a card")
+		return
+	}
+
+	var operationIDValue string
+	if h.cardAdapterClient != nil {
+		// Charge the card and credit the account through the card adapter
+		grpcResp, err := h.cardAdapterClient.ProcessCardTopUp(r.Context(), &bankcard_v1.ProcessCardTopUpRequest{
+			AccountId: accountId.String(),
+			Amount: &bankcard_v1.Amount{
+				Value:        topUpReq.Amount.Value,
+				CurrencyCode: topUpReq.Amount.CurrencyCode,
+			},
+			Card: &bankcard_v1.Card{
+				Number:     card.CardNumber,
+				ExpiryDate: card.ExpiryDate,
+				Cvv:        card.Cvv,
+			},
+			IdempotencyKey: params.XIdempotencyKey.String(),
+		})
+		if err != nil {
+			handleGrpcError(w, err)
+			return
+		}
+		operationIDValue = grpcResp.OperationId
+	} else {
+		// Credit the account directly through the bank service
+		grpcResp, err := h.bankClient.TopUp(r.Context(), &bank_v1.TopUpRequest{
+			AccountId: accountId.String(),
+			Amount: &bank_v1.Amount{
+				Value:        topUpReq.Amount.Value,
+				CurrencyCode: topUpReq.Amount.CurrencyCode,
+			},
+			IdempotencyKey: params.XIdempotencyKey.String(),
+			Source:         directTopUpSource,
+		})
+		if err != nil {
+			handleGrpcError(w, err)
+			return
+		}
+		operationIDValue = grpcResp.OperationId
+	}
+
+	// Build response
+	operationID, err := uuid.Parse(operationIDValue)
+	if err != nil {
+		sendErrorResponse(w, http.StatusInternalServerError, "INVALID_RESPONSE", "Invalid operation ID in response", err.Error())
+		return
+	}
+
+	resp := models.TopUpResponse{
+		OperationId: operationID,
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// cardDetails holds the bank card fields of a BankCard top-up method
+type cardDetails struct {
+	CardNumber string
+	ExpiryDate string
+	Cvv        string
+}
+
+// validateTopUpRequest validates the top-up request body and returns the card details,
+// or nil if the request has none. Whether card details are required depends on the top-up mode.
+// Card number, expiry date and CVV are only checked for presence here;
+// the card adapter validates their format.
+func validateTopUpRequest(accountId models.AccountIdParam, req *models.TopUpRequest) (*cardDetails, error) {
+	if req.AccountId != accountId {
+		return nil, errors.New("accountId in body does not match accountId in path")
+	}
+	if req.Amount.Value == "" {
+		return nil, errors.New("amount.value is required")
+	}
+	if req.Amount.CurrencyCode == "" {
+		return nil, errors.New("amount.currencyCode is required")
+	}
+	if req.Method.TopUpMethod != models.TopUpMethodTopUpMethodBankCard {
+		return nil, errors.New("unsupported method.topUpMethod: " + string(req.Method.TopUpMethod))
+	}
+
+	method, err := req.Method.AsCardTopUpMethod()
+	if err != nil {
+		return nil, errors.New("invalid method: " + err.Error())
+	}
+	if method.CardDetails == nil {
+		return nil, nil
+	}
+	if method.CardDetails.CardNumber == "" {
+		return nil, errors.New("method.cardDetails.cardNumber is required")
+	}
+	if method.CardDetails.ExpiryDate == "" {
+		return nil, errors.New("method.cardDetails.expiryDate is required")
+	}
+	if method.CardDetails.Cvv == "" {
+		return nil, errors.New("method.cardDetails.cvv is required")
+	}
+
+	return &cardDetails{
+		CardNumber: method.CardDetails.CardNumber,
+		ExpiryDate: method.CardDetails.ExpiryDate,
+		Cvv:        method.CardDetails.Cvv,
+	}, nil
+}
@@ -0,0 +1,370 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/clients"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/handlers"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/models"
+	bank_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/bank.v1"
+	bankcard_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/bankcard.v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// mockCardAdapter implements the BankCardAdapterServer for testing
+type mockCardAdapter struct {
+	bankcard_v1.UnimplementedBankCardAdapterServer
+	processCardTopUpFunc func(context.Context, *bankcard_v1.ProcessCardTopUpRequest) (*bankcard_v1.ProcessCardTopUpResponse, error)
+}
+
+func (m *mockCardAdapter) ProcessCardTopUp(ctx context.Context, req *bankcard_v1.ProcessCardTopUpRequest) (*bankcard_v1.ProcessCardTopUpResponse, error) {
+	if m.processCardTopUpFunc != nil {
+		return m.processCardTopUpFunc(ctx, req)
+	}
+	return &bankcard_v1.ProcessCardTopUpResponse{
+		OperationId: uuid.New().String(),
+		Status:      bankcard_v1.CardTopUpStatus_CARD_TOP_UP_STATUS_SUCCESS,
+		Message:     "Top-up completed successfully",
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}, nil
+}
+
+// setupMockCardAdapterServer creates a mock gRPC server for the bank card adapter
+func setupMockCardAdapterServer(t *testing.T, mockService *mockCardAdapter) (*grpc.Server, *bufconn.Listener) {
+	lis := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	bankcard_v1.RegisterBankCardAdapterServer(grpcServer, mockService)
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			t.Logf("Server exited with error: %v", err)
+		}
+	}()
+
+	return grpcServer, lis
+}
+
+// newCardAdapterHandler creates a Handler backed by the given mock card adapter
+func newCardAdapterHandler(t *testing.T, mockService *mockCardAdapter) *handlers.Handler {
+	grpcServer, lis := setupMockCardAdapterServer(t, mockService)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := createTestClient(context.Background(), lis)
+	if err != nil {
+		t.Fatalf("Failed to create test client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return handlers.NewHandler(nil, nil, clients.NewCardAdapterClientFromConn(conn))
+}
+
+// newBankHandler creates a Handler backed by the given mock bank service and no card adapter
+func newBankHandler(t *testing.T, mockService *mockBankService) *handlers.Handler {
+	grpcServer, lis := setupMockServer(t, mockService)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := createTestClient(context.Background(), lis)
+	if err != nil {
+		t.Fatalf("Failed to create test client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return handlers.NewHandler(clients.NewBankClientFromConn(conn), nil, nil)
+}
+
+// topUpBody builds a BankCard top-up request body
+func topUpBody(accountID uuid.UUID, value, cardNumber string) string {
+	return `{
+		"accountId": "` + accountID.String() + `",
+		"amount": {"value": "` + value + `", "currencyCode": "RUB"},
+		"method": {
+			"topUpMethod": "BankCard",
+			"cardDetails": {"cardNumber": "` + cardNumber + `", "expiryDate": "12/30", "cvv": "123"}
+		}
+	}`
+}
+
+func TestTopUpAccount_CardAdapter(t *testing.T) {
+	accountID := uuid.New()
+	idempotencyKey := uuid.New()
+	expectedOperationID := uuid.New()
+
+	handler := newCardAdapterHandler(t, &mockCardAdapter{
+		processCardTopUpFunc: func(ctx context.Context, req *bankcard_v1.ProcessCardTopUpRequest) (*bankcard_v1.ProcessCardTopUpResponse, error) {
+			if req.AccountId != accountID.String() {
+				t.Errorf("Expected account ID %s, got %s", accountID, req.AccountId)
+			}
+			if req.Amount.Value != "100.00" || req.Amount.CurrencyCode != "RUB" {
+				t.Errorf("Unexpected amount: %s %s", req.Amount.Value, req.Amount.CurrencyCode)
+			}
+			if req.Card.Number != "4111111111111111" || req.Card.ExpiryDate != "12/30" || req.Card.Cvv != "123" {
+				t.Errorf("Unexpected card: %+v", req.Card)
+			}
+			if req.IdempotencyKey != idempotencyKey.String() {
+				t.Errorf("Expected idempotency key %s, got %s", idempotencyKey, req.IdempotencyKey)
+			}
+			return &bankcard_v1.ProcessCardTopUpResponse{
+				OperationId: expectedOperationID.String(),
+				Status:      bankcard_v1.CardTopUpStatus_CARD_TOP_UP_STATUS_SUCCESS,
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID.String()+"/topups",
+		strings.NewReader(topUpBody(accountID, "100.00", "4111111111111111")))
+	w := httptest.NewRecorder()
+
+	handler.TopUpAccount(w, req, accountID, models.TopUpAccountParams{XIdempotencyKey: idempotencyKey})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp models.TopUpResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.OperationId != expectedOperationID {
+		t.Errorf("Expected operation ID %s, got %s", expectedOperationID, resp.OperationId)
+	}
+}
+
+// directTopUpBody builds a top-up request body without card details, as accepted in direct mode
+func directTopUpBody(accountID uuid.UUID, value string) string {
+	return `{
+		"accountId": "` + accountID.String() + `",
+		"amount": {"value": "` + value + `", "currencyCode": "RUB"},
+		"method": {"topUpMethod": "BankCard"}
+	}`
+}
+
+func TestTopUpAccount_DirectBankService(t *testing.T) {
+	accountID := uuid.New()
+	idempotencyKey := uuid.New()
+	expectedOperationID := uuid.New()
+
+	handler := newBankHandler(t, &mockBankService{
+		topUpFunc: func(ctx context.Context, req *bank_v1.TopUpRequest) (*bank_v1.TopUpResponse, error) {
+			if req.AccountId != accountID.String() {
+				t.Errorf("Expected account ID %s, got %s", accountID, req.AccountId)
+			}
+			if req.IdempotencyKey != idempotencyKey.String() {
+				t.Errorf("Expected idempotency key %s, got %s", idempotencyKey, req.IdempotencyKey)
+			}
+			if req.Source != "direct" {
+				t.Errorf("Expected source direct, got %s", req.Source)
+			}
+			return &bank_v1.TopUpResponse{
+				OperationId: expectedOperationID.String(),
+				Status:      bank_v1.TopUpStatus_TOP_UP_STATUS_SUCCESS,
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID.String()+"/topups",
+		strings.NewReader(directTopUpBody(accountID, "100.00")))
+	w := httptest.NewRecorder()
+
+	handler.TopUpAccount(w, req, accountID, models.TopUpAccountParams{XIdempotencyKey: idempotencyKey})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp models.TopUpResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.OperationId != expectedOperationID {
+		t.Errorf("Expected operation ID %s, got %s", expectedOperationID, resp.OperationId)
+	}
+}
+
+func TestTopUpAccount_InvalidRequest(t *testing.T) {
+	accountID := uuid.New()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed JSON", "{invalid json"},
+		{"account mismatch", topUpBody(uuid.New(), "100.00", "4111111111111111")},
+		{"missing amount value", topUpBody(accountID, "", "4111111111111111")},
+		{"missing card number", topUpBody(accountID, "100.00", "")},
+		{"unsupported method", `{"accountId": "` + accountID.String() + `", "amount": {"value": "1.00", "currencyCode": "RUB"}, "method": {"topUpMethod": "Cash"}}`},
+		{"missing card details", `{"accountId": "` + accountID.String() + `", "amount": {"value": "1.00", "currencyCode": "RUB"}, "method": {"topUpMethod": "BankCard"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The card adapter must not be called for invalid requests
+			handler := newCardAdapterHandler(t, &mockCardAdapter{
+				processCardTopUpFunc: func(ctx context.Context, req *bankcard_v1.ProcessCardTopUpRequest) (*bankcard_v1.ProcessCardTopUpResponse, error) {
+					t.Error("ProcessCardTopUp should not be called")
+					return nil, status.Error(codes.Internal, "unexpected call")
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID.String()+"/topups", bytes.NewReader([]byte(tt.body)))
+			w := httptest.NewRecorder()
+
+			handler.TopUpAccount(w, req, accountID, models.TopUpAccountParams{XIdempotencyKey: uuid.New()})
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status code %d, got %d. Body: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+
+			var errorResp models.BaseError
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errorResp.Code != "INVALID_REQUEST" {
+				t.Errorf("Expected error code INVALID_REQUEST, got %s", errorResp.Code)
+			}
+		})
+	}
+}
+
+func TestTopUpAccount_DirectRejectsCardDetails(t *testing.T) {
+	accountID := uuid.New()
+	// The account must not be credited when the request asks for a card to be charged
+	handler := newBankHandler(t, &mockBankService{
+		topUpFunc: func(ctx context.Context, req *bank_v1.TopUpRequest) (*bank_v1.TopUpResponse, error) {
+			t.Error("TopUp should not be called")
+			return nil, status.Error(codes.Internal, "unexpected call")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID.String()+"/topups",
+		strings.NewReader(topUpBody(accountID, "100.00", "4111111111111111")))
+	w := httptest.NewRecorder()
+
+	handler.TopUpAccount(w, req, accountID, models.TopUpAccountParams{XIdempotencyKey: uuid.New()})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d. Body: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+}
+
+func TestTopUpAccount_GrpcErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		grpcError      error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "CardDeclined",
+			grpcError:      status.Error(codes.FailedPrecondition, "card declined: insufficient_funds"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "FAILED_PRECONDITION",
+		},
+		{
+			name:           "AccountNotFound",
+			grpcError:      status.Error(codes.NotFound, "account not found"),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "NOT_FOUND",
+		},
+		{
+			name:           "BankUnavailable",
+			grpcError:      status.Error(codes.Unavailable, "bank service unavailable"),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   "UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newCardAdapterHandler(t, &mockCardAdapter{
+				processCardTopUpFunc: func(ctx context.Context, req *bankcard_v1.ProcessCardTopUpRequest) (*bankcard_v1.ProcessCardTopUpResponse, error) {
+					return nil, tt.grpcError
+				},
+			})
+
+			accountID := uuid.New()
+			req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID.String()+"/topups",
+				strings.NewReader(topUpBody(accountID, "100.00", "4000000000009995")))
+			w := httptest.NewRecorder()
+
+			handler.TopUpAccount(w, req, accountID, models.TopUpAccountParams{XIdempotencyKey: uuid.New()})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var errorResp models.BaseError
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errorResp.Code != tt.expectedCode {
+				t.Errorf("Expected error code %s, got %s", tt.expectedCode, errorResp.Code)
+			}
+		})
+	}
+}
+
+func TestGetAccount_Success(t *testing.T) {
+	accountID := uuid.New()
+
+	handler := newBankHandler(t, &mockBankService{
+		getAccountFunc: func(ctx context.Context, req *bank_v1.GetAccountRequest) (*bank_v1.GetAccountResponse, error) {
+			if req.AccountId != accountID.String() {
+				t.Errorf("Expected account ID %s, got %s", accountID, req.AccountId)
+			}
+			return &bank_v1.GetAccountResponse{
+				AccountId: req.AccountId,
+				Balance: &bank_v1.Amount{
+					Value:        "150.00",
+					CurrencyCode: "RUB",
+				},
+				Timestamp: time.Now().Format(time.RFC3339),
+				Status:    bank_v1.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String(), nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAccount(w, req, accountID)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp models.Account
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.AccountId != accountID {
+		t.Errorf("Expected account ID %s, got %s", accountID, resp.AccountId)
+	}
+	if resp.Balance.Value != "150.00" || resp.Balance.CurrencyCode != "RUB" {
+		t.Errorf("Expected balance 150.00 RUB, got %s %s", resp.Balance.Value, resp.Balance.CurrencyCode)
+	}
+}
+
+func TestGetAccount_NotFound(t *testing.T) {
+	handler := newBankHandler(t, &mockBankService{})
+
+	accountID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String(), nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAccount(w, req, accountID)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// readinessTimeout bounds the downstream health checks of a readiness probe
+const readinessTimeout = 2 * time.Second
+
+// HealthResponse is the body of the liveness and readiness probes.
+// Checks maps each downstream service to "ok" or the reason it is unavailable.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Livez reports that the gateway is running; it does not check the downstream services
+func (h *Handler) Livez(w http.ResponseWriter, r *http.Request) {
+	sendHealthResponse(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Readyz checks the configured downstream services concurrently
+// and responds with 503 Service Unavailable unless all of them are ready
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := h.downstreamChecks()
+	resp := HealthResponse{Status: "ok", Checks: make(map[string]string, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+			err := check(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				resp.Status = "unavailable"
+				resp.Checks[name] = err.Error()
+				return
+			}
+			resp.Checks[name] = "ok"
+		}(name, check)
+	}
+	wg.Wait()
+
+	statusCode := http.StatusOK
+	if resp.Status != "ok" {
+		statusCode = http.StatusServiceUnavailable
+	}
+	sendHealthResponse(w, statusCode, resp)
+}
+
+// downstreamChecks returns the health checks of the configured downstream services by name
+func (h *Handler) downstreamChecks() map[string]func(context.Context) error {
+	checks := make(map[string]func(context.Context) error)
+	if h.bankClient != nil {
+		checks["bank-service"] = h.bankClient.CheckHealth
+	}
+	if h.analyticsClient != nil {
+		checks["analytics-service"] = h.analyticsClient.CheckHealth
+	}
+	if h.cardAdapterClient != nil {
+		checks["bank-card-adapter"] = h.cardAdapterClient.CheckHealth
+	}
+	return checks
+}
+
+// sendHealthResponse writes a probe response; probes must never be cached
+func sendHealthResponse(w http.ResponseWriter, statusCode int, resp HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/clients"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/handlers"
+	analytics_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/analytics.v1"
+	bank_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/bank.v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupHealthServer creates a gRPC server reporting the given status for the service
+func setupHealthServer(t *testing.T, service string, status healthpb.HealthCheckResponse_ServingStatus) *grpc.ClientConn {
+	lis := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(service, status)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			t.Logf("Server exited with error: %v", err)
+		}
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := createTestClient(context.Background(), lis)
+	if err != nil {
+		t.Fatalf("Failed to create test client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestLivez(t *testing.T) {
+	handler := handlers.NewHandler(nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	w := httptest.NewRecorder()
+	handler.Livez(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		bankStatus     healthpb.HealthCheckResponse_ServingStatus
+		expectedStatus int
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "all services serving",
+			bankStatus:     healthpb.HealthCheckResponse_SERVING,
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"bank-service": "ok", "analytics-service": "ok"},
+		},
+		{
+			name:           "bank service not serving",
+			bankStatus:     healthpb.HealthCheckResponse_NOT_SERVING,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"bank-service": "bank.v1.BankService is NOT_SERVING", "analytics-service": "ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bankConn := setupHealthServer(t, bank_v1.BankService_ServiceDesc.ServiceName, tt.bankStatus)
+			analyticsConn := setupHealthServer(t, analytics_v1.AnalyticsService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+			handler := handlers.NewHandler(
+				clients.NewBankClientFromConn(bankConn),
+				clients.NewAnalyticsClientFromConn(analyticsConn),
+				nil,
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+			handler.Readyz(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp handlers.HealthResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Checks) != len(tt.expectedChecks) {
+				t.Errorf("Expected checks %v, got %v", tt.expectedChecks, resp.Checks)
+			}
+			for name, expected := range tt.expectedChecks {
+				if resp.Checks[name] != expected {
+					t.Errorf("Expected %s check to be %q, got %q", name, expected, resp.Checks[name])
+				}
+			}
+		})
+	}
+}
+
+func TestReadyz_ServiceWithoutHealthService(t *testing.T) {
+	// The mock bank service does not register the health service
+	grpcServer, lis := setupMockServer(t, &mockBankService{})
+	t.Cleanup(grpcServer.Stop)
+	conn, err := createTestClient(context.Background(), lis)
+	if err != nil {
+		t.Fatalf("Failed to create test client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	handler := handlers.NewHandler(clients.NewBankClientFromConn(conn), nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	w := httptest.NewRecorder()
+	handler.Readyz(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/models"
+	analytics_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/analytics.v1"
+)
+
+// GetAccountStatement streams the account statement exported by the analytics service
+func (h *Handler) GetAccountStatement(w http.ResponseWriter, r *http.Request, accountId models.AccountIdParam, params models.GetAccountStatementParams) {
+	// Map the requested format, CSV by default
+	format := analytics_v1.StatementFormat_STATEMENT_FORMAT_CSV
+	contentType := "text/csv; charset=utf-8"
+	extension := "csv"
+	if params.Format != nil {
+		switch string(*params.Format) {
+		case "csv":
+		case "jsonl":
+			format = analytics_v1.StatementFormat_STATEMENT_FORMAT_JSONL
+			contentType = "application/x-ndjson"
+			extension = "jsonl"
+		default:
+			sendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request parameters", "unsupported format: "+string(*params.Format))
+			return
+		}
+	}
+
+	// Call analytics service; the stream is cancelled if the client disconnects
+	stream, err := h.analyticsClient.ExportAccountStatement(r.Context(), &analytics_v1.ExportAccountStatementRequest{
+		AccountId: accountId.String(),
+		From:      params.From.Format(time.RFC3339Nano),
+		To:        params.To.Format(time.RFC3339Nano),
+		Format:    format,
+	})
+	if err != nil {
+		handleGrpcError(w, err)
+		return
+	}
+
+	// Receive the first chunk before writing the headers,
+	// so that validation errors are still returned with a proper status code
+	chunk, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		handleGrpcError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s.%s"`, accountId, extension))
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	for err == nil {
+		if _, writeErr := w.Write(chunk.Data); writeErr != nil {
+			log.Printf("Failed to write statement for account %s: %v", accountId, writeErr)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		chunk, err = stream.Recv()
+	}
+
+	// Headers are already sent, so a failure in the middle of the stream can only be logged
+	if !errors.Is(err, io.EOF) {
+		log.Printf("Statement export for account %s was interrupted: %v", accountId, err)
+	}
+}
@@ -0,0 +1,163 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/clients"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/handlers"
+	"github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/internal/models"
+	analytics_v1 "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/proto/analytics.v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newAnalyticsHandler creates a Handler backed by the given mock analytics service
+func newAnalyticsHandler(t *testing.T, mockService *mockAnalyticsService) *handlers.Handler {
+	grpcServer, lis := setupMockAnalyticsServer(t, mockService)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := createTestClient(context.Background(), lis)
+	if err != nil {
+		t.Fatalf("Failed to create test client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return handlers.NewHandler(nil, clients.NewAnalyticsClientFromConn(conn), nil)
+}
+
+func TestGetAccountStatement_StreamsChunks(t *testing.T) {
+	accountID := uuid.New()
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	handler := newAnalyticsHandler(t, &mockAnalyticsService{
+		exportAccountStatementFunc: func(req *analytics_v1.ExportAccountStatementRequest, stream analytics_v1.AnalyticsService_ExportAccountStatementServer) error {
+			if req.AccountId != accountID.String() {
+				t.Errorf("Expected account ID %s, got %s", accountID, req.AccountId)
+			}
+			if req.From != "2025-10-01T00:00:00Z" || req.To != "2025-11-01T00:00:00Z" {
+				t.Errorf("Unexpected range: %s - %s", req.From, req.To)
+			}
+			if req.Format != analytics_v1.StatementFormat_STATEMENT_FORMAT_JSONL {
+				t.Errorf("Expected JSONL format, got %v", req.Format)
+			}
+			for _, data := range []string{`{"recordType":"OPENING_BALANCE"}` + "\n", `{"recordType":"CLOSING_BALANCE"}` + "\n"} {
+				if err := stream.Send(&analytics_v1.StatementChunk{Data: []byte(data)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	format := models.GetAccountStatementParamsFormat("jsonl")
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/statement", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAccountStatement(w, req, accountID, models.GetAccountStatementParams{From: from, To: to, Format: &format})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Expected Content-Type application/x-ndjson, got %s", contentType)
+	}
+
+	expected := `{"recordType":"OPENING_BALANCE"}` + "\n" + `{"recordType":"CLOSING_BALANCE"}` + "\n"
+	if w.Body.String() != expected {
+		t.Errorf("Expected body %q, got %q", expected, w.Body.String())
+	}
+}
+
+func TestGetAccountStatement_DefaultsToCSV(t *testing.T) {
+	handler := newAnalyticsHandler(t, &mockAnalyticsService{
+		exportAccountStatementFunc: func(req *analytics_v1.ExportAccountStatementRequest, stream analytics_v1.AnalyticsService_ExportAccountStatementServer) error {
+			if req.Format != analytics_v1.StatementFormat_STATEMENT_FORMAT_CSV {
+				t.Errorf("Expected CSV format, got %v", req.Format)
+			}
+			return stream.Send(&analytics_v1.StatementChunk{Data: []byte("record_type\n")})
+		},
+	})
+
+	accountID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/statement", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAccountStatement(w, req, accountID, models.GetAccountStatementParams{
+		From: time.Now().Add(-time.Hour),
+		To:   time.Now(),
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/csv; charset=utf-8" {
+		t.Errorf("Expected CSV Content-Type, got %s", contentType)
+	}
+	if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename="statement-`+accountID.String()+`.csv"` {
+		t.Errorf("Unexpected Content-Disposition: %s", disposition)
+	}
+}
+
+func TestGetAccountStatement_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         string
+		grpcError      error
+		expectedStatus int
+	}{
+		{
+			name:           "UnsupportedFormat",
+			format:         "pdf",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "InvalidRange",
+			format:         "csv",
+			grpcError:      status.Error(codes.InvalidArgument, "from must be before to"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Internal",
+			format:         "csv",
+			grpcError:      status.Error(codes.Internal, "failed to export operations"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newAnalyticsHandler(t, &mockAnalyticsService{
+				exportAccountStatementFunc: func(req *analytics_v1.ExportAccountStatementRequest, stream analytics_v1.AnalyticsService_ExportAccountStatementServer) error {
+					return tt.grpcError
+				},
+			})
+
+			accountID := uuid.New()
+			format := models.GetAccountStatementParamsFormat(tt.format)
+			req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/statement", nil)
+			w := httptest.NewRecorder()
+
+			handler.GetAccountStatement(w, req, accountID, models.GetAccountStatementParams{
+				From:   time.Now().Add(-time.Hour),
+				To:     time.Now(),
+				Format: &format,
+			})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var errorResp models.BaseError
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+		})
+	}
+}
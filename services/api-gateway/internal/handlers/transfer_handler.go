@@ -16,15 +16,19 @@ import (
 
 // Handler implements the server.ServerInterface
 type Handler struct {
-	bankClient      *clients.BankClient
-	analyticsClient *clients.AnalyticsClient
+	bankClient        *clients.BankClient
+	analyticsClient   *clients.AnalyticsClient
+	cardAdapterClient *clients.CardAdapterClient
 }
 
-// NewHandler creates a new Handler with the given bank and analytics clients
-func NewHandler(bankClient *clients.BankClient, analyticsClient *clients.AnalyticsClient) *Handler {
+// NewHandler creates a new Handler with the given bank, analytics and card adapter clients.
+// If cardAdapterClient is nil, top-ups are sent directly to BankService.TopUp
+// without charging the card.
+func NewHandler(bankClient *clients.BankClient, analyticsClient *clients.AnalyticsClient, cardAdapterClient *clients.CardAdapterClient) *Handler {
 	return &Handler{
-		bankClient:      bankClient,
-		analyticsClient: analyticsClient,
+		bankClient:        bankClient,
+		analyticsClient:   analyticsClient,
+		cardAdapterClient: cardAdapterClient,
 	}
 }
 
@@ -55,6 +59,12 @@ func (h *Handler) TransferBetweenAccounts(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// Declined transfers are returned by the bank service as FAILED with a failure reason
+	if grpcResp.Status == bank_v1.TransferStatus_TRANSFER_STATUS_FAILED {
+		handleTransferFailure(w, grpcResp)
+		return
+	}
+
 	// Build response
 	operationID, err := uuid.Parse(grpcResp.OperationId)
 	if err != nil {
@@ -72,11 +82,6 @@ func (h *Handler) TransferBetweenAccounts(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(resp)
 }
 
-// GetAccount is not implemented yet
-func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request, accountId models.AccountIdParam) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
-
 // GetAccountOperations retrieves the list of operations for a given account
 func (h *Handler) GetAccountOperations(w http.ResponseWriter, r *http.Request, accountId models.AccountIdParam, params models.GetAccountOperationsParams) {
 	// Build gRPC request
@@ -89,9 +94,58 @@ func (h *Handler) GetAccountOperations(w http.ResponseWriter, r *http.Request, a
 		grpcReq.Limit = int32(*params.Limit)
 	}
 
-	// Add optional afterId parameter
-	if params.AfterId != nil {
-		grpcReq.AfterId = params.AfterId.String()
+	// Add optional cursor and direction parameters
+	if params.Cursor != nil {
+		grpcReq.Cursor = *params.Cursor
+	}
+
+	if params.Direction != nil {
+		switch *params.Direction {
+		case models.After:
+			grpcReq.Direction = analytics_v1.PageDirection_PAGE_DIRECTION_AFTER
+		case models.Before:
+			grpcReq.Direction = analytics_v1.PageDirection_PAGE_DIRECTION_BEFORE
+		default:
+			sendErrorResponse(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid request parameters", "direction must be after or before")
+			return
+		}
+	}
+
+	// Add optional filters
+	if params.Type != nil {
+		switch *params.Type {
+		case models.Topup:
+			grpcReq.Type = analytics_v1.OperationType_TOPUP
+		case models.Transfer:
+			grpcReq.Type = analytics_v1.OperationType_TRANSFER
+		default:
+			sendErrorResponse(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid request parameters", "type must be Topup or Transfer")
+			return
+		}
+	}
+
+	if params.From != nil {
+		grpcReq.From = params.From.Format(time.RFC3339Nano)
+	}
+
+	if params.To != nil {
+		grpcReq.To = params.To.Format(time.RFC3339Nano)
+	}
+
+	if params.MinAmount != nil {
+		grpcReq.MinAmount = *params.MinAmount
+	}
+
+	if params.MaxAmount != nil {
+		grpcReq.MaxAmount = *params.MaxAmount
+	}
+
+	if params.CounterpartyId != nil {
+		grpcReq.CounterpartyId = params.CounterpartyId.String()
+	}
+
+	if params.CurrencyCode != nil {
+		grpcReq.CurrencyCode = *params.CurrencyCode
 	}
 
 	// Call analytics service
@@ -147,16 +201,15 @@ func (h *Handler) GetAccountOperations(w http.ResponseWriter, r *http.Request, a
 	// Build response
 	resp := models.GetOperationsResponse{
 		Content: operations,
+		HasMore: grpcResp.HasMore,
 	}
 
-	// Add optional afterId in response
-	if grpcResp.AfterId != "" {
-		afterID, err := uuid.Parse(grpcResp.AfterId)
-		if err != nil {
-			sendErrorResponse(w, http.StatusInternalServerError, "INVALID_RESPONSE", "Invalid after ID in response", err.Error())
-			return
-		}
-		resp.AfterId = &afterID
+	// Add optional cursors in response
+	if grpcResp.NextCursor != "" {
+		resp.NextCursor = &grpcResp.NextCursor
+	}
+	if grpcResp.PrevCursor != "" {
+		resp.PrevCursor = &grpcResp.PrevCursor
 	}
 
 	// Send response
@@ -165,11 +218,6 @@ func (h *Handler) GetAccountOperations(w http.ResponseWriter, r *http.Request, a
 	json.NewEncoder(w).Encode(resp)
 }
 
-// TopUpAccount is not implemented yet
-func (h *Handler) TopUpAccount(w http.ResponseWriter, r *http.Request, accountId models.AccountIdParam, params models.TopUpAccountParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
-
 // handleGrpcError converts gRPC errors to HTTP responses
 func handleGrpcError(w http.ResponseWriter, err error) {
 	st, ok := status.FromError(err)
@@ -187,11 +235,30 @@ func handleGrpcError(w http.ResponseWriter, err error) {
 		sendErrorResponse(w, http.StatusBadRequest, "FAILED_PRECONDITION", "Operation cannot be performed", st.Message())
 	case codes.AlreadyExists:
 		sendErrorResponse(w, http.StatusConflict, "ALREADY_EXISTS", "Resource already exists", st.Message())
+	case codes.Aborted:
+		// A concurrent update aborted the operation; retrying with the same idempotency key is safe
+		sendErrorResponse(w, http.StatusConflict, "ABORTED", "Operation aborted by a concurrent update, retry the request", st.Message())
+	case codes.Unavailable:
+		sendErrorResponse(w, http.StatusServiceUnavailable, "UNAVAILABLE", "Service temporarily unavailable", st.Message())
 	default:
 		sendErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An internal error occurred", st.Message())
 	}
 }
 
+// handleTransferFailure converts a FAILED transfer to an HTTP error response
+// the same way as the gRPC error the bank service used to return for it
+func handleTransferFailure(w http.ResponseWriter, resp *bank_v1.TransferMoneyResponse) {
+	switch resp.FailureReason {
+	case bank_v1.TransferFailureReason_TRANSFER_FAILURE_REASON_INSUFFICIENT_FUNDS,
+		bank_v1.TransferFailureReason_TRANSFER_FAILURE_REASON_ACCOUNT_NOT_ACTIVE:
+		sendErrorResponse(w, http.StatusBadRequest, "FAILED_PRECONDITION", "Operation cannot be performed", resp.Message)
+	case bank_v1.TransferFailureReason_TRANSFER_FAILURE_REASON_CURRENCY_MISMATCH:
+		sendErrorResponse(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid request parameters", resp.Message)
+	default:
+		sendErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An internal error occurred", resp.Message)
+	}
+}
+
 // sendErrorResponse sends an error response in the expected format
 func sendErrorResponse(w http.ResponseWriter, statusCode int, code, description, details string) {
 	errorResp := models.BaseError{
@@ -29,6 +29,8 @@ const bufSize = 1024 * 1024
 type mockBankService struct {
 	bank_v1.UnimplementedBankServiceServer
 	transferMoneyFunc func(context.Context, *bank_v1.TransferMoneyRequest) (*bank_v1.TransferMoneyResponse, error)
+	getAccountFunc    func(context.Context, *bank_v1.GetAccountRequest) (*bank_v1.GetAccountResponse, error)
+	topUpFunc         func(context.Context, *bank_v1.TopUpRequest) (*bank_v1.TopUpResponse, error)
 }
 
 func (m *mockBankService) TransferMoney(ctx context.Context, req *bank_v1.TransferMoneyRequest) (*bank_v1.TransferMoneyResponse, error) {
@@ -43,10 +45,30 @@ func (m *mockBankService) TransferMoney(ctx context.Context, req *bank_v1.Transf
 	}, nil
 }
 
+func (m *mockBankService) GetAccount(ctx context.Context, req *bank_v1.GetAccountRequest) (*bank_v1.GetAccountResponse, error) {
+	if m.getAccountFunc != nil {
+		return m.getAccountFunc(ctx, req)
+	}
+	return nil, status.Error(codes.NotFound, "account not found")
+}
+
+func (m *mockBankService) TopUp(ctx context.Context, req *bank_v1.TopUpRequest) (*bank_v1.TopUpResponse, error) {
+	if m.topUpFunc != nil {
+		return m.topUpFunc(ctx, req)
+	}
+	return &bank_v1.TopUpResponse{
+		OperationId: uuid.New().String(),
+		Status:      bank_v1.TopUpStatus_TOP_UP_STATUS_SUCCESS,
+		Message:     "Top-up completed successfully",
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}, nil
+}
+
 // mockAnalyticsService implements the AnalyticsServiceServer for testing
 type mockAnalyticsService struct {
 	analytics_v1.UnimplementedAnalyticsServiceServer
-	listAccountOperationsFunc func(context.Context, *analytics_v1.ListAccountOperationsRequest) (*analytics_v1.ListAccountOperationsResponse, error)
+	listAccountOperationsFunc  func(context.Context, *analytics_v1.ListAccountOperationsRequest) (*analytics_v1.ListAccountOperationsResponse, error)
+	exportAccountStatementFunc func(*analytics_v1.ExportAccountStatementRequest, analytics_v1.AnalyticsService_ExportAccountStatementServer) error
 }
 
 func (m *mockAnalyticsService) ListAccountOperations(ctx context.Context, req *analytics_v1.ListAccountOperationsRequest) (*analytics_v1.ListAccountOperationsResponse, error) {
@@ -55,10 +77,16 @@ func (m *mockAnalyticsService) ListAccountOperations(ctx context.Context, req *a
 	}
 	return &analytics_v1.ListAccountOperationsResponse{
 		Content: []*analytics_v1.Operation{},
-		AfterId: "",
 	}, nil
 }
 
+func (m *mockAnalyticsService) ExportAccountStatement(req *analytics_v1.ExportAccountStatementRequest, stream analytics_v1.AnalyticsService_ExportAccountStatementServer) error {
+	if m.exportAccountStatementFunc != nil {
+		return m.exportAccountStatementFunc(req, stream)
+	}
+	return nil
+}
+
 // setupMockServer creates a mock gRPC server for testing
 func setupMockServer(t *testing.T, mockService *mockBankService) (*grpc.Server, *bufconn.Listener) {
 	lis := bufconn.Listen(bufSize)
@@ -145,7 +173,7 @@ func TestTransferBetweenAccounts_Success(t *testing.T) {
 
 	// Create bank client wrapper using the test connection
 	bankClient := clients.NewBankClientFromConn(conn)
-	handler := handlers.NewHandler(bankClient, nil)
+	handler := handlers.NewHandler(bankClient, nil, nil)
 
 	// Create test HTTP request
 	senderID := uuid.New()
@@ -203,7 +231,7 @@ func TestTransferBetweenAccounts_InvalidRequest(t *testing.T) {
 	defer conn.Close()
 
 	bankClient := clients.NewBankClientFromConn(conn)
-	handler := handlers.NewHandler(bankClient, nil)
+	handler := handlers.NewHandler(bankClient, nil, nil)
 
 	senderID := uuid.New()
 	idempotencyKey := uuid.New()
@@ -264,6 +292,12 @@ func TestTransferBetweenAccounts_GrpcErrors(t *testing.T) {
 			expectedStatus: http.StatusConflict,
 			expectedCode:   "ALREADY_EXISTS",
 		},
+		{
+			name:           "Aborted",
+			grpcError:      status.Error(codes.Aborted, "transaction aborted due to a concurrent update"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "ABORTED",
+		},
 		{
 			name:           "Internal",
 			grpcError:      status.Error(codes.Internal, "internal server error"),
@@ -291,7 +325,7 @@ func TestTransferBetweenAccounts_GrpcErrors(t *testing.T) {
 			defer conn.Close()
 
 			bankClient := clients.NewBankClientFromConn(conn)
-			handler := handlers.NewHandler(bankClient, nil)
+			handler := handlers.NewHandler(bankClient, nil, nil)
 
 			senderID := uuid.New()
 			recipientID := uuid.New()
@@ -338,6 +372,106 @@ func TestTransferBetweenAccounts_GrpcErrors(t *testing.T) {
 	}
 }
 
+func TestTransferBetweenAccounts_Failed(t *testing.T) {
+	tests := []struct {
+		name           string
+		failureReason  bank_v1.TransferFailureReason
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "InsufficientFunds",
+			failureReason:  bank_v1.TransferFailureReason_TRANSFER_FAILURE_REASON_INSUFFICIENT_FUNDS,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "FAILED_PRECONDITION",
+		},
+		{
+			name:           "AccountNotActive",
+			failureReason:  bank_v1.TransferFailureReason_TRANSFER_FAILURE_REASON_ACCOUNT_NOT_ACTIVE,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "FAILED_PRECONDITION",
+		},
+		{
+			name:           "CurrencyMismatch",
+			failureReason:  bank_v1.TransferFailureReason_TRANSFER_FAILURE_REASON_CURRENCY_MISMATCH,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_ARGUMENT",
+		},
+		{
+			name:           "Internal",
+			failureReason:  bank_v1.TransferFailureReason_TRANSFER_FAILURE_REASON_INTERNAL,
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   "INTERNAL_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockBankService{
+				transferMoneyFunc: func(ctx context.Context, req *bank_v1.TransferMoneyRequest) (*bank_v1.TransferMoneyResponse, error) {
+					return &bank_v1.TransferMoneyResponse{
+						OperationId:   uuid.New().String(),
+						Status:        bank_v1.TransferStatus_TRANSFER_STATUS_FAILED,
+						Message:       "Transfer declined",
+						Timestamp:     time.Now().Format(time.RFC3339),
+						FailureReason: tt.failureReason,
+					}, nil
+				},
+			}
+
+			grpcServer, lis := setupMockServer(t, mockService)
+			defer grpcServer.Stop()
+
+			ctx := context.Background()
+			conn, err := createTestClient(ctx, lis)
+			if err != nil {
+				t.Fatalf("Failed to create test client: %v", err)
+			}
+			defer conn.Close()
+
+			bankClient := clients.NewBankClientFromConn(conn)
+			handler := handlers.NewHandler(bankClient, nil, nil)
+
+			senderID := uuid.New()
+			idempotencyKey := uuid.New()
+
+			body, err := json.Marshal(models.TransferRequest{
+				RecipientId: uuid.New(),
+				Amount: models.Amount{
+					Value:        "100.00",
+					CurrencyCode: "RUB",
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to marshal request: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/"+senderID.String()+"/transfers", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Idempotency-Key", idempotencyKey.String())
+
+			w := httptest.NewRecorder()
+
+			handler.TransferBetweenAccounts(w, req, senderID, models.TransferBetweenAccountsParams{
+				XIdempotencyKey: idempotencyKey,
+			})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var errorResp models.BaseError
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+
+			if errorResp.Code != tt.expectedCode {
+				t.Errorf("Expected error code %s, got %s", tt.expectedCode, errorResp.Code)
+			}
+		})
+	}
+}
+
 func TestTransferBetweenAccounts_IdempotencyKeyPropagation(t *testing.T) {
 	expectedIdempotencyKey := uuid.New().String()
 
@@ -366,7 +500,7 @@ func TestTransferBetweenAccounts_IdempotencyKeyPropagation(t *testing.T) {
 	defer conn.Close()
 
 	bankClient := clients.NewBankClientFromConn(conn)
-	handler := handlers.NewHandler(bankClient, nil)
+	handler := handlers.NewHandler(bankClient, nil, nil)
 
 	senderID := uuid.New()
 	recipientID := uuid.New()
@@ -432,7 +566,9 @@ func TestGetAccountOperations_Success(t *testing.T) {
 						},
 					},
 				},
-				AfterId: op2ID.String(),
+				NextCursor: "next-cursor",
+				PrevCursor: "prev-cursor",
+				HasMore:    true,
 			}, nil
 		},
 	}
@@ -449,7 +585,7 @@ func TestGetAccountOperations_Success(t *testing.T) {
 
 	// Create analytics client wrapper using the test connection
 	analyticsClient := clients.NewAnalyticsClientFromConn(conn)
-	handler := handlers.NewHandler(nil, analyticsClient)
+	handler := handlers.NewHandler(nil, analyticsClient, nil)
 
 	// Create test HTTP request
 	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations", nil)
@@ -493,18 +629,23 @@ func TestGetAccountOperations_Success(t *testing.T) {
 		t.Errorf("Expected operation type Topup, got %v", resp.Content[1].Type)
 	}
 
-	// Verify afterId
-	if resp.AfterId == nil {
-		t.Error("Expected afterId to be set")
-	} else if *resp.AfterId != op2ID {
-		t.Errorf("Expected afterId %s, got %s", op2ID.String(), resp.AfterId.String())
+	// Verify pagination
+	if !resp.HasMore {
+		t.Error("Expected hasMore to be true")
+	}
+	if resp.NextCursor == nil || *resp.NextCursor != "next-cursor" {
+		t.Errorf("Expected nextCursor next-cursor, got %v", resp.NextCursor)
+	}
+	if resp.PrevCursor == nil || *resp.PrevCursor != "prev-cursor" {
+		t.Errorf("Expected prevCursor prev-cursor, got %v", resp.PrevCursor)
 	}
 }
 
-func TestGetAccountOperations_WithLimitAndAfterId(t *testing.T) {
+func TestGetAccountOperations_WithLimitAndCursor(t *testing.T) {
 	// Setup mock analytics gRPC server
 	accountID := uuid.New()
-	afterID := uuid.New()
+	cursor := "MTc2MDI4MTIwMDAwMDpvcC0x"
+	direction := models.Before
 	limit := 10
 
 	mockService := &mockAnalyticsService{
@@ -516,13 +657,15 @@ func TestGetAccountOperations_WithLimitAndAfterId(t *testing.T) {
 			if req.Limit != int32(limit) {
 				t.Errorf("Expected limit %d, got %d", limit, req.Limit)
 			}
-			if req.AfterId != afterID.String() {
-				t.Errorf("Expected afterId %s, got %s", afterID.String(), req.AfterId)
+			if req.Cursor != cursor {
+				t.Errorf("Expected cursor %s, got %s", cursor, req.Cursor)
+			}
+			if req.Direction != analytics_v1.PageDirection_PAGE_DIRECTION_BEFORE {
+				t.Errorf("Expected direction BEFORE, got %v", req.Direction)
 			}
 
 			return &analytics_v1.ListAccountOperationsResponse{
 				Content: []*analytics_v1.Operation{},
-				AfterId: "",
 			}, nil
 		},
 	}
@@ -538,20 +681,150 @@ func TestGetAccountOperations_WithLimitAndAfterId(t *testing.T) {
 	defer conn.Close()
 
 	analyticsClient := clients.NewAnalyticsClientFromConn(conn)
-	handler := handlers.NewHandler(nil, analyticsClient)
+	handler := handlers.NewHandler(nil, analyticsClient, nil)
 
 	// Create test HTTP request with query parameters
-	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations?limit=10&afterId="+afterID.String(), nil)
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations?limit=10&cursor="+cursor+"&direction=before", nil)
 	w := httptest.NewRecorder()
 
 	handler.GetAccountOperations(w, req, accountID, models.GetAccountOperationsParams{
-		Limit:   &limit,
-		AfterId: &afterID,
+		Limit:     &limit,
+		Cursor:    &cursor,
+		Direction: &direction,
 	})
 
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
 	}
+
+	var resp models.GetOperationsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.HasMore || resp.NextCursor != nil || resp.PrevCursor != nil {
+		t.Errorf("Expected empty last page without cursors, got %+v", resp)
+	}
+}
+
+func TestGetAccountOperations_InvalidDirection(t *testing.T) {
+	accountID := uuid.New()
+	direction := models.GetAccountOperationsParamsDirection("sideways")
+
+	handler := handlers.NewHandler(nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations?direction=sideways", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAccountOperations(w, req, accountID, models.GetAccountOperationsParams{
+		Direction: &direction,
+	})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetAccountOperations_WithFilters(t *testing.T) {
+	// Setup mock analytics gRPC server
+	accountID := uuid.New()
+	counterpartyID := uuid.New()
+	opType := models.Transfer
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	minAmount := "10.00"
+	maxAmount := "1000.00"
+	currencyCode := "RUB"
+
+	mockService := &mockAnalyticsService{
+		listAccountOperationsFunc: func(ctx context.Context, req *analytics_v1.ListAccountOperationsRequest) (*analytics_v1.ListAccountOperationsResponse, error) {
+			// Verify filters are passed through
+			if req.Type != analytics_v1.OperationType_TRANSFER {
+				t.Errorf("Expected type TRANSFER, got %v", req.Type)
+			}
+			if req.From != "2025-10-01T00:00:00Z" || req.To != "2025-11-01T00:00:00Z" {
+				t.Errorf("Expected time range 2025-10-01T00:00:00Z - 2025-11-01T00:00:00Z, got %s - %s", req.From, req.To)
+			}
+			if req.MinAmount != minAmount || req.MaxAmount != maxAmount {
+				t.Errorf("Expected amount range %s - %s, got %s - %s", minAmount, maxAmount, req.MinAmount, req.MaxAmount)
+			}
+			if req.CounterpartyId != counterpartyID.String() {
+				t.Errorf("Expected counterparty ID %s, got %s", counterpartyID.String(), req.CounterpartyId)
+			}
+			if req.CurrencyCode != currencyCode {
+				t.Errorf("Expected currency code %s, got %s", currencyCode, req.CurrencyCode)
+			}
+
+			return &analytics_v1.ListAccountOperationsResponse{
+				Content: []*analytics_v1.Operation{},
+			}, nil
+		},
+	}
+
+	grpcServer, lis := setupMockAnalyticsServer(t, mockService)
+	defer grpcServer.Stop()
+
+	ctx := context.Background()
+	conn, err := createTestClient(ctx, lis)
+	if err != nil {
+		t.Fatalf("Failed to create test client: %v", err)
+	}
+	defer conn.Close()
+
+	analyticsClient := clients.NewAnalyticsClientFromConn(conn)
+	handler := handlers.NewHandler(nil, analyticsClient, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAccountOperations(w, req, accountID, models.GetAccountOperationsParams{
+		Type:           &opType,
+		From:           &from,
+		To:             &to,
+		MinAmount:      &minAmount,
+		MaxAmount:      &maxAmount,
+		CounterpartyId: &counterpartyID,
+		CurrencyCode:   &currencyCode,
+	})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestGetAccountOperations_InvalidFilter(t *testing.T) {
+	// Setup mock analytics gRPC server rejecting the filter
+	accountID := uuid.New()
+	minAmount := "ten"
+
+	mockService := &mockAnalyticsService{
+		listAccountOperationsFunc: func(ctx context.Context, req *analytics_v1.ListAccountOperationsRequest) (*analytics_v1.ListAccountOperationsResponse, error) {
+			return nil, status.Error(codes.InvalidArgument, "invalid min_amount")
+		},
+	}
+
+	grpcServer, lis := setupMockAnalyticsServer(t, mockService)
+	defer grpcServer.Stop()
+
+	ctx := context.Background()
+	conn, err := createTestClient(ctx, lis)
+	if err != nil {
+		t.Fatalf("Failed to create test client: %v", err)
+	}
+	defer conn.Close()
+
+	analyticsClient := clients.NewAnalyticsClientFromConn(conn)
+	handler := handlers.NewHandler(nil, analyticsClient, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations?minAmount=ten", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAccountOperations(w, req, accountID, models.GetAccountOperationsParams{
+		MinAmount: &minAmount,
+	})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
 }
 
 func TestGetAccountOperations_NotFound(t *testing.T) {
@@ -575,7 +848,7 @@ func TestGetAccountOperations_NotFound(t *testing.T) {
 	defer conn.Close()
 
 	analyticsClient := clients.NewAnalyticsClientFromConn(conn)
-	handler := handlers.NewHandler(nil, analyticsClient)
+	handler := handlers.NewHandler(nil, analyticsClient, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations", nil)
 	w := httptest.NewRecorder()
@@ -630,7 +903,7 @@ func TestGetAccountOperations_InvalidTimestamp(t *testing.T) {
 	defer conn.Close()
 
 	analyticsClient := clients.NewAnalyticsClientFromConn(conn)
-	handler := handlers.NewHandler(nil, analyticsClient)
+	handler := handlers.NewHandler(nil, analyticsClient, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID.String()+"/operations", nil)
 	w := httptest.NewRecorder()
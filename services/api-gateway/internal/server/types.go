@@ -6,6 +6,7 @@ import "github.com/spbu-ds-practicum-2025/example-project/services/api-gateway/i
 type (
 	AccountIdParam                = models.AccountIdParam
 	GetAccountOperationsParams    = models.GetAccountOperationsParams
+	GetAccountStatementParams     = models.GetAccountStatementParams
 	TopUpAccountParams            = models.TopUpAccountParams
 	TransferBetweenAccountsParams = models.TransferBetweenAccountsParams
 	IdempotencyKeyHeader          = models.IdempotencyKeyHeader
@@ -1,22 +1,121 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/clients"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/domain"
+	grpcserver "github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/grpc"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/proto/bankcard.v1"
+	"github.com/spbu-ds-practicum-2025/example-project/services/common/health"
 )
 
 func main() {
-	lis, err := net.Listen("tcp", ":50052")
+	// Get configuration from environment variables
+	port := getEnv("PORT", "50052")
+	bankServiceAddr := getEnv("BANK_SERVICE_ADDR", "localhost:50051")
+
+	acquirerConfig := clients.FakeAcquirerConfig{}
+	if value := os.Getenv("FAKE_ACQUIRER_DELAY"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("invalid FAKE_ACQUIRER_DELAY: %v", err)
+		}
+		acquirerConfig.Delay = delay
+	}
+	if value := os.Getenv("FAKE_ACQUIRER_FAILURE_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			log.Fatalf("invalid FAKE_ACQUIRER_FAILURE_RATE: must be a number between 0 and 1")
+		}
+		acquirerConfig.FailureRate = rate
+	}
+	healthCheckInterval := getEnvDuration("HEALTH_CHECK_INTERVAL", 5*time.Second)
+	healthCheckTimeout := getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+
+	// Create bank service client
+	bankClient, err := clients.NewBankClient(bankServiceAddr)
+	if err != nil {
+		log.Fatalf("failed to create bank client: %v", err)
+	}
+	defer bankClient.Close()
+
+	// Create payment gateway (in-process fake acquirer) and domain service
+	gateway := clients.NewFakeAcquirer(acquirerConfig)
+	topUpService := domain.NewCardTopUpService(gateway, bankClient)
+
+	// Create gRPC server
+	grpcServer := grpc.NewServer()
+	pb.RegisterBankCardAdapterServer(grpcServer, grpcserver.NewBankCardAdapterServer(topUpService))
+
+	// Register health service reporting whether the bank service is reachable
+	healthChecker := health.NewChecker(
+		[]string{pb.BankCardAdapter_ServiceDesc.ServiceName},
+		[]health.Dependency{{Name: "bank-service", Check: bankClient.CheckHealth}},
+		healthCheckTimeout,
+	)
+	healthChecker.Register(grpcServer)
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	go healthChecker.Run(healthCtx, healthCheckInterval)
+
+	// Register reflection service (useful for tools like grpcurl)
+	reflection.Register(grpcServer)
+
+	// Start listening
+	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		log.Fatalf("failed to listen on port %s: %v", port, err)
+	}
+
+	// Start server in a goroutine
+	go func() {
+		log.Printf("bank-card-adapter gRPC server starting on :%s, connecting to Bank Service at %s", port, bankServiceAddr)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("shutting down gRPC server...")
+	// Report NOT_SERVING first, so that clients stop sending new calls while in-flight ones finish
+	healthChecker.Shutdown()
+	stopHealth()
+	grpcServer.GracefulStop()
+	log.Println("gRPC server stopped")
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
+	return defaultValue
+}
 
-	s := grpc.NewServer()
-	_ = s
-	log.Println("bank-card-adapter gRPC server starting on :50052")
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("gRPC server failed: %v", err)
+// getEnvDuration gets a duration environment variable (e.g., "5s") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalf("invalid %s: must be a positive duration", key)
 	}
+	return duration
 }
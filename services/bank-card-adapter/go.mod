@@ -3,6 +3,16 @@ module github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adap
 go 1.20
 
 require (
+	github.com/spbu-ds-practicum-2025/example-project/services/common/health v0.0.0
 	google.golang.org/grpc v1.64.0
-	google.golang.org/protobuf v1.31.0
+	google.golang.org/protobuf v1.33.0
 )
+
+require (
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+)
+
+replace github.com/spbu-ds-practicum-2025/example-project/services/common/health => ../common/health
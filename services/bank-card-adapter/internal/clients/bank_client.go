@@ -0,0 +1,121 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/domain"
+	bank_v1 "github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/proto/bank.v1"
+)
+
+// BankClient wraps the gRPC client for the Bank Service and implements domain.BankService.
+type BankClient struct {
+	client bank_v1.BankServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewBankClient creates a new BankClient connected to the specified address
+func NewBankClient(bankServiceAddr string) (*BankClient, error) {
+	conn, err := grpc.NewClient(
+		bankServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to bank service: %w", err)
+	}
+
+	return NewBankClientFromConn(conn), nil
+}
+
+// NewBankClientFromConn creates a new BankClient from an existing gRPC connection
+// This is useful for testing with mock servers
+func NewBankClientFromConn(conn *grpc.ClientConn) *BankClient {
+	return &BankClient{
+		client: bank_v1.NewBankServiceClient(conn),
+		conn:   conn,
+	}
+}
+
+// TopUp calls the TopUp RPC on the bank service and translates gRPC errors into domain errors.
+func (c *BankClient) TopUp(ctx context.Context, request domain.TopUpRequest) (*domain.TopUpResult, error) {
+	resp, err := c.client.TopUp(ctx, &bank_v1.TopUpRequest{
+		AccountId: request.AccountID,
+		Amount: &bank_v1.Amount{
+			Value:        request.Amount.Value,
+			CurrencyCode: request.Amount.CurrencyCode,
+		},
+		IdempotencyKey:        request.IdempotencyKey,
+		Source:                request.Source,
+		ExternalTransactionId: request.ExternalTransactionID,
+	})
+	if err != nil {
+		return nil, mapBankError(err)
+	}
+
+	result := &domain.TopUpResult{
+		OperationID: resp.GetOperationId(),
+		AccountID:   resp.GetAccountId(),
+		Amount: domain.Amount{
+			Value:        resp.GetAmount().GetValue(),
+			CurrencyCode: resp.GetAmount().GetCurrencyCode(),
+		},
+		Message: resp.GetMessage(),
+		NewBalance: domain.Amount{
+			Value:        resp.GetNewBalance().GetValue(),
+			CurrencyCode: resp.GetNewBalance().GetCurrencyCode(),
+		},
+		CompletedAt: time.Now(),
+	}
+	if completedAt, err := time.Parse(time.RFC3339, resp.GetTimestamp()); err == nil {
+		result.CompletedAt = completedAt
+	}
+
+	return result, nil
+}
+
+// CheckHealth checks that the bank service is reachable and reports SERVING over the gRPC health checking protocol.
+func (c *BankClient) CheckHealth(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: bank_v1.BankService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check bank service health: %w", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("bank service is %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// Close closes the gRPC connection
+func (c *BankClient) Close() error {
+	return c.conn.Close()
+}
+
+// mapBankError maps BankService gRPC errors to domain errors.
+// Errors describing the request or the account are permanent; everything else may succeed on retry.
+func mapBankError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("%w: %v", domain.ErrBankUnavailable, err)
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", domain.ErrAccountNotFound, st.Message())
+	case codes.AlreadyExists:
+		return fmt.Errorf("%w: %s", domain.ErrIdempotencyConflict, st.Message())
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange,
+		codes.PermissionDenied, codes.Unauthenticated, codes.Unimplemented:
+		return fmt.Errorf("%w: %s", domain.ErrTopUpRejected, st.Message())
+	default:
+		return fmt.Errorf("%w: %s", domain.ErrBankUnavailable, st.Message())
+	}
+}
@@ -0,0 +1,35 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/domain"
+)
+
+func TestMapBankError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{"account not found", status.Error(codes.NotFound, "account not found"), domain.ErrAccountNotFound},
+		{"invalid argument", status.Error(codes.InvalidArgument, "currency mismatch"), domain.ErrTopUpRejected},
+		{"account not active", status.Error(codes.FailedPrecondition, "account is not active"), domain.ErrTopUpRejected},
+		{"idempotency key reused", status.Error(codes.AlreadyExists, "idempotency key was already used"), domain.ErrIdempotencyConflict},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), domain.ErrBankUnavailable},
+		{"internal", status.Error(codes.Internal, "internal error"), domain.ErrBankUnavailable},
+		{"not a gRPC error", errors.New("connection reset"), domain.ErrBankUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := mapBankError(tt.err); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
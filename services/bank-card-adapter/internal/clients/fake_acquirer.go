@@ -0,0 +1,139 @@
+package clients
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/domain"
+)
+
+// Test card numbers with a predefined outcome in FakeAcquirer.
+// Any other valid card number is charged successfully (unless a random failure is configured).
+const (
+	// TestCardDeclined is always declined with reason "card_declined"
+	TestCardDeclined = "4000000000000002"
+
+	// TestCardInsufficientFunds is always declined with reason "insufficient_funds"
+	TestCardInsufficientFunds = "4000000000009995"
+)
+
+// FakeAcquirerConfig holds the behaviour of the fake acquirer.
+type FakeAcquirerConfig struct {
+	// Delay simulates the network and processing latency of each call
+	Delay time.Duration
+
+	// FailureRate is the probability (0..1) of randomly declining a charge with reason "do_not_honor"
+	FailureRate float64
+}
+
+// FakeAcquirer is an in-process domain.PaymentGateway simulating an external card acquirer.
+// Charges are kept in memory and deduplicated by idempotency key, like a real acquirer does.
+type FakeAcquirer struct {
+	config FakeAcquirerConfig
+
+	mu      sync.Mutex
+	charges map[string]*domain.Charge // by charge ID
+	byKey   map[string]string         // idempotency key -> charge ID
+	random  *mathrand.Rand
+}
+
+// NewFakeAcquirer creates a new FakeAcquirer.
+func NewFakeAcquirer(config FakeAcquirerConfig) *FakeAcquirer {
+	return &FakeAcquirer{
+		config:  config,
+		charges: make(map[string]*domain.Charge),
+		byKey:   make(map[string]string),
+		random:  mathrand.New(mathrand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Charge charges the card. Repeating a charge with the same idempotency key
+// returns the original charge, including a refunded one.
+func (a *FakeAcquirer) Charge(ctx context.Context, request domain.ChargeRequest) (*domain.Charge, error) {
+	if err := a.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if id, ok := a.byKey[request.IdempotencyKey]; ok {
+		existing := *a.charges[id]
+		return &existing, nil
+	}
+
+	switch {
+	case request.Card.Number == TestCardDeclined:
+		return nil, &domain.DeclinedError{Reason: "card_declined"}
+	case request.Card.Number == TestCardInsufficientFunds:
+		return nil, &domain.DeclinedError{Reason: "insufficient_funds"}
+	case a.config.FailureRate > 0 && a.random.Float64() < a.config.FailureRate:
+		return nil, &domain.DeclinedError{Reason: "do_not_honor"}
+	}
+
+	id, err := newChargeID()
+	if err != nil {
+		return nil, err
+	}
+
+	charge := &domain.Charge{
+		ID:        id,
+		Amount:    request.Amount,
+		CardLast4: request.Card.Last4(),
+		Status:    domain.ChargeStatusSucceeded,
+		CreatedAt: time.Now(),
+	}
+	a.charges[id] = charge
+	a.byKey[request.IdempotencyKey] = id
+
+	result := *charge
+	return &result, nil
+}
+
+// Refund returns a charge to the card.
+func (a *FakeAcquirer) Refund(ctx context.Context, chargeID string) error {
+	if err := a.wait(ctx); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	charge, ok := a.charges[chargeID]
+	if !ok {
+		return fmt.Errorf("charge %s not found", chargeID)
+	}
+	charge.Status = domain.ChargeStatusRefunded
+	return nil
+}
+
+// wait simulates the acquirer latency, honouring context cancellation.
+func (a *FakeAcquirer) wait(ctx context.Context) error {
+	if a.config.Delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(a.config.Delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// newChargeID generates a random acquirer-style charge identifier (e.g., "ch_3f2a...").
+func newChargeID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate charge id: %w", err)
+	}
+	return "ch_" + hex.EncodeToString(b), nil
+}
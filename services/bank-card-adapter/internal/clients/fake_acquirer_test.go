@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/domain"
+)
+
+func chargeRequest(key, number string) domain.ChargeRequest {
+	return domain.ChargeRequest{
+		IdempotencyKey: key,
+		Card:           domain.Card{Number: number, ExpiryMonth: 12, ExpiryYear: 2030, CVV: "123"},
+		Amount:         domain.Amount{Value: "100.00", CurrencyCode: "RUB"},
+	}
+}
+
+func TestFakeAcquirer_ChargeIsIdempotent(t *testing.T) {
+	acquirer := NewFakeAcquirer(FakeAcquirerConfig{})
+
+	first, err := acquirer.Charge(context.Background(), chargeRequest("key-1", "4111111111111111"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Status != domain.ChargeStatusSucceeded || first.CardLast4 != "1111" {
+		t.Errorf("unexpected charge: %+v", first)
+	}
+
+	second, err := acquirer.Charge(context.Background(), chargeRequest("key-1", "4111111111111111"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("expected the same charge for the same key, got %s and %s", first.ID, second.ID)
+	}
+
+	other, err := acquirer.Charge(context.Background(), chargeRequest("key-2", "4111111111111111"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.ID == first.ID {
+		t.Error("expected a new charge for a different key")
+	}
+}
+
+func TestFakeAcquirer_TestCards(t *testing.T) {
+	tests := []struct {
+		number string
+		reason string
+	}{
+		{TestCardDeclined, "card_declined"},
+		{TestCardInsufficientFunds, "insufficient_funds"},
+	}
+
+	acquirer := NewFakeAcquirer(FakeAcquirerConfig{})
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			_, err := acquirer.Charge(context.Background(), chargeRequest("key-"+tt.reason, tt.number))
+
+			var declined *domain.DeclinedError
+			if !errors.As(err, &declined) || declined.Reason != tt.reason {
+				t.Errorf("expected decline %q, got %v", tt.reason, err)
+			}
+		})
+	}
+}
+
+func TestFakeAcquirer_Refund(t *testing.T) {
+	acquirer := NewFakeAcquirer(FakeAcquirerConfig{})
+
+	charge, err := acquirer.Charge(context.Background(), chargeRequest("key-1", "4111111111111111"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := acquirer.Refund(context.Background(), charge.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The refunded charge is returned for its idempotency key
+	again, err := acquirer.Charge(context.Background(), chargeRequest("key-1", "4111111111111111"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Status != domain.ChargeStatusRefunded {
+		t.Errorf("expected REFUNDED charge, got %s", again.Status)
+	}
+
+	if err := acquirer.Refund(context.Background(), "ch_unknown"); err == nil {
+		t.Error("expected error for unknown charge")
+	}
+}
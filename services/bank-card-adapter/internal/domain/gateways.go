@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+)
+
+// PaymentGateway defines the interface of the external card acquirer.
+// Implementations must be idempotent on ChargeRequest.IdempotencyKey: charging again
+// with a known key returns the original charge instead of charging the card twice.
+type PaymentGateway interface {
+	// Charge charges the card with the given amount.
+	// Returns a *DeclinedError if the acquirer declines the card.
+	Charge(ctx context.Context, request ChargeRequest) (*Charge, error)
+
+	// Refund returns a charge to the card.
+	// Refunding an already refunded charge is a no-op.
+	Refund(ctx context.Context, chargeID string) error
+}
+
+// BankService defines the interface of the BankService operations used by the adapter.
+type BankService interface {
+	// TopUp credits an account. The operation is idempotent on TopUpRequest.IdempotencyKey.
+	// Returns ErrAccountNotFound or ErrTopUpRejected if BankService refuses the top-up,
+	// ErrIdempotencyConflict if the key was used for a different top-up,
+	// and ErrBankUnavailable if the outcome is unknown and the call may be retried.
+	TopUp(ctx context.Context, request TopUpRequest) (*TopUpResult, error)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+)
+
+// Amount represents a monetary value with currency.
+// The adapter never does arithmetic on amounts, so the value is kept as the decimal string
+// received from the caller and passed through to the payment gateway and BankService.
+type Amount struct {
+	Value        string // Decimal string with up to 2 decimal places (e.g., "100.50")
+	CurrencyCode string // ISO 4217 currency code (e.g., "RUB")
+}
+
+// Equal reports whether both amounts have the same currency and value,
+// comparing values as decimals (e.g., "100.5" equals "100.50").
+func (a Amount) Equal(other Amount) bool {
+	return a.CurrencyCode == other.CurrencyCode && normalizeAmount(a.Value) == normalizeAmount(other.Value)
+}
+
+// Card represents the details of a bank card to be charged.
+type Card struct {
+	Number      string // Card number (PAN), digits only
+	ExpiryMonth int    // Expiry month (1-12)
+	ExpiryYear  int    // Expiry year (four digits, e.g., 2025)
+	CVV         string // Card verification value
+}
+
+// ChargeRequest represents a request to charge a card through the payment gateway.
+type ChargeRequest struct {
+	IdempotencyKey string // Key making repeated charges with the same key return the original charge
+	Card           Card   // Card to be charged
+	Amount         Amount // Amount to be charged
+	Description    string // Statement description of the charge
+}
+
+// Charge represents a successful card charge in the payment gateway.
+type Charge struct {
+	ID        string       // Identifier of the charge in the payment gateway
+	Amount    Amount       // Amount charged
+	CardLast4 string       // Last four digits of the charged card
+	Status    ChargeStatus // Current status of the charge
+	CreatedAt time.Time    // Timestamp when the card was charged
+}
+
+// ChargeStatus represents the possible states of a card charge.
+type ChargeStatus string
+
+const (
+	// ChargeStatusSucceeded indicates the card was charged
+	ChargeStatusSucceeded ChargeStatus = "SUCCEEDED"
+
+	// ChargeStatusRefunded indicates the charge was returned to the card
+	ChargeStatusRefunded ChargeStatus = "REFUNDED"
+)
+
+// TopUpRequest represents a request to credit an account in BankService.
+type TopUpRequest struct {
+	AccountID             string // Account to be credited (UUID format)
+	Amount                Amount // Amount to credit
+	IdempotencyKey        string // Key making repeated top-ups with the same key idempotent
+	Source                string // Source of the funds (e.g., "bank_card")
+	ExternalTransactionID string // Identifier of the payment gateway charge
+}
+
+// TopUpResult represents the outcome of a successful BankService top-up.
+type TopUpResult struct {
+	OperationID string    // Identifier of the top-up operation in BankService
+	AccountID   string    // Account that was credited
+	Amount      Amount    // Amount that was credited
+	Message     string    // Human-readable message about the top-up
+	NewBalance  Amount    // Account balance right after the top-up
+	CompletedAt time.Time // Timestamp when the top-up was completed
+}
+
+// CardTopUp represents a completed card top-up: a card charge credited to an account.
+type CardTopUp struct {
+	AccountID   string    // Account that was credited
+	Amount      Amount    // Amount charged and credited
+	ChargeID    string    // Identifier of the payment gateway charge
+	CardLast4   string    // Last four digits of the charged card
+	OperationID string    // Identifier of the top-up operation in BankService
+	Message     string    // Human-readable message about the top-up
+	NewBalance  Amount    // Account balance right after the top-up
+	CompletedAt time.Time // Timestamp when the top-up was completed
+}
+
+// Last4 returns the last four digits of the card number.
+func (c Card) Last4() string {
+	if len(c.Number) < 4 {
+		return c.Number
+	}
+	return c.Number[len(c.Number)-4:]
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// TopUpSource is the BankService top-up source recorded for card top-ups.
+const TopUpSource = "bank_card"
+
+var (
+	// ErrInvalidCard is returned when the card details are malformed or the card has expired
+	ErrInvalidCard = errors.New("invalid card")
+
+	// ErrInvalidAmount is returned when the top-up amount is not a positive decimal
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrCardDeclined is returned (wrapped in a *DeclinedError) when the acquirer declines the card
+	ErrCardDeclined = errors.New("card declined")
+
+	// ErrAccountNotFound is returned when BankService doesn't know the account
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrTopUpRejected is returned when BankService refuses the top-up (e.g. the account is closed)
+	ErrTopUpRejected = errors.New("top-up rejected by bank service")
+
+	// ErrIdempotencyConflict is returned when the idempotency key was already used for a top-up
+	// of a different account or amount. Nothing is refunded, since the existing charge may
+	// have been credited by the original top-up.
+	ErrIdempotencyConflict = errors.New("idempotency key was already used for a different top-up")
+
+	// ErrBankUnavailable is returned when BankService cannot be reached or fails internally.
+	// The card charge is kept, so retrying with the same idempotency key completes the top-up.
+	ErrBankUnavailable = errors.New("bank service unavailable")
+)
+
+// DeclinedError is returned by a PaymentGateway when the acquirer declines the card.
+type DeclinedError struct {
+	Reason string // Machine-readable decline reason (e.g., "insufficient_funds")
+}
+
+// Error implements the error interface.
+func (e *DeclinedError) Error() string {
+	return fmt.Sprintf("card declined: %s", e.Reason)
+}
+
+// Unwrap makes errors.Is(err, ErrCardDeclined) match declines.
+func (e *DeclinedError) Unwrap() error {
+	return ErrCardDeclined
+}
+
+// CardTopUpService orchestrates card top-ups: it charges the card through the payment gateway
+// and credits the charged amount to the account through BankService.
+type CardTopUpService struct {
+	gateway PaymentGateway
+	bank    BankService
+	now     func() time.Time
+}
+
+// NewCardTopUpService creates a new instance of CardTopUpService.
+func NewCardTopUpService(gateway PaymentGateway, bank BankService) *CardTopUpService {
+	return &CardTopUpService{
+		gateway: gateway,
+		bank:    bank,
+		now:     time.Now,
+	}
+}
+
+// ProcessCardTopUp charges the card and credits the charged amount to the account.
+// This operation is idempotent - both the charge and the BankService top-up use the
+// idempotency key, so a retry never charges the card or credits the account twice.
+//
+// The top-up is executed in the following steps:
+// 1. Validate the card and the amount
+// 2. Charge the card through the payment gateway
+// 3. Credit the account via BankService.TopUp with the charge ID as external transaction ID
+// 4. If BankService refuses the top-up, refund the charge
+//
+// If BankService is unavailable the charge is kept and ErrBankUnavailable is returned;
+// a retry with the same idempotency key finds the existing charge and completes the top-up.
+// If the charge or the top-up found for the key differ from the request in account or amount,
+// ErrIdempotencyConflict is returned.
+func (s *CardTopUpService) ProcessCardTopUp(
+	ctx context.Context,
+	accountID string,
+	amount Amount,
+	card Card,
+	idempotencyKey string,
+) (*CardTopUp, error) {
+	if err := ValidateAmount(amount.Value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+	if err := ValidateCard(card, s.now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCard, err)
+	}
+
+	charge, err := s.gateway.Charge(ctx, ChargeRequest{
+		IdempotencyKey: idempotencyKey,
+		Card:           card,
+		Amount:         amount,
+		Description:    fmt.Sprintf("Wallet top-up %s", accountID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge card: %w", err)
+	}
+	if !charge.Amount.Equal(amount) {
+		// The key was used for a charge of another amount
+		return nil, fmt.Errorf("%w: charge %s is %s %s", ErrIdempotencyConflict, charge.ID, charge.Amount.Value, charge.Amount.CurrencyCode)
+	}
+	if charge.Status == ChargeStatusRefunded {
+		// A previous attempt with this key was refused by BankService and refunded
+		return nil, fmt.Errorf("%w: charge %s was refunded", ErrTopUpRejected, charge.ID)
+	}
+
+	result, err := s.bank.TopUp(ctx, TopUpRequest{
+		AccountID:             accountID,
+		Amount:                amount,
+		IdempotencyKey:        idempotencyKey,
+		Source:                TopUpSource,
+		ExternalTransactionID: charge.ID,
+	})
+	if err != nil {
+		if errors.Is(err, ErrAccountNotFound) || errors.Is(err, ErrTopUpRejected) {
+			// The account will never accept this top-up: return the money to the card
+			if refundErr := s.gateway.Refund(ctx, charge.ID); refundErr != nil {
+				log.Printf("failed to refund charge %s after rejected top-up: %v", charge.ID, refundErr)
+				return nil, fmt.Errorf("top-up rejected and refund of charge %s failed: %w", charge.ID, refundErr)
+			}
+		}
+		return nil, fmt.Errorf("failed to top up account: %w", err)
+	}
+	if result.AccountID != accountID || !result.Amount.Equal(amount) {
+		// The key was used for a top-up of another account or amount
+		return nil, fmt.Errorf("%w: top-up %s credited %s %s to account %s",
+			ErrIdempotencyConflict, result.OperationID, result.Amount.Value, result.Amount.CurrencyCode, result.AccountID)
+	}
+
+	return &CardTopUp{
+		AccountID:   result.AccountID,
+		Amount:      result.Amount,
+		ChargeID:    charge.ID,
+		CardLast4:   charge.CardLast4,
+		OperationID: result.OperationID,
+		Message:     result.Message,
+		NewBalance:  result.NewBalance,
+		CompletedAt: result.CompletedAt,
+	}, nil
+}
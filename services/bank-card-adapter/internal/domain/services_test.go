@@ -0,0 +1,274 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const validCardNumber = "4111111111111111"
+
+// fakeGateway is an in-memory PaymentGateway recording charges and refunds
+type fakeGateway struct {
+	declineWith string
+	charges     map[string]*Charge // by idempotency key
+	refunded    []string
+}
+
+func (g *fakeGateway) Charge(ctx context.Context, request ChargeRequest) (*Charge, error) {
+	if g.declineWith != "" {
+		return nil, &DeclinedError{Reason: g.declineWith}
+	}
+	if g.charges == nil {
+		g.charges = make(map[string]*Charge)
+	}
+	if charge, ok := g.charges[request.IdempotencyKey]; ok {
+		return charge, nil
+	}
+	charge := &Charge{
+		ID:        "ch_" + request.IdempotencyKey,
+		Amount:    request.Amount,
+		CardLast4: request.Card.Last4(),
+		Status:    ChargeStatusSucceeded,
+	}
+	g.charges[request.IdempotencyKey] = charge
+	return charge, nil
+}
+
+func (g *fakeGateway) Refund(ctx context.Context, chargeID string) error {
+	for _, charge := range g.charges {
+		if charge.ID == chargeID {
+			charge.Status = ChargeStatusRefunded
+		}
+	}
+	g.refunded = append(g.refunded, chargeID)
+	return nil
+}
+
+// fakeBank is a BankService returning a configured error or crediting the account.
+// Like BankService, it returns the original top-up when an idempotency key is reused.
+type fakeBank struct {
+	err      error
+	requests []TopUpRequest
+	topUps   map[string]*TopUpResult // by idempotency key
+}
+
+func (b *fakeBank) TopUp(ctx context.Context, request TopUpRequest) (*TopUpResult, error) {
+	b.requests = append(b.requests, request)
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.topUps == nil {
+		b.topUps = make(map[string]*TopUpResult)
+	}
+	if result, ok := b.topUps[request.IdempotencyKey]; ok {
+		return result, nil
+	}
+	result := &TopUpResult{
+		OperationID: "op-1",
+		AccountID:   request.AccountID,
+		Amount:      request.Amount,
+		Message:     "Top-up completed successfully",
+		NewBalance:  Amount{Value: "1100.00", CurrencyCode: "RUB"},
+	}
+	b.topUps[request.IdempotencyKey] = result
+	return result, nil
+}
+
+func newTestService(gateway PaymentGateway, bank BankService) *CardTopUpService {
+	service := NewCardTopUpService(gateway, bank)
+	service.now = func() time.Time { return time.Date(2025, 11, 8, 12, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func validCard() Card {
+	return Card{Number: validCardNumber, ExpiryMonth: 12, ExpiryYear: 2026, CVV: "123"}
+}
+
+var rub100 = Amount{Value: "100.00", CurrencyCode: "RUB"}
+
+func TestProcessCardTopUp_Success(t *testing.T) {
+	gateway := &fakeGateway{}
+	bank := &fakeBank{}
+	service := newTestService(gateway, bank)
+
+	topUp, err := service.ProcessCardTopUp(context.Background(), "account-1", rub100, validCard(), "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if topUp.ChargeID != "ch_key-1" || topUp.CardLast4 != "1111" || topUp.OperationID != "op-1" {
+		t.Errorf("unexpected top-up: %+v", topUp)
+	}
+	if len(bank.requests) != 1 {
+		t.Fatalf("expected one bank top-up, got %d", len(bank.requests))
+	}
+	request := bank.requests[0]
+	if request.ExternalTransactionID != "ch_key-1" || request.Source != TopUpSource || request.IdempotencyKey != "key-1" {
+		t.Errorf("unexpected bank request: %+v", request)
+	}
+}
+
+func TestProcessCardTopUp_RetryAfterBankOutageDoesNotChargeTwice(t *testing.T) {
+	gateway := &fakeGateway{}
+	bank := &fakeBank{err: ErrBankUnavailable}
+	service := newTestService(gateway, bank)
+
+	_, err := service.ProcessCardTopUp(context.Background(), "account-1", rub100, validCard(), "key-1")
+	if !errors.Is(err, ErrBankUnavailable) {
+		t.Fatalf("expected ErrBankUnavailable, got %v", err)
+	}
+	if len(gateway.refunded) != 0 {
+		t.Errorf("charge must be kept when the bank outcome is unknown, refunded %v", gateway.refunded)
+	}
+
+	// Bank recovers: the retry reuses the charge
+	bank.err = nil
+	topUp, err := service.ProcessCardTopUp(context.Background(), "account-1", rub100, validCard(), "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gateway.charges) != 1 || topUp.ChargeID != "ch_key-1" {
+		t.Errorf("expected the original charge to be reused, got %d charges", len(gateway.charges))
+	}
+}
+
+func TestProcessCardTopUp_RefundsRejectedTopUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		bankErr error
+	}{
+		{"account not found", ErrAccountNotFound},
+		{"account closed", ErrTopUpRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &fakeGateway{}
+			service := newTestService(gateway, &fakeBank{err: tt.bankErr})
+
+			_, err := service.ProcessCardTopUp(context.Background(), "account-1", rub100, validCard(), "key-1")
+			if !errors.Is(err, tt.bankErr) {
+				t.Fatalf("expected %v, got %v", tt.bankErr, err)
+			}
+			if len(gateway.refunded) != 1 || gateway.refunded[0] != "ch_key-1" {
+				t.Fatalf("expected the charge to be refunded, got %v", gateway.refunded)
+			}
+
+			// Retrying the same key must not credit the refunded charge
+			_, err = service.ProcessCardTopUp(context.Background(), "account-1", rub100, validCard(), "key-1")
+			if !errors.Is(err, ErrTopUpRejected) {
+				t.Errorf("expected ErrTopUpRejected on retry, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProcessCardTopUp_RejectsReusedKey(t *testing.T) {
+	gateway := &fakeGateway{}
+	bank := &fakeBank{}
+	service := newTestService(gateway, bank)
+
+	if _, err := service.ProcessCardTopUp(context.Background(), "account-1", rub100, validCard(), "key-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		accountID string
+		amount    Amount
+	}{
+		{"different amount", "account-1", Amount{Value: "250.00", CurrencyCode: "RUB"}},
+		{"different currency", "account-1", Amount{Value: "100.00", CurrencyCode: "USD"}},
+		{"different account", "account-2", rub100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ProcessCardTopUp(context.Background(), tt.accountID, tt.amount, validCard(), "key-1")
+			if !errors.Is(err, ErrIdempotencyConflict) {
+				t.Fatalf("expected ErrIdempotencyConflict, got %v", err)
+			}
+			if len(gateway.refunded) != 0 {
+				t.Errorf("the original charge must not be refunded, refunded %v", gateway.refunded)
+			}
+		})
+	}
+
+	// The same request written differently is a replay
+	topUp, err := service.ProcessCardTopUp(context.Background(), "account-1", Amount{Value: "100", CurrencyCode: "RUB"}, validCard(), "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error on replay: %v", err)
+	}
+	if topUp.OperationID != "op-1" || !topUp.Amount.Equal(rub100) {
+		t.Errorf("expected the original top-up, got %+v", topUp)
+	}
+}
+
+func TestProcessCardTopUp_Declined(t *testing.T) {
+	bank := &fakeBank{}
+	service := newTestService(&fakeGateway{declineWith: "insufficient_funds"}, bank)
+
+	_, err := service.ProcessCardTopUp(context.Background(), "account-1", rub100, validCard(), "key-1")
+
+	var declined *DeclinedError
+	if !errors.As(err, &declined) || declined.Reason != "insufficient_funds" || !errors.Is(err, ErrCardDeclined) {
+		t.Fatalf("expected insufficient_funds decline, got %v", err)
+	}
+	if len(bank.requests) != 0 {
+		t.Error("bank must not be called for a declined card")
+	}
+}
+
+func TestProcessCardTopUp_InvalidInput(t *testing.T) {
+	expired := validCard()
+	expired.ExpiryYear, expired.ExpiryMonth = 2025, 10
+
+	badChecksum := validCard()
+	badChecksum.Number = "4111111111111112"
+
+	badCVV := validCard()
+	badCVV.CVV = "12a"
+
+	tests := []struct {
+		name        string
+		amount      Amount
+		card        Card
+		expectedErr error
+	}{
+		{"expired card", rub100, expired, ErrInvalidCard},
+		{"bad checksum", rub100, badChecksum, ErrInvalidCard},
+		{"bad cvv", rub100, badCVV, ErrInvalidCard},
+		{"zero amount", Amount{Value: "0.00", CurrencyCode: "RUB"}, validCard(), ErrInvalidAmount},
+		{"too many decimals", Amount{Value: "1.001", CurrencyCode: "RUB"}, validCard(), ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &fakeGateway{}
+			service := newTestService(gateway, &fakeBank{})
+
+			_, err := service.ProcessCardTopUp(context.Background(), "account-1", tt.amount, tt.card, "key-1")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+			if len(gateway.charges) != 0 {
+				t.Error("card must not be charged for invalid input")
+			}
+		})
+	}
+}
+
+func TestParseExpiryDate(t *testing.T) {
+	month, year, err := ParseExpiryDate("12/25")
+	if err != nil || month != 12 || year != 2025 {
+		t.Errorf("expected 12/2025, got %d/%d (%v)", month, year, err)
+	}
+
+	for _, value := range []string{"", "1/25", "13/25", "00/25", "12/2025", "ab/cd", "12-25"} {
+		if _, _, err := ParseExpiryDate(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
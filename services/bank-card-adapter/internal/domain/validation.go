@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseExpiryDate parses a card expiry date in MM/YY format (e.g., "12/25").
+// Returns the month and the four-digit year.
+func ParseExpiryDate(value string) (month int, year int, err error) {
+	rawMonth, rawYear, ok := strings.Cut(value, "/")
+	if !ok || len(rawMonth) != 2 || len(rawYear) != 2 || !isDigits(rawMonth) || !isDigits(rawYear) {
+		return 0, 0, fmt.Errorf("expiry date must be in MM/YY format")
+	}
+
+	month, _ = strconv.Atoi(rawMonth)
+	if month < 1 || month > 12 {
+		return 0, 0, fmt.Errorf("expiry month must be between 01 and 12")
+	}
+
+	year, _ = strconv.Atoi(rawYear)
+	return month, 2000 + year, nil
+}
+
+// ValidateCard checks the card number format and checksum, the CVV format,
+// and that the card has not expired at the given time.
+// A card is valid until the end of its expiry month.
+func ValidateCard(card Card, now time.Time) error {
+	if len(card.Number) < 12 || len(card.Number) > 19 || !isDigits(card.Number) {
+		return fmt.Errorf("card number must be 12-19 digits")
+	}
+	if !luhnValid(card.Number) {
+		return fmt.Errorf("card number checksum is invalid")
+	}
+
+	if (len(card.CVV) != 3 && len(card.CVV) != 4) || !isDigits(card.CVV) {
+		return fmt.Errorf("cvv must be 3 or 4 digits")
+	}
+
+	if card.ExpiryMonth < 1 || card.ExpiryMonth > 12 {
+		return fmt.Errorf("expiry month must be between 1 and 12")
+	}
+	// First moment after the expiry month
+	expiresAt := time.Date(card.ExpiryYear, time.Month(card.ExpiryMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	if !now.Before(expiresAt) {
+		return fmt.Errorf("card has expired")
+	}
+
+	return nil
+}
+
+// ValidateAmount checks that value is a positive decimal with up to 2 decimal places.
+func ValidateAmount(value string) error {
+	integer, fraction, hasPoint := strings.Cut(value, ".")
+	if integer == "" || !isDigits(integer) {
+		return fmt.Errorf("amount must be a decimal with up to 2 decimal places")
+	}
+	if hasPoint && (len(fraction) == 0 || len(fraction) > 2 || !isDigits(fraction)) {
+		return fmt.Errorf("amount must be a decimal with up to 2 decimal places")
+	}
+	if strings.Trim(integer+fraction, "0") == "" {
+		return fmt.Errorf("amount must be positive")
+	}
+	return nil
+}
+
+// normalizeAmount formats a decimal amount with exactly 2 decimal places and
+// without leading zeros, so that equal amounts have equal representations.
+// The value is expected to pass ValidateAmount.
+func normalizeAmount(value string) string {
+	integer, fraction, _ := strings.Cut(value, ".")
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	return integer + "." + fraction
+}
+
+// luhnValid reports whether the card number passes the Luhn checksum.
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// isDigits reports whether s consists of ASCII digits only.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
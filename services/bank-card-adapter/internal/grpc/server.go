@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/domain"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/proto/bankcard.v1"
+)
+
+// uuidPattern matches account identifiers; they are checked before charging the card
+// so that a typo never results in a charge followed by a refund.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// BankCardAdapterServer implements the BankCardAdapter gRPC service.
+type BankCardAdapterServer struct {
+	pb.UnimplementedBankCardAdapterServer
+	topUpService *domain.CardTopUpService
+}
+
+// NewBankCardAdapterServer creates a new BankCardAdapterServer.
+func NewBankCardAdapterServer(topUpService *domain.CardTopUpService) *BankCardAdapterServer {
+	return &BankCardAdapterServer{
+		topUpService: topUpService,
+	}
+}
+
+// ProcessCardTopUp charges a bank card and credits the charged amount to an account.
+// This operation is idempotent when called with the same idempotency key.
+func (s *BankCardAdapterServer) ProcessCardTopUp(ctx context.Context, req *pb.ProcessCardTopUpRequest) (*pb.ProcessCardTopUpResponse, error) {
+	// Validate request
+	if err := validateProcessCardTopUpRequest(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	month, year, err := domain.ParseExpiryDate(req.Card.ExpiryDate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid card.expiry_date: %v", err)
+	}
+
+	// Convert proto messages to domain types
+	amount := domain.Amount{
+		Value:        req.Amount.Value,
+		CurrencyCode: req.Amount.CurrencyCode,
+	}
+	card := domain.Card{
+		Number:      req.Card.Number,
+		ExpiryMonth: month,
+		ExpiryYear:  year,
+		CVV:         req.Card.Cvv,
+	}
+
+	topUp, err := s.topUpService.ProcessCardTopUp(ctx, req.AccountId, amount, card, req.IdempotencyKey)
+	if err != nil {
+		return nil, mapDomainErrorToGRPC(err)
+	}
+
+	return &pb.ProcessCardTopUpResponse{
+		OperationId:           topUp.OperationID,
+		Status:                pb.CardTopUpStatus_CARD_TOP_UP_STATUS_SUCCESS,
+		Message:               topUp.Message,
+		Timestamp:             formatTimestamp(topUp.CompletedAt),
+		ExternalTransactionId: topUp.ChargeID,
+		CardLast4:             topUp.CardLast4,
+		NewBalance: &pb.Amount{
+			Value:        topUp.NewBalance.Value,
+			CurrencyCode: topUp.NewBalance.CurrencyCode,
+		},
+	}, nil
+}
+
+// validateProcessCardTopUpRequest validates the ProcessCardTopUpRequest.
+func validateProcessCardTopUpRequest(req *pb.ProcessCardTopUpRequest) error {
+	if req.AccountId == "" {
+		return fmt.Errorf("account_id is required")
+	}
+	if !uuidPattern.MatchString(req.AccountId) {
+		return fmt.Errorf("invalid account_id: must be a UUID")
+	}
+	if req.Amount == nil {
+		return fmt.Errorf("amount is required")
+	}
+	if req.Amount.Value == "" {
+		return fmt.Errorf("amount.value is required")
+	}
+	if req.Amount.CurrencyCode == "" {
+		return fmt.Errorf("amount.currency_code is required")
+	}
+	if req.Card == nil {
+		return fmt.Errorf("card is required")
+	}
+	if req.Card.Number == "" {
+		return fmt.Errorf("card.number is required")
+	}
+	if req.Card.ExpiryDate == "" {
+		return fmt.Errorf("card.expiry_date is required")
+	}
+	if req.Card.Cvv == "" {
+		return fmt.Errorf("card.cvv is required")
+	}
+	if req.IdempotencyKey == "" {
+		return fmt.Errorf("idempotency_key is required")
+	}
+	return nil
+}
+
+// mapDomainErrorToGRPC maps domain errors to gRPC status codes.
+func mapDomainErrorToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var declined *domain.DeclinedError
+	switch {
+	case errors.Is(err, domain.ErrInvalidCard), errors.Is(err, domain.ErrInvalidAmount):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &declined):
+		return status.Errorf(codes.FailedPrecondition, "card declined: %s", declined.Reason)
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return status.Error(codes.NotFound, "account not found")
+	case errors.Is(err, domain.ErrTopUpRejected):
+		return status.Errorf(codes.FailedPrecondition, "top-up rejected, charge refunded: %v", err)
+	case errors.Is(err, domain.ErrIdempotencyConflict):
+		return status.Error(codes.AlreadyExists, "idempotency key was already used for a different top-up")
+	case errors.Is(err, domain.ErrBankUnavailable):
+		return status.Error(codes.Unavailable, "bank service unavailable, retry with the same idempotency key")
+	default:
+		return status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+}
+
+// formatTimestamp formats a time.Time to ISO 8601 format.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,72 @@
+package grpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/domain"
+	grpcserver "github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/internal/grpc"
+	pb "github.com/spbu-ds-practicum-2025/example-project/services/bank-card-adapter/proto/bankcard.v1"
+)
+
+const accountID = "11111111-1111-1111-1111-111111111111"
+
+func validRequest() *pb.ProcessCardTopUpRequest {
+	return &pb.ProcessCardTopUpRequest{
+		AccountId:      accountID,
+		Amount:         &pb.Amount{Value: "100.00", CurrencyCode: "RUB"},
+		Card:           &pb.Card{Number: "4111111111111111", ExpiryDate: "12/30", Cvv: "123"},
+		IdempotencyKey: "key-1",
+	}
+}
+
+// TestProcessCardTopUp_ValidationErrors tests request validation
+func TestProcessCardTopUp_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(req *pb.ProcessCardTopUpRequest)
+		errContains string
+	}{
+		{"missing account_id", func(req *pb.ProcessCardTopUpRequest) { req.AccountId = "" }, "account_id is required"},
+		{"invalid account_id", func(req *pb.ProcessCardTopUpRequest) { req.AccountId = "invalid-uuid" }, "invalid account_id"},
+		{"missing amount", func(req *pb.ProcessCardTopUpRequest) { req.Amount = nil }, "amount is required"},
+		{"missing currency_code", func(req *pb.ProcessCardTopUpRequest) { req.Amount.CurrencyCode = "" }, "amount.currency_code is required"},
+		{"missing card", func(req *pb.ProcessCardTopUpRequest) { req.Card = nil }, "card is required"},
+		{"missing card number", func(req *pb.ProcessCardTopUpRequest) { req.Card.Number = "" }, "card.number is required"},
+		{"malformed expiry date", func(req *pb.ProcessCardTopUpRequest) { req.Card.ExpiryDate = "2030-12" }, "invalid card.expiry_date"},
+		{"missing idempotency_key", func(req *pb.ProcessCardTopUpRequest) { req.IdempotencyKey = "" }, "idempotency_key is required"},
+		{"bad card checksum", func(req *pb.ProcessCardTopUpRequest) { req.Card.Number = "4111111111111112" }, "checksum"},
+		{"non-positive amount", func(req *pb.ProcessCardTopUpRequest) { req.Amount.Value = "0" }, "amount must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Validation errors happen before the gateway or the bank is called
+			server := grpcserver.NewBankCardAdapterServer(domain.NewCardTopUpService(nil, nil))
+
+			req := validRequest()
+			tt.modify(req)
+
+			_, err := server.ProcessCardTopUp(context.Background(), req)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("expected gRPC status error, got: %v", err)
+			}
+
+			if st.Code() != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", st.Code())
+			}
+			if !strings.Contains(st.Message(), tt.errContains) {
+				t.Errorf("expected error message to contain %q, got %q", tt.errContains, st.Message())
+			}
+		})
+	}
+}
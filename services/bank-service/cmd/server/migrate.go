@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/config"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/migrate"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/migrations"
+)
+
+const migrateUsage = `Usage: bank-service migrate <action>
+
+Actions:
+    up              Apply all pending migrations
+    down [n]        Revert the last n migrations (default: 1)
+    status          Show the database version and pending migrations
+    to <version>    Apply or revert migrations up to the version (0 reverts all)
+    force <version> Set the version without running migrations, clearing the dirty flag`
+
+// runMigrate runs the migrate subcommand with the arguments following "migrate".
+func runMigrate(ctx context.Context, cfg config.DatabaseConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing action\n\n%s", migrateUsage)
+	}
+
+	pool, err := db.NewPoolWithConfig(ctx, cfg.URL, poolConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create database pool: %w", err)
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool.Pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	action, arg := args[0], ""
+	if len(args) > 1 {
+		arg = args[1]
+	}
+
+	switch action {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if arg != "" {
+			if steps, err = strconv.Atoi(arg); err != nil {
+				return fmt.Errorf("invalid number of migrations %q", arg)
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "to", "force":
+		version, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid version %q\n\n%s", arg, migrateUsage)
+		}
+		if action == "force" {
+			return migrator.Force(ctx, version)
+		}
+		return migrator.To(ctx, version)
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(status)
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q\n\n%s", action, migrateUsage)
+	}
+}
+
+// printMigrationStatus prints the database version and the migrations to stdout.
+func printMigrationStatus(status *migrate.Status) {
+	dirty := ""
+	if status.Dirty {
+		dirty = " (dirty)"
+	}
+	fmt.Printf("version: %d%s\n", status.Version, dirty)
+
+	for _, migration := range status.Applied {
+		fmt.Printf("  applied  %s\n", migration)
+	}
+	for _, migration := range status.Pending {
+		fmt.Printf("  pending  %s\n", migration)
+	}
+}
+
+// migrateOnStart applies pending migrations at startup if enabled in the configuration.
+func migrateOnStart(ctx context.Context, cfg *config.Config, pool *db.Pool) {
+	if !cfg.Features.MigrateOnStart {
+		return
+	}
+
+	migrator, err := migrate.New(pool.Pool, migrations.FS)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		log.Fatalf("failed to apply migrations: %v", err)
+	}
+	log.Println("database migrations applied")
+}
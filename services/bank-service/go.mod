@@ -5,9 +5,11 @@ go 1.24.0
 require (
 	github.com/jackc/pgx/v5 v5.7.6
 	github.com/rabbitmq/amqp091-go v1.5.0
+	github.com/spbu-ds-practicum-2025/example-project/services/common/health v0.0.0
 	github.com/testcontainers/testcontainers-go v0.28.0
 	google.golang.org/grpc v1.76.0
 	google.golang.org/protobuf v1.36.6
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -68,3 +70,5 @@ require (
 	golang.org/x/text v0.27.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250804133106-a7a43d27e69b // indirect
 )
+
+replace github.com/spbu-ds-practicum-2025/example-project/services/common/health => ../common/health
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envVars are the environment variables read by Load
+var envVars = []string{
+	"CONFIG_FILE", "PORT", "SHUTDOWN_TIMEOUT",
+	"MIGRATE_ON_START", "GRPC_REFLECTION", "EVENTS_ENABLED",
+	"DATABASE_URL", "DB_MAX_CONNS", "DB_MIN_CONNS", "DB_MAX_CONN_LIFETIME", "DB_MAX_CONN_IDLE_TIME", "DB_CONNECT_TIMEOUT",
+	"DB_TX_ISOLATION_LEVEL", "DB_TX_MAX_ATTEMPTS", "DB_TX_RETRY_BACKOFF", "DB_TX_MAX_BACKOFF",
+	"RABBITMQ_URL", "RABBITMQ_EXCHANGE", "RABBITMQ_ROUTING_KEY", "RABBITMQ_TOPUP_ROUTING_KEY",
+	"OUTBOX_POLL_INTERVAL", "OUTBOX_BATCH_SIZE", "OUTBOX_RETRY_BACKOFF", "OUTBOX_MAX_BACKOFF", "OUTBOX_CLAIM_LEASE",
+	"HEALTH_CHECK_INTERVAL", "HEALTH_CHECK_TIMEOUT", "METRICS_ADDR",
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		envVars  map[string]string
+		validate func(*testing.T, *Config)
+	}{
+		{
+			name:    "default values",
+			envVars: map[string]string{},
+			validate: func(t *testing.T, cfg *Config) {
+				if cfg.Port != "50051" {
+					t.Errorf("expected Port to be 50051, got %s", cfg.Port)
+				}
+				if cfg.Database.MaxConns != 25 || cfg.Database.MinConns != 5 {
+					t.Errorf("expected pool of 5 to 25 connections, got %d to %d", cfg.Database.MinConns, cfg.Database.MaxConns)
+				}
+				if cfg.Transaction.IsolationLevel != "READ COMMITTED" {
+					t.Errorf("expected READ COMMITTED isolation level, got %s", cfg.Transaction.IsolationLevel)
+				}
+				if cfg.RabbitMQ.Exchange != "bank.operations" {
+					t.Errorf("expected RabbitMQ exchange to be bank.operations, got %s", cfg.RabbitMQ.Exchange)
+				}
+				if cfg.Features.MigrateOnStart || !cfg.Features.Events || !cfg.Features.GRPCReflection {
+					t.Errorf("expected default features, got %+v", cfg.Features)
+				}
+			},
+		},
+		{
+			name: "environment values",
+			envVars: map[string]string{
+				"PORT":                  "6000",
+				"SHUTDOWN_TIMEOUT":      "5s",
+				"MIGRATE_ON_START":      "true",
+				"EVENTS_ENABLED":        "false",
+				"DATABASE_URL":          "postgres://bank:secret@db:5432/bank",
+				"DB_MAX_CONNS":          "50",
+				"DB_MIN_CONNS":          "10",
+				"DB_TX_ISOLATION_LEVEL": "SERIALIZABLE",
+				"DB_TX_MAX_ATTEMPTS":    "3",
+				"RABBITMQ_EXCHANGE":     "custom.exchange",
+				"OUTBOX_BATCH_SIZE":     "20",
+				"HEALTH_CHECK_INTERVAL": "10s",
+				"METRICS_ADDR":          ":9100",
+			},
+			validate: func(t *testing.T, cfg *Config) {
+				if cfg.Port != "6000" {
+					t.Errorf("expected Port to be 6000, got %s", cfg.Port)
+				}
+				if cfg.ShutdownTimeout != 5*time.Second {
+					t.Errorf("expected shutdown timeout to be 5s, got %s", cfg.ShutdownTimeout)
+				}
+				if !cfg.Features.MigrateOnStart || cfg.Features.Events {
+					t.Errorf("expected migrations on start without events, got %+v", cfg.Features)
+				}
+				if cfg.Database.URL != "postgres://bank:secret@db:5432/bank" {
+					t.Errorf("expected database URL from environment, got %s", cfg.Database.URL)
+				}
+				if cfg.Database.MaxConns != 50 || cfg.Database.MinConns != 10 {
+					t.Errorf("expected pool of 10 to 50 connections, got %d to %d", cfg.Database.MinConns, cfg.Database.MaxConns)
+				}
+				if cfg.Transaction.IsolationLevel != "SERIALIZABLE" || cfg.Transaction.MaxAttempts != 3 {
+					t.Errorf("expected SERIALIZABLE transactions with 3 attempts, got %+v", cfg.Transaction)
+				}
+				if cfg.RabbitMQ.Exchange != "custom.exchange" {
+					t.Errorf("expected RabbitMQ exchange to be custom.exchange, got %s", cfg.RabbitMQ.Exchange)
+				}
+				if cfg.Outbox.BatchSize != 20 {
+					t.Errorf("expected outbox batch size to be 20, got %d", cfg.Outbox.BatchSize)
+				}
+				if cfg.Health.CheckInterval != 10*time.Second || cfg.Health.CheckTimeout != 2*time.Second {
+					t.Errorf("expected health checks every 10s with the default 2s timeout, got %+v", cfg.Health)
+				}
+				if cfg.Metrics.Addr != ":9100" {
+					t.Errorf("expected metrics address to be :9100, got %s", cfg.Metrics.Addr)
+				}
+			},
+		},
+		{
+			name: "file values overridden by environment",
+			file: `
+port: "7000"
+shutdown_timeout: 1m
+features:
+  events: false
+database:
+  url: postgres://file@db/bank
+  max_conns: 40
+  connect_timeout: 3s
+rabbitmq:
+  exchange: file.exchange
+`,
+			envVars: map[string]string{
+				"DB_MAX_CONNS": "60",
+			},
+			validate: func(t *testing.T, cfg *Config) {
+				if cfg.Port != "7000" {
+					t.Errorf("expected Port from file to be 7000, got %s", cfg.Port)
+				}
+				if cfg.ShutdownTimeout != time.Minute {
+					t.Errorf("expected shutdown timeout from file to be 1m, got %s", cfg.ShutdownTimeout)
+				}
+				if cfg.Features.Events || !cfg.Features.GRPCReflection {
+					t.Errorf("expected events disabled and other features left at defaults, got %+v", cfg.Features)
+				}
+				if cfg.Database.URL != "postgres://file@db/bank" || cfg.Database.ConnectTimeout != 3*time.Second {
+					t.Errorf("expected database settings from file, got %+v", cfg.Database)
+				}
+				if cfg.Database.MaxConns != 60 {
+					t.Errorf("expected environment to override max conns to 60, got %d", cfg.Database.MaxConns)
+				}
+				if cfg.Database.MinConns != 5 {
+					t.Errorf("expected default min conns 5, got %d", cfg.Database.MinConns)
+				}
+				if cfg.RabbitMQ.Exchange != "file.exchange" {
+					t.Errorf("expected RabbitMQ exchange from file, got %s", cfg.RabbitMQ.Exchange)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			if tt.file != "" {
+				t.Setenv("CONFIG_FILE", writeConfigFile(t, tt.file))
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, cfg)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		envVars  map[string]string
+		expected []string
+	}{
+		{
+			name:     "invalid environment values",
+			envVars:  map[string]string{"DB_MAX_CONNS": "many", "SHUTDOWN_TIMEOUT": "soon"},
+			expected: []string{"DB_MAX_CONNS", "SHUTDOWN_TIMEOUT"},
+		},
+		{
+			name:     "unknown file field",
+			file:     "database:\n  max_connections: 10\n",
+			expected: []string{"max_connections"},
+		},
+		{
+			name:     "missing file",
+			envVars:  map[string]string{"CONFIG_FILE": filepath.Join(os.TempDir(), "missing-bank-config.yaml")},
+			expected: []string{"failed to open config file"},
+		},
+		{
+			name: "invalid settings",
+			envVars: map[string]string{
+				"PORT":                  "http",
+				"DB_MAX_CONNS":          "4",
+				"DB_MIN_CONNS":          "8",
+				"DB_TX_ISOLATION_LEVEL": "READ UNCOMMITTED",
+				"OUTBOX_MAX_BACKOFF":    "1ms",
+				"HEALTH_CHECK_TIMEOUT":  "0s",
+			},
+			expected: []string{"port", "database.min_conns", "transaction.isolation_level", "outbox.max_backoff", "health.check_timeout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			if tt.file != "" {
+				t.Setenv("CONFIG_FILE", writeConfigFile(t, tt.file))
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			_, err := Load()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error to mention %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_EventsDisabled(t *testing.T) {
+	cfg := Default()
+	cfg.Features.Events = false
+	cfg.RabbitMQ = RabbitMQConfig{}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected RabbitMQ settings to be optional without events, got %v", err)
+	}
+}
+
+// clearEnv unsets the configuration environment variables for the test
+func clearEnv(t *testing.T) {
+	for _, key := range envVars {
+		t.Setenv(key, "")
+	}
+}
+
+// writeConfigFile writes the YAML content to a temporary file and returns its path
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
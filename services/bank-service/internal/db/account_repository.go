@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,16 +24,65 @@ func NewAccountRepository(pool *pgxpool.Pool) *AccountRepository {
 	}
 }
 
+// accountColumns is the column list scanned by scanAccount.
+const accountColumns = `
+	id, COALESCE(owner_id, ''), COALESCE(owner_name, ''),
+	balance_value, balance_currency_code, status,
+	created_at, updated_at, closed_at
+`
+
+// Create persists a new account.
+func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) error {
+	query := `
+		INSERT INTO accounts (
+			id, owner_id, owner_name,
+			balance_value, balance_currency_code, status,
+			created_at, updated_at, closed_at
+		) VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, $8, $9)
+	`
+
+	// Use transaction if available, otherwise use pool
+	var err error
+	if tx := getTx(ctx); tx != nil {
+		_, err = tx.Exec(ctx, query,
+			account.ID,
+			account.OwnerID,
+			account.OwnerName,
+			account.Balance.Value.String(),
+			account.Balance.CurrencyCode,
+			string(account.Status),
+			account.CreatedAt,
+			account.UpdatedAt,
+			account.ClosedAt,
+		)
+	} else {
+		_, err = r.pool.Exec(ctx, query,
+			account.ID,
+			account.OwnerID,
+			account.OwnerName,
+			account.Balance.Value.String(),
+			account.Balance.CurrencyCode,
+			string(account.Status),
+			account.CreatedAt,
+			account.UpdatedAt,
+			account.ClosedAt,
+		)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to create account: %w", translateError(err))
+	}
+
+	return nil
+}
+
 // GetByID retrieves an account by its unique identifier.
 func (r *AccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Account, error) {
-	query := `
-		SELECT id, balance_value, balance_currency_code, created_at, updated_at
+	query := `SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE id = $1
 	`
 
-	var account domain.Account
-
 	// Use transaction if available, otherwise use pool
 	var row pgx.Row
 	if tx := getTx(ctx); tx != nil {
@@ -41,14 +91,7 @@ func (r *AccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 		row = r.pool.QueryRow(ctx, query, id)
 	}
 
-	err := row.Scan(
-		&account.ID,
-		&account.Balance.Value,
-		&account.Balance.CurrencyCode,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
-
+	account, err := scanAccount(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrAccountNotFound
@@ -56,7 +99,7 @@ func (r *AccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
 
-	return &account, nil
+	return account, nil
 }
 
 // Update persists changes to an existing account.
@@ -65,7 +108,10 @@ func (r *AccountRepository) Update(ctx context.Context, account *domain.Account)
 		UPDATE accounts
 		SET balance_value = $2,
 		    balance_currency_code = $3,
-		    updated_at = $4
+		    updated_at = $4,
+		    owner_name = NULLIF($5, ''),
+		    status = $6,
+		    closed_at = $7
 		WHERE id = $1
 	`
 
@@ -76,25 +122,31 @@ func (r *AccountRepository) Update(ctx context.Context, account *domain.Account)
 	if tx := getTx(ctx); tx != nil {
 		result, execErr := tx.Exec(ctx, query,
 			account.ID,
-			account.Balance.Value,
+			account.Balance.Value.String(),
 			account.Balance.CurrencyCode,
 			account.UpdatedAt,
+			account.OwnerName,
+			string(account.Status),
+			account.ClosedAt,
 		)
 		err = execErr
 		rowsAffected = result.RowsAffected()
 	} else {
 		result, execErr := r.pool.Exec(ctx, query,
 			account.ID,
-			account.Balance.Value,
+			account.Balance.Value.String(),
 			account.Balance.CurrencyCode,
 			account.UpdatedAt,
+			account.OwnerName,
+			string(account.Status),
+			account.ClosedAt,
 		)
 		err = execErr
 		rowsAffected = result.RowsAffected()
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to update account: %w", err)
+		return fmt.Errorf("failed to update account: %w", translateError(err))
 	}
 
 	if rowsAffected == 0 {
@@ -108,15 +160,12 @@ func (r *AccountRepository) Update(ctx context.Context, account *domain.Account)
 // This method MUST be called within a transaction context.
 // Uses SELECT ... FOR UPDATE to lock the row.
 func (r *AccountRepository) Lock(ctx context.Context, id uuid.UUID) (*domain.Account, error) {
-	query := `
-		SELECT id, balance_value, balance_currency_code, created_at, updated_at
+	query := `SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE id = $1
 		FOR UPDATE
 	`
 
-	var account domain.Account
-
 	// Use transaction if available, otherwise use pool
 	var row pgx.Row
 	if tx := getTx(ctx); tx != nil {
@@ -125,20 +174,97 @@ func (r *AccountRepository) Lock(ctx context.Context, id uuid.UUID) (*domain.Acc
 		row = r.pool.QueryRow(ctx, query, id)
 	}
 
+	account, err := scanAccount(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", translateError(err))
+	}
+
+	return account, nil
+}
+
+// List retrieves accounts matching the filter ordered by (created_at, id).
+// Uses keyset pagination: the cursor in the filter selects the accounts after the given one.
+func (r *AccountRepository) List(ctx context.Context, filter domain.AccountFilter) ([]*domain.Account, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.OwnerID != "" {
+		args = append(args, filter.OwnerID)
+		conditions = append(conditions, fmt.Sprintf("owner_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.AfterID != uuid.Nil {
+		args = append(args, filter.AfterCreatedAt, filter.AfterID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) > ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := `SELECT ` + accountColumns + ` FROM accounts`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at, id LIMIT $%d", len(args))
+
+	// Use transaction if available, otherwise use pool
+	var rows pgx.Rows
+	var err error
+	if tx := getTx(ctx); tx != nil {
+		rows, err = tx.Query(ctx, query, args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*domain.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// scanAccount scans an accounts row selected with accountColumns.
+func scanAccount(row pgx.Row) (*domain.Account, error) {
+	var account domain.Account
+	var balance string
+	var status string
+
 	err := row.Scan(
 		&account.ID,
-		&account.Balance.Value,
+		&account.OwnerID,
+		&account.OwnerName,
+		&balance,
 		&account.Balance.CurrencyCode,
+		&status,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+		&account.ClosedAt,
 	)
-
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, domain.ErrAccountNotFound
-		}
-		return nil, fmt.Errorf("failed to lock account: %w", err)
+		return nil, err
+	}
+
+	if account.Balance.Value, err = domain.ParseMoney(balance); err != nil {
+		return nil, fmt.Errorf("failed to parse account balance: %w", err)
 	}
 
+	account.Status = domain.AccountStatus(status)
 	return &account, nil
 }
@@ -0,0 +1,530 @@
+// Package dbtest provides the contract test suite of the bank-service repositories.
+//
+// The suite is run against every implementation (PostgreSQL and in-memory),
+// so that tests using the in-memory repositories can rely on the same behavior:
+// errors, ordering, transaction visibility and locking.
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// lockWait is how long a transaction is expected to stay blocked on a lock held by another one
+const lockWait = 100 * time.Millisecond
+
+// Repositories are the implementations under test.
+type Repositories struct {
+	Accounts  domain.AccountRepository
+	Transfers domain.TransferRepository
+	TxManager domain.TransactionManager
+}
+
+// Run runs the contract test suite.
+// newRepositories is called for every test and must return repositories backed by empty storage.
+func Run(t *testing.T, newRepositories func(t *testing.T) Repositories) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repos Repositories)
+	}{
+		{"AccountCreateAndGet", testAccountCreateAndGet},
+		{"AccountUpdate", testAccountUpdate},
+		{"AccountCheckConstraint", testAccountCheckConstraint},
+		{"AccountList", testAccountList},
+		{"TransferCreateAndGet", testTransferCreateAndGet},
+		{"TransferDuplicateIdempotencyKey", testTransferDuplicateIdempotencyKey},
+		{"TransferUpdate", testTransferUpdate},
+		{"TransferList", testTransferList},
+		{"RollbackDiscardsChanges", testRollbackDiscardsChanges},
+		{"UncommittedChangesAreInvisible", testUncommittedChangesAreInvisible},
+		{"AccountLockWaitsForCommit", testAccountLockWaitsForCommit},
+		{"IdempotencyKeyLockWaitsForCommit", testIdempotencyKeyLockWaitsForCommit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepositories(t))
+		})
+	}
+}
+
+// now returns the current time with the precision PostgreSQL stores
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// createAccount persists an ACTIVE account of the owner with the given balance in minor units
+func createAccount(t *testing.T, repos Repositories, ownerID string, balance int64, createdAt time.Time) *domain.Account {
+	t.Helper()
+
+	account := domain.OpenAccount(ownerID, "Test Owner", "RUB")
+	account.Balance.Value = domain.NewMoney(balance)
+	account.CreatedAt = createdAt
+	account.UpdatedAt = createdAt
+	if err := repos.Accounts.Create(context.Background(), account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	return account
+}
+
+// newTransfer returns a SUCCESS transfer between the accounts
+func newTransfer(senderID, recipientID uuid.UUID, amount int64, createdAt time.Time) *domain.Transfer {
+	transfer := domain.NewTransfer(senderID, recipientID, domain.Amount{Value: domain.NewMoney(amount), CurrencyCode: "RUB"}, uuid.New().String())
+	transfer.CreatedAt = createdAt
+	transfer.MarkAsSuccess("Transfer completed successfully")
+	completedAt := createdAt.Add(time.Millisecond)
+	transfer.CompletedAt = &completedAt
+	return transfer
+}
+
+// createTransfer persists a SUCCESS transfer between the accounts
+func createTransfer(t *testing.T, repos Repositories, senderID, recipientID uuid.UUID, amount int64, createdAt time.Time) *domain.Transfer {
+	t.Helper()
+
+	transfer := newTransfer(senderID, recipientID, amount, createdAt)
+	if err := repos.Transfers.Create(context.Background(), transfer); err != nil {
+		t.Fatalf("failed to create transfer: %v", err)
+	}
+	return transfer
+}
+
+// balanceOf returns the committed balance of the account in minor units
+func balanceOf(t *testing.T, repos Repositories, id uuid.UUID) int64 {
+	t.Helper()
+
+	account, err := repos.Accounts.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	return account.Balance.Value.MinorUnits()
+}
+
+func testAccountCreateAndGet(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	account := createAccount(t, repos, "owner-1", 10050, now())
+
+	got, err := repos.Accounts.GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != account.ID || got.OwnerID != "owner-1" || got.OwnerName != "Test Owner" ||
+		got.Balance != account.Balance || got.Status != domain.AccountStatusActive ||
+		!got.CreatedAt.Equal(account.CreatedAt) || got.ClosedAt != nil {
+		t.Errorf("expected %+v, got %+v", account, got)
+	}
+
+	if _, err := repos.Accounts.GetByID(ctx, uuid.New()); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func testAccountUpdate(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	account := createAccount(t, repos, "owner-1", 0, now())
+
+	if err := account.Close(); err != nil {
+		t.Fatalf("failed to close account: %v", err)
+	}
+	if err := repos.Accounts.Update(ctx, account); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := repos.Accounts.GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != domain.AccountStatusClosed || got.ClosedAt == nil {
+		t.Errorf("expected CLOSED account with closed_at, got %+v", got)
+	}
+
+	missing := domain.OpenAccount("owner-1", "", "RUB")
+	if err := repos.Accounts.Update(ctx, missing); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("expected ErrAccountNotFound, got %v", err)
+	}
+	if _, err := repos.Accounts.Lock(ctx, missing.ID); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("expected ErrAccountNotFound from Lock, got %v", err)
+	}
+}
+
+func testAccountCheckConstraint(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	account := createAccount(t, repos, "owner-1", 100, now())
+
+	account.Balance.Value = domain.NewMoney(-1)
+	if err := repos.Accounts.Update(ctx, account); !errors.Is(err, domain.ErrConstraintViolation) {
+		t.Errorf("expected ErrConstraintViolation, got %v", err)
+	}
+	if balance := balanceOf(t, repos, account.ID); balance != 100 {
+		t.Errorf("expected balance 100, got %d", balance)
+	}
+}
+
+func testAccountList(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	base := now()
+
+	var owned []*domain.Account
+	for i := 0; i < 3; i++ {
+		owned = append(owned, createAccount(t, repos, "owner-1", 0, base.Add(time.Duration(i)*time.Second)))
+	}
+	createAccount(t, repos, "owner-2", 0, base)
+
+	firstPage, err := repos.Accounts.List(ctx, domain.AccountFilter{OwnerID: "owner-1", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(firstPage) != 2 || firstPage[0].ID != owned[0].ID || firstPage[1].ID != owned[1].ID {
+		t.Fatalf("expected the two oldest accounts of owner-1, got %d accounts", len(firstPage))
+	}
+
+	last := firstPage[len(firstPage)-1]
+	secondPage, err := repos.Accounts.List(ctx, domain.AccountFilter{
+		OwnerID:        "owner-1",
+		AfterCreatedAt: last.CreatedAt,
+		AfterID:        last.ID,
+		Limit:          2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secondPage) != 1 || secondPage[0].ID != owned[2].ID {
+		t.Errorf("expected the newest account of owner-1, got %d accounts", len(secondPage))
+	}
+
+	all, err := repos.Accounts.List(ctx, domain.AccountFilter{Status: domain.AccountStatusActive, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 4 {
+		t.Errorf("expected 4 ACTIVE accounts, got %d", len(all))
+	}
+}
+
+func testTransferCreateAndGet(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sender := createAccount(t, repos, "owner-1", 0, now())
+	recipient := createAccount(t, repos, "owner-2", 0, now())
+	transfer := createTransfer(t, repos, sender.ID, recipient.ID, 10050, now())
+
+	byID, err := repos.Transfers.GetByID(ctx, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byID.IdempotencyKey != transfer.IdempotencyKey || byID.Amount != transfer.Amount ||
+		byID.Status != domain.TransferStatusSuccess || byID.FailureReason != "" ||
+		!byID.CreatedAt.Equal(transfer.CreatedAt) || byID.CompletedAt == nil || !byID.CompletedAt.Equal(*transfer.CompletedAt) {
+		t.Errorf("expected %+v, got %+v", transfer, byID)
+	}
+
+	byKey, err := repos.Transfers.GetByIdempotencyKey(ctx, transfer.IdempotencyKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byKey == nil || byKey.ID != transfer.ID {
+		t.Errorf("expected transfer %s by idempotency key, got %+v", transfer.ID, byKey)
+	}
+
+	if _, err := repos.Transfers.GetByID(ctx, uuid.New()); !errors.Is(err, domain.ErrTransferNotFound) {
+		t.Errorf("expected ErrTransferNotFound, got %v", err)
+	}
+	missing, err := repos.Transfers.GetByIdempotencyKey(ctx, "missing")
+	if err != nil || missing != nil {
+		t.Errorf("expected no transfer and no error for unknown key, got %+v, %v", missing, err)
+	}
+}
+
+func testTransferDuplicateIdempotencyKey(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sender := createAccount(t, repos, "owner-1", 0, now())
+	recipient := createAccount(t, repos, "owner-2", 0, now())
+	transfer := createTransfer(t, repos, sender.ID, recipient.ID, 100, now())
+
+	duplicate := newTransfer(sender.ID, recipient.ID, 200, now())
+	duplicate.IdempotencyKey = transfer.IdempotencyKey
+	if err := repos.Transfers.Create(ctx, duplicate); !errors.Is(err, domain.ErrDuplicateIdempotencyKey) {
+		t.Errorf("expected ErrDuplicateIdempotencyKey, got %v", err)
+	}
+}
+
+func testTransferUpdate(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sender := createAccount(t, repos, "owner-1", 0, now())
+	recipient := createAccount(t, repos, "owner-2", 0, now())
+
+	transfer := domain.NewTransfer(sender.ID, recipient.ID, domain.Amount{Value: domain.NewMoney(100), CurrencyCode: "RUB"}, uuid.New().String())
+	transfer.CreatedAt = now()
+	if err := repos.Transfers.Create(ctx, transfer); err != nil {
+		t.Fatalf("failed to create transfer: %v", err)
+	}
+
+	transfer.MarkAsFailed(domain.TransferFailureInternal, "Failed to credit recipient")
+	if err := repos.Transfers.Update(ctx, transfer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := repos.Transfers.GetByID(ctx, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != domain.TransferStatusFailed || got.FailureReason != domain.TransferFailureInternal ||
+		got.Message != "Failed to credit recipient" || got.CompletedAt == nil {
+		t.Errorf("expected FAILED transfer with INTERNAL reason, got %+v", got)
+	}
+
+	missing := newTransfer(sender.ID, recipient.ID, 100, now())
+	if err := repos.Transfers.Update(ctx, missing); !errors.Is(err, domain.ErrTransferNotFound) {
+		t.Errorf("expected ErrTransferNotFound, got %v", err)
+	}
+}
+
+func testTransferList(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	base := now()
+	account := createAccount(t, repos, "owner-1", 0, base)
+	other := createAccount(t, repos, "owner-2", 0, base)
+	unrelated := createAccount(t, repos, "owner-3", 0, base)
+
+	sent := createTransfer(t, repos, account.ID, other.ID, 100, base.Add(time.Second))
+	received := createTransfer(t, repos, other.ID, account.ID, 200, base.Add(2*time.Second))
+	createTransfer(t, repos, other.ID, unrelated.ID, 300, base.Add(3*time.Second))
+
+	failed := newTransfer(account.ID, other.ID, 400, base.Add(4*time.Second))
+	failed.MarkAsFailed(domain.TransferFailureInsufficientFunds, "Insufficient funds")
+	if err := repos.Transfers.Create(ctx, failed); err != nil {
+		t.Fatalf("failed to create transfer: %v", err)
+	}
+
+	// Most recent first, both directions
+	firstPage, err := repos.Transfers.List(ctx, domain.TransferFilter{AccountID: account.ID, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(firstPage) != 2 || firstPage[0].ID != failed.ID || firstPage[1].ID != received.ID {
+		t.Fatalf("expected the failed and received transfers, got %d transfers", len(firstPage))
+	}
+
+	last := firstPage[len(firstPage)-1]
+	secondPage, err := repos.Transfers.List(ctx, domain.TransferFilter{
+		AccountID:       account.ID,
+		BeforeCreatedAt: last.CreatedAt,
+		BeforeID:        last.ID,
+		Limit:           2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secondPage) != 1 || secondPage[0].ID != sent.ID {
+		t.Errorf("expected the sent transfer, got %d transfers", len(secondPage))
+	}
+
+	succeeded, err := repos.Transfers.List(ctx, domain.TransferFilter{AccountID: account.ID, Status: domain.TransferStatusSuccess, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(succeeded) != 2 {
+		t.Errorf("expected 2 SUCCESS transfers, got %d", len(succeeded))
+	}
+}
+
+func testRollbackDiscardsChanges(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sender := createAccount(t, repos, "owner-1", 100, now())
+	recipient := createAccount(t, repos, "owner-2", 0, now())
+	transfer := newTransfer(sender.ID, recipient.ID, 100, now())
+
+	errRollback := errors.New("rollback")
+	err := repos.TxManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		account, err := repos.Accounts.Lock(txCtx, sender.ID)
+		if err != nil {
+			return err
+		}
+		account.Balance.Value = domain.NewMoney(0)
+		if err := repos.Accounts.Update(txCtx, account); err != nil {
+			return err
+		}
+		if err := repos.Transfers.Create(txCtx, transfer); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected the function error, got %v", err)
+	}
+
+	if balance := balanceOf(t, repos, sender.ID); balance != 100 {
+		t.Errorf("expected balance 100 after rollback, got %d", balance)
+	}
+	if _, err := repos.Transfers.GetByID(ctx, transfer.ID); !errors.Is(err, domain.ErrTransferNotFound) {
+		t.Errorf("expected transfer to be rolled back, got %v", err)
+	}
+
+	// The idempotency key is free again
+	if err := repos.Transfers.Create(ctx, transfer); err != nil {
+		t.Errorf("unexpected error creating the transfer again: %v", err)
+	}
+}
+
+func testUncommittedChangesAreInvisible(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	account := createAccount(t, repos, "owner-1", 100, now())
+
+	err := repos.TxManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		locked, err := repos.Accounts.Lock(txCtx, account.ID)
+		if err != nil {
+			return err
+		}
+		locked.Balance.Value = domain.NewMoney(50)
+		if err := repos.Accounts.Update(txCtx, locked); err != nil {
+			return err
+		}
+
+		inside, err := repos.Accounts.GetByID(txCtx, account.ID)
+		if err != nil {
+			return err
+		}
+		if inside.Balance.Value.MinorUnits() != 50 {
+			t.Errorf("expected the transaction to see its own change, got %s", inside.Balance.Value)
+		}
+		if outside := balanceOf(t, repos, account.ID); outside != 100 {
+			t.Errorf("expected uncommitted change to be invisible outside the transaction, got %d", outside)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if balance := balanceOf(t, repos, account.ID); balance != 50 {
+		t.Errorf("expected balance 50 after commit, got %d", balance)
+	}
+}
+
+func testAccountLockWaitsForCommit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	account := createAccount(t, repos, "owner-1", 100, now())
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- repos.TxManager.WithTransaction(ctx, func(txCtx context.Context) error {
+			held, err := repos.Accounts.Lock(txCtx, account.ID)
+			if err != nil {
+				return err
+			}
+			held.Balance.Value = domain.NewMoney(50)
+			if err := repos.Accounts.Update(txCtx, held); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+
+	acquired := make(chan *domain.Account, 1)
+	second := make(chan error, 1)
+	go func() {
+		second <- repos.TxManager.WithTransaction(ctx, func(txCtx context.Context) error {
+			held, err := repos.Accounts.Lock(txCtx, account.ID)
+			if err != nil {
+				return err
+			}
+			acquired <- held
+			return nil
+		})
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected the lock to wait while another transaction holds it")
+	case <-time.After(lockWait):
+	}
+
+	close(release)
+	if err := <-first; err != nil {
+		t.Fatalf("unexpected error in the first transaction: %v", err)
+	}
+
+	select {
+	case held := <-acquired:
+		if held.Balance.Value.MinorUnits() != 50 {
+			t.Errorf("expected the lock to return the committed balance 50, got %s", held.Balance.Value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the lock to be acquired after commit")
+	}
+	if err := <-second; err != nil {
+		t.Errorf("unexpected error in the second transaction: %v", err)
+	}
+}
+
+func testIdempotencyKeyLockWaitsForCommit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sender := createAccount(t, repos, "owner-1", 0, now())
+	recipient := createAccount(t, repos, "owner-2", 0, now())
+	transfer := newTransfer(sender.ID, recipient.ID, 100, now())
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- repos.TxManager.WithTransaction(ctx, func(txCtx context.Context) error {
+			if err := repos.Transfers.LockIdempotencyKey(txCtx, transfer.IdempotencyKey); err != nil {
+				return err
+			}
+			if err := repos.Transfers.Create(txCtx, transfer); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+
+	found := make(chan *domain.Transfer, 1)
+	second := make(chan error, 1)
+	go func() {
+		second <- repos.TxManager.WithTransaction(ctx, func(txCtx context.Context) error {
+			if err := repos.Transfers.LockIdempotencyKey(txCtx, transfer.IdempotencyKey); err != nil {
+				return err
+			}
+			existing, err := repos.Transfers.GetByIdempotencyKey(txCtx, transfer.IdempotencyKey)
+			if err != nil {
+				return err
+			}
+			found <- existing
+			return nil
+		})
+	}()
+
+	select {
+	case <-found:
+		t.Fatal("expected the idempotency key lock to wait while another transaction holds it")
+	case <-time.After(lockWait):
+	}
+
+	close(release)
+	if err := <-first; err != nil {
+		t.Fatalf("unexpected error in the first transaction: %v", err)
+	}
+
+	select {
+	case existing := <-found:
+		if existing == nil || existing.ID != transfer.ID {
+			t.Errorf("expected the committed transfer %s, got %+v", transfer.ID, existing)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the idempotency key lock to be acquired after commit")
+	}
+	if err := <-second; err != nil {
+		t.Errorf("unexpected error in the second transaction: %v", err)
+	}
+}
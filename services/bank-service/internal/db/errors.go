@@ -0,0 +1,58 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// PostgreSQL error codes translated into domain errors.
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgNumericOutOfRange    = "22003"
+	pgUniqueViolation      = "23505"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// translateError translates a PostgreSQL error into the matching domain error.
+// The original error is kept in the chain, so it can still be inspected with errors.As.
+// Errors without a domain counterpart are returned unchanged.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var domainErr error
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		// Idempotency keys are declared UNIQUE inline, so their constraints are named <table>_idempotency_key_key
+		if strings.HasSuffix(pgErr.ConstraintName, "_idempotency_key_key") {
+			domainErr = domain.ErrDuplicateIdempotencyKey
+		}
+	case pgCheckViolation:
+		domainErr = domain.ErrConstraintViolation
+	case pgNumericOutOfRange:
+		// Money is bounded to the NUMERIC(15,2) range, so this only happens
+		// if a value bypassed its checks
+		domainErr = domain.ErrMoneyOverflow
+	case pgSerializationFailure, pgDeadlockDetected:
+		domainErr = domain.ErrSerializationFailure
+	}
+
+	if domainErr == nil || errors.Is(err, domainErr) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", domainErr, err)
+}
+
+// isRetryable reports whether the transaction failed with err can succeed when run again,
+// i.e. it was aborted by a serialization failure or a deadlock.
+func isRetryable(err error) bool {
+	return errors.Is(translateError(err), domain.ErrSerializationFailure)
+}
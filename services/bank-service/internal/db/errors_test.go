@@ -0,0 +1,65 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{"duplicate transfer idempotency key", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "transfers_idempotency_key_key"}, domain.ErrDuplicateIdempotencyKey},
+		{"duplicate top-up idempotency key", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "topups_idempotency_key_key"}, domain.ErrDuplicateIdempotencyKey},
+		{"check violation", &pgconn.PgError{Code: pgCheckViolation, ConstraintName: "accounts_balance_value_check"}, domain.ErrConstraintViolation},
+		{"numeric out of range", &pgconn.PgError{Code: pgNumericOutOfRange}, domain.ErrMoneyOverflow},
+		{"serialization failure", &pgconn.PgError{Code: pgSerializationFailure}, domain.ErrSerializationFailure},
+		{"deadlock", &pgconn.PgError{Code: pgDeadlockDetected}, domain.ErrSerializationFailure},
+		{"wrapped error", fmt.Errorf("failed to lock account: %w", &pgconn.PgError{Code: pgDeadlockDetected}), domain.ErrSerializationFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+
+			// The PostgreSQL error is kept in the chain
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) {
+				t.Errorf("expected PostgreSQL error to be kept, got %v", err)
+			}
+
+			// Translating twice does not wrap again
+			if again := translateError(err); again != err {
+				t.Errorf("expected error to be translated once, got %v", again)
+			}
+		})
+	}
+}
+
+func TestTranslateError_Unchanged(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"not a PostgreSQL error", errors.New("connection refused")},
+		{"unique violation of another constraint", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "transfers_pkey"}},
+		{"other PostgreSQL error", &pgconn.PgError{Code: "42P01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := translateError(tt.err); err != tt.err {
+				t.Errorf("expected error unchanged, got %v", err)
+			}
+		})
+	}
+}
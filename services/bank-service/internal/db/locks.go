@@ -0,0 +1,14 @@
+package db
+
+// Advisory lock classes, used as the first key of the two-key pg_advisory_xact_lock.
+// Each purpose locks within its own class, so an idempotency key can never contend
+// with an outbox claim or with an equal key used for another purpose.
+// The single-key advisory locks of the migrator live in a separate PostgreSQL key space.
+const (
+	lockClassTransferKey int32 = 1
+	lockClassTopUpKey    int32 = 2
+	lockClassOutboxClaim int32 = 3
+)
+
+// advisoryXactLockQuery acquires a transaction-scoped advisory lock on a key within a lock class.
+const advisoryXactLockQuery = `SELECT pg_advisory_xact_lock($1, hashtext($2))`
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// AccountRepository implements domain.AccountRepository in memory.
+type AccountRepository struct {
+	store *Store
+}
+
+// NewAccountRepository creates a new AccountRepository.
+func NewAccountRepository(store *Store) *AccountRepository {
+	return &AccountRepository{
+		store: store,
+	}
+}
+
+// accountLock returns the name of the row lock of the account.
+func accountLock(id uuid.UUID) string {
+	return "account:" + id.String()
+}
+
+// Create persists a new account.
+func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) error {
+	return r.store.inTx(ctx, func(t *tx) error {
+		if err := t.lock(ctx, accountLock(account.ID)); err != nil {
+			return fmt.Errorf("failed to create account: %w", err)
+		}
+		if t.account(account.ID) != nil {
+			return fmt.Errorf("failed to create account: account %s already exists", account.ID)
+		}
+		if err := checkAccount(account); err != nil {
+			return fmt.Errorf("failed to create account: %w", err)
+		}
+
+		t.accounts[account.ID] = copyAccount(account)
+		return nil
+	})
+}
+
+// GetByID retrieves an account by its unique identifier.
+func (r *AccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Account, error) {
+	var account *domain.Account
+	err := r.store.inTx(ctx, func(t *tx) error {
+		account = t.account(id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	return copyAccount(account), nil
+}
+
+// Update persists changes to an existing account.
+// The account stays locked until the transaction ends.
+func (r *AccountRepository) Update(ctx context.Context, account *domain.Account) error {
+	return r.store.inTx(ctx, func(t *tx) error {
+		if err := t.lock(ctx, accountLock(account.ID)); err != nil {
+			return fmt.Errorf("failed to update account: %w", err)
+		}
+
+		existing := t.account(account.ID)
+		if existing == nil {
+			return domain.ErrAccountNotFound
+		}
+		if err := checkAccount(account); err != nil {
+			return fmt.Errorf("failed to update account: %w", err)
+		}
+
+		// Owner and creation time are not updatable
+		updated := copyAccount(account)
+		updated.OwnerID = existing.OwnerID
+		updated.CreatedAt = existing.CreatedAt
+		t.accounts[account.ID] = updated
+		return nil
+	})
+}
+
+// Lock acquires a lock on the account for the duration of the transaction.
+// This method MUST be called within a transaction context; outside of one,
+// the lock is released as soon as the account is read.
+// Waits while another transaction holds the lock.
+func (r *AccountRepository) Lock(ctx context.Context, id uuid.UUID) (*domain.Account, error) {
+	var account *domain.Account
+	err := r.store.inTx(ctx, func(t *tx) error {
+		if err := t.lock(ctx, accountLock(id)); err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		account = t.account(id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	return copyAccount(account), nil
+}
+
+// List retrieves accounts matching the filter ordered by (created_at, id).
+// The cursor in the filter selects the accounts after the given one.
+func (r *AccountRepository) List(ctx context.Context, filter domain.AccountFilter) ([]*domain.Account, error) {
+	var visible []*domain.Account
+	err := r.store.inTx(ctx, func(t *tx) error {
+		visible = t.visibleAccounts()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(visible, func(i, j int) bool {
+		return keysetLess(visible[i].CreatedAt, visible[i].ID, visible[j].CreatedAt, visible[j].ID)
+	})
+
+	var accounts []*domain.Account
+	for _, account := range visible {
+		if len(accounts) >= filter.Limit {
+			break
+		}
+		if filter.OwnerID != "" && account.OwnerID != filter.OwnerID {
+			continue
+		}
+		if filter.Status != "" && account.Status != filter.Status {
+			continue
+		}
+		if filter.AfterID != uuid.Nil && !keysetLess(filter.AfterCreatedAt, filter.AfterID, account.CreatedAt, account.ID) {
+			continue
+		}
+		accounts = append(accounts, copyAccount(account))
+	}
+
+	return accounts, nil
+}
+
+// checkAccount enforces the constraints of the accounts table.
+func checkAccount(account *domain.Account) error {
+	switch {
+	case account.Balance.Value.IsNegative():
+		return fmt.Errorf("%w: balance must not be negative", domain.ErrConstraintViolation)
+	case len(account.Balance.CurrencyCode) != 3:
+		return fmt.Errorf("%w: currency code must have 3 letters", domain.ErrConstraintViolation)
+	case account.Status != domain.AccountStatusActive &&
+		account.Status != domain.AccountStatusFrozen &&
+		account.Status != domain.AccountStatusClosed:
+		return fmt.Errorf("%w: unknown account status %q", domain.ErrConstraintViolation, account.Status)
+	case (account.Status == domain.AccountStatusClosed) != (account.ClosedAt != nil):
+		return fmt.Errorf("%w: closed_at must be set if and only if the account is closed", domain.ErrConstraintViolation)
+	default:
+		return nil
+	}
+}
+
+// keysetLess reports whether (createdAtA, idA) sorts before (createdAtB, idB),
+// comparing UUIDs bytewise as PostgreSQL does.
+func keysetLess(createdAtA time.Time, idA uuid.UUID, createdAtB time.Time, idB uuid.UUID) bool {
+	if !createdAtA.Equal(createdAtB) {
+		return createdAtA.Before(createdAtB)
+	}
+	return bytes.Compare(idA[:], idB[:]) < 0
+}
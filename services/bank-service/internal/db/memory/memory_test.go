@@ -0,0 +1,19 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db/dbtest"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db/memory"
+)
+
+func TestContract(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) dbtest.Repositories {
+		store := memory.NewStore()
+		return dbtest.Repositories{
+			Accounts:  memory.NewAccountRepository(store),
+			Transfers: memory.NewTransferRepository(store),
+			TxManager: memory.NewTransactionManager(store),
+		}
+	})
+}
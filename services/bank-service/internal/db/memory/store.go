@@ -0,0 +1,256 @@
+// Package memory implements the bank-service repositories and transaction manager in memory.
+//
+// It mirrors the PostgreSQL implementation closely enough for domain and gRPC tests
+// to run without a database: changes made within a transaction are visible only to it
+// until commit and are discarded on rollback, rows written or locked within a transaction
+// stay locked until it ends, and the same constraints are enforced with the same domain errors.
+// All transactions behave as READ COMMITTED; deadlocks are not detected, so a transaction
+// waiting for a lock only gives up when its context is cancelled.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// Store holds the committed data shared by the in-memory repositories.
+type Store struct {
+	mu           sync.Mutex
+	accounts     map[uuid.UUID]*domain.Account
+	transfers    map[uuid.UUID]*domain.Transfer
+	transferKeys map[string]uuid.UUID // Idempotency key index of transfers
+	locks        map[string]*rowLock
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{
+		accounts:     make(map[uuid.UUID]*domain.Account),
+		transfers:    make(map[uuid.UUID]*domain.Transfer),
+		transferKeys: make(map[string]uuid.UUID),
+		locks:        make(map[string]*rowLock),
+	}
+}
+
+// rowLock is an exclusive lock held by a transaction until it ends.
+type rowLock struct {
+	owner    *tx
+	released chan struct{}
+}
+
+// tx is an in-memory transaction: the changes it made and the locks it holds.
+type tx struct {
+	store     *Store
+	accounts  map[uuid.UUID]*domain.Account
+	transfers map[uuid.UUID]*domain.Transfer
+	locks     []string
+}
+
+// txKey is the key type for storing transaction in context.
+type txKey struct{}
+
+// getTx retrieves the transaction from context.
+// If no transaction is found, returns nil.
+func getTx(ctx context.Context) *tx {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok {
+		return t
+	}
+	return nil
+}
+
+// begin starts a new transaction.
+func (s *Store) begin() *tx {
+	return &tx{
+		store:     s,
+		accounts:  make(map[uuid.UUID]*domain.Account),
+		transfers: make(map[uuid.UUID]*domain.Transfer),
+	}
+}
+
+// inTx runs fn within the transaction from context, or within a transaction
+// of its own that is committed if fn succeeds, like a single statement outside a transaction.
+func (s *Store) inTx(ctx context.Context, fn func(t *tx) error) error {
+	if t := getTx(ctx); t != nil {
+		return fn(t)
+	}
+
+	t := s.begin()
+	if err := fn(t); err != nil {
+		t.rollback()
+		return err
+	}
+	t.commit()
+	return nil
+}
+
+// lock acquires the named lock for the transaction, waiting while another transaction holds it.
+func (t *tx) lock(ctx context.Context, name string) error {
+	s := t.store
+	for {
+		s.mu.Lock()
+		l, ok := s.locks[name]
+		if !ok {
+			s.locks[name] = &rowLock{owner: t, released: make(chan struct{})}
+			t.locks = append(t.locks, name)
+			s.mu.Unlock()
+			return nil
+		}
+		if l.owner == t {
+			s.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// commit applies the changes of the transaction to the store and releases its locks.
+func (t *tx) commit() {
+	s := t.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, account := range t.accounts {
+		s.accounts[id] = account
+	}
+	for id, transfer := range t.transfers {
+		s.transfers[id] = transfer
+		s.transferKeys[transfer.IdempotencyKey] = id
+	}
+	t.releaseLocked()
+}
+
+// rollback discards the changes of the transaction and releases its locks.
+func (t *tx) rollback() {
+	s := t.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.accounts = nil
+	t.transfers = nil
+	t.releaseLocked()
+}
+
+// releaseLocked releases the locks of the transaction. The store mutex must be held.
+func (t *tx) releaseLocked() {
+	for _, name := range t.locks {
+		if l := t.store.locks[name]; l != nil && l.owner == t {
+			close(l.released)
+			delete(t.store.locks, name)
+		}
+	}
+	t.locks = nil
+}
+
+// account returns the account as seen by the transaction, or nil if it doesn't exist.
+func (t *tx) account(id uuid.UUID) *domain.Account {
+	if account, ok := t.accounts[id]; ok {
+		return account
+	}
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	return t.store.accounts[id]
+}
+
+// transfer returns the transfer as seen by the transaction, or nil if it doesn't exist.
+func (t *tx) transfer(id uuid.UUID) *domain.Transfer {
+	if transfer, ok := t.transfers[id]; ok {
+		return transfer
+	}
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	return t.store.transfers[id]
+}
+
+// transferByKey returns the transfer with the idempotency key as seen by the transaction, or nil.
+func (t *tx) transferByKey(idempotencyKey string) *domain.Transfer {
+	for _, transfer := range t.transfers {
+		if transfer.IdempotencyKey == idempotencyKey {
+			return transfer
+		}
+	}
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	if id, ok := t.store.transferKeys[idempotencyKey]; ok {
+		return t.store.transfers[id]
+	}
+	return nil
+}
+
+// visibleAccounts returns all accounts as seen by the transaction.
+func (t *tx) visibleAccounts() []*domain.Account {
+	t.store.mu.Lock()
+	visible := make(map[uuid.UUID]*domain.Account, len(t.store.accounts))
+	for id, account := range t.store.accounts {
+		visible[id] = account
+	}
+	t.store.mu.Unlock()
+
+	for id, account := range t.accounts {
+		visible[id] = account
+	}
+
+	accounts := make([]*domain.Account, 0, len(visible))
+	for _, account := range visible {
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+// visibleTransfers returns all transfers as seen by the transaction.
+func (t *tx) visibleTransfers() []*domain.Transfer {
+	t.store.mu.Lock()
+	visible := make(map[uuid.UUID]*domain.Transfer, len(t.store.transfers))
+	for id, transfer := range t.store.transfers {
+		visible[id] = transfer
+	}
+	t.store.mu.Unlock()
+
+	for id, transfer := range t.transfers {
+		visible[id] = transfer
+	}
+
+	transfers := make([]*domain.Transfer, 0, len(visible))
+	for _, transfer := range visible {
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
+
+// copyTime returns a copy of the optional timestamp with the precision PostgreSQL stores.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := t.Truncate(time.Microsecond)
+	return &copied
+}
+
+// copyAccount returns a copy of the account with the precision PostgreSQL stores,
+// so callers never share state with the store.
+func copyAccount(account *domain.Account) *domain.Account {
+	copied := *account
+	copied.CreatedAt = account.CreatedAt.Truncate(time.Microsecond)
+	copied.UpdatedAt = account.UpdatedAt.Truncate(time.Microsecond)
+	copied.ClosedAt = copyTime(account.ClosedAt)
+	return &copied
+}
+
+// copyTransfer returns a copy of the transfer with the precision PostgreSQL stores,
+// so callers never share state with the store.
+func copyTransfer(transfer *domain.Transfer) *domain.Transfer {
+	copied := *transfer
+	copied.CreatedAt = transfer.CreatedAt.Truncate(time.Microsecond)
+	copied.CompletedAt = copyTime(transfer.CompletedAt)
+	return &copied
+}
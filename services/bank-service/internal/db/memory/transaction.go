@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// TransactionManager implements domain.TransactionManager in memory.
+type TransactionManager struct {
+	store *Store
+}
+
+// NewTransactionManager creates a new TransactionManager.
+func NewTransactionManager(store *Store) *TransactionManager {
+	return &TransactionManager{
+		store: store,
+	}
+}
+
+// WithTransaction executes the given function within a transaction.
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+// The isolation level option is accepted, but every transaction behaves as READ COMMITTED.
+func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...domain.TxOption) error {
+	t := tm.store.begin()
+
+	// Store transaction in context so repositories can use it
+	txCtx := context.WithValue(ctx, txKey{}, t)
+
+	if err := fn(txCtx); err != nil {
+		t.rollback()
+		return err
+	}
+
+	t.commit()
+	return nil
+}
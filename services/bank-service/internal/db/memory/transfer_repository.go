@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// TransferRepository implements domain.TransferRepository in memory.
+type TransferRepository struct {
+	store *Store
+}
+
+// NewTransferRepository creates a new TransferRepository.
+func NewTransferRepository(store *Store) *TransferRepository {
+	return &TransferRepository{
+		store: store,
+	}
+}
+
+// transferLock returns the name of the row lock of the transfer.
+func transferLock(id uuid.UUID) string {
+	return "transfer:" + id.String()
+}
+
+// idempotencyKeyLock returns the name of the lock of the transfer idempotency key.
+// Creating a transfer takes it too, as inserting into the unique index does in PostgreSQL.
+func idempotencyKeyLock(idempotencyKey string) string {
+	return "transfer-idempotency-key:" + idempotencyKey
+}
+
+// Create persists a new transfer record.
+func (r *TransferRepository) Create(ctx context.Context, transfer *domain.Transfer) error {
+	return r.store.inTx(ctx, func(t *tx) error {
+		if err := t.lock(ctx, idempotencyKeyLock(transfer.IdempotencyKey)); err != nil {
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+		if err := t.lock(ctx, transferLock(transfer.ID)); err != nil {
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+
+		if t.transferByKey(transfer.IdempotencyKey) != nil {
+			return fmt.Errorf("failed to create transfer: %w", domain.ErrDuplicateIdempotencyKey)
+		}
+		if t.transfer(transfer.ID) != nil {
+			return fmt.Errorf("failed to create transfer: transfer %s already exists", transfer.ID)
+		}
+		if t.account(transfer.SenderID) == nil || t.account(transfer.RecipientID) == nil {
+			return fmt.Errorf("failed to create transfer: %w", domain.ErrAccountNotFound)
+		}
+		if err := checkTransfer(transfer); err != nil {
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+
+		t.transfers[transfer.ID] = copyTransfer(transfer)
+		return nil
+	})
+}
+
+// LockIdempotencyKey acquires a lock on the idempotency key for the duration of the transaction.
+// This method MUST be called within a transaction context.
+func (r *TransferRepository) LockIdempotencyKey(ctx context.Context, idempotencyKey string) error {
+	t := getTx(ctx)
+	if t == nil {
+		return fmt.Errorf("failed to lock idempotency key: no transaction in context")
+	}
+
+	if err := t.lock(ctx, idempotencyKeyLock(idempotencyKey)); err != nil {
+		return fmt.Errorf("failed to lock idempotency key: %w", err)
+	}
+	return nil
+}
+
+// GetByIdempotencyKey retrieves a transfer by its idempotency key.
+// Returns nil if no transfer is found with the given key.
+func (r *TransferRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.Transfer, error) {
+	var transfer *domain.Transfer
+	err := r.store.inTx(ctx, func(t *tx) error {
+		transfer = t.transferByKey(idempotencyKey)
+		return nil
+	})
+	if err != nil || transfer == nil {
+		return nil, err
+	}
+	return copyTransfer(transfer), nil
+}
+
+// GetByID retrieves a transfer by its unique identifier.
+func (r *TransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Transfer, error) {
+	var transfer *domain.Transfer
+	err := r.store.inTx(ctx, func(t *tx) error {
+		transfer = t.transfer(id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer == nil {
+		return nil, domain.ErrTransferNotFound
+	}
+	return copyTransfer(transfer), nil
+}
+
+// Update persists changes to an existing transfer.
+// Only the status, failure reason, message and completion time are updated.
+func (r *TransferRepository) Update(ctx context.Context, transfer *domain.Transfer) error {
+	return r.store.inTx(ctx, func(t *tx) error {
+		if err := t.lock(ctx, transferLock(transfer.ID)); err != nil {
+			return fmt.Errorf("failed to update transfer: %w", err)
+		}
+
+		existing := t.transfer(transfer.ID)
+		if existing == nil {
+			return domain.ErrTransferNotFound
+		}
+
+		updated := copyTransfer(existing)
+		updated.Status = transfer.Status
+		updated.FailureReason = transfer.FailureReason
+		updated.Message = transfer.Message
+		updated.CompletedAt = copyTime(transfer.CompletedAt)
+		if err := checkTransfer(updated); err != nil {
+			return fmt.Errorf("failed to update transfer: %w", err)
+		}
+
+		t.transfers[transfer.ID] = updated
+		return nil
+	})
+}
+
+// List retrieves transfers sent or received by the filter account ordered by (created_at, id), most recent first.
+// The cursor in the filter selects the transfers before the given one.
+func (r *TransferRepository) List(ctx context.Context, filter domain.TransferFilter) ([]*domain.Transfer, error) {
+	var visible []*domain.Transfer
+	err := r.store.inTx(ctx, func(t *tx) error {
+		visible = t.visibleTransfers()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(visible, func(i, j int) bool {
+		return keysetLess(visible[j].CreatedAt, visible[j].ID, visible[i].CreatedAt, visible[i].ID)
+	})
+
+	var transfers []*domain.Transfer
+	for _, transfer := range visible {
+		if len(transfers) >= filter.Limit {
+			break
+		}
+		if transfer.SenderID != filter.AccountID && transfer.RecipientID != filter.AccountID {
+			continue
+		}
+		if filter.Status != "" && transfer.Status != filter.Status {
+			continue
+		}
+		if filter.BeforeID != uuid.Nil && !keysetLess(transfer.CreatedAt, transfer.ID, filter.BeforeCreatedAt, filter.BeforeID) {
+			continue
+		}
+		transfers = append(transfers, copyTransfer(transfer))
+	}
+
+	return transfers, nil
+}
+
+// checkTransfer enforces the constraints of the transfers table.
+func checkTransfer(transfer *domain.Transfer) error {
+	switch {
+	case !transfer.Amount.Value.IsPositive():
+		return fmt.Errorf("%w: amount must be positive", domain.ErrConstraintViolation)
+	case len(transfer.Amount.CurrencyCode) != 3:
+		return fmt.Errorf("%w: currency code must have 3 letters", domain.ErrConstraintViolation)
+	case transfer.SenderID == transfer.RecipientID:
+		return fmt.Errorf("%w: sender and recipient must be different", domain.ErrConstraintViolation)
+	case transfer.Status != domain.TransferStatusPending &&
+		transfer.Status != domain.TransferStatusSuccess &&
+		transfer.Status != domain.TransferStatusFailed:
+		return fmt.Errorf("%w: unknown transfer status %q", domain.ErrConstraintViolation, transfer.Status)
+	case (transfer.Status == domain.TransferStatusFailed) != (transfer.FailureReason != ""):
+		return fmt.Errorf("%w: failure reason must be set if and only if the transfer failed", domain.ErrConstraintViolation)
+	default:
+		return nil
+	}
+}
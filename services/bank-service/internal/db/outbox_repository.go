@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// OutboxRepository implements domain.OutboxRepository using PostgreSQL.
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOutboxRepository creates a new OutboxRepository.
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{
+		pool: pool,
+	}
+}
+
+// Create persists a new outbox message and sets its generated ID.
+func (r *OutboxRepository) Create(ctx context.Context, message *domain.OutboxMessage) error {
+	query := `
+		INSERT INTO outbox (
+			event_type, aggregate_id, account_ids,
+			attempts, created_at, next_attempt_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	// Use transaction if available, otherwise use pool
+	var row pgx.Row
+	if tx := getTx(ctx); tx != nil {
+		row = tx.QueryRow(ctx, query,
+			string(message.EventType),
+			message.AggregateID,
+			message.AccountIDs,
+			message.Attempts,
+			message.CreatedAt,
+			message.NextAttemptAt,
+		)
+	} else {
+		row = r.pool.QueryRow(ctx, query,
+			string(message.EventType),
+			message.AggregateID,
+			message.AccountIDs,
+			message.Attempts,
+			message.CreatedAt,
+			message.NextAttemptAt,
+		)
+	}
+
+	if err := row.Scan(&message.ID); err != nil {
+		return fmt.Errorf("failed to create outbox message: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// claimLockKey names the advisory lock serializing outbox claims within lockClassOutboxClaim
+const claimLockKey = "outbox"
+
+// ClaimPending claims due undispatched messages in publication order for the duration of the lease.
+// This method MUST be called within a transaction context.
+//
+// Claims are serialized by a transaction-scoped advisory lock, so each relay sees the claims
+// committed by the others. A message is left out if an earlier undispatched message sharing
+// one of its accounts is waiting for a retry or claimed by another relay, which keeps events
+// ordered per account; messages of other accounts are not held up.
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxMessage, error) {
+	tx := getTx(ctx)
+	if tx == nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: no transaction in context")
+	}
+
+	if _, err := tx.Exec(ctx, advisoryXactLockQuery, lockClassOutboxClaim, claimLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock outbox claims: %w", translateError(err))
+	}
+
+	query := `
+		WITH claimable AS (
+			SELECT o.id
+			FROM outbox o
+			WHERE o.dispatched_at IS NULL
+			  AND o.next_attempt_at <= now()
+			  AND (o.claimed_until IS NULL OR o.claimed_until <= now())
+			  AND NOT EXISTS (
+			      SELECT 1
+			      FROM outbox earlier
+			      WHERE earlier.dispatched_at IS NULL
+			        AND earlier.id < o.id
+			        AND earlier.account_ids && o.account_ids
+			        AND (earlier.next_attempt_at > now() OR earlier.claimed_until > now())
+			  )
+			ORDER BY o.id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox
+		SET claimed_until = now() + $2 * interval '1 millisecond'
+		FROM claimable
+		WHERE outbox.id = claimable.id
+		RETURNING outbox.id, outbox.event_type, outbox.aggregate_id, outbox.account_ids,
+		          outbox.attempts, COALESCE(outbox.last_error, ''),
+		          outbox.created_at, outbox.next_attempt_at, outbox.dispatched_at, outbox.claimed_until
+	`
+
+	rows, err := tx.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending outbox messages: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var messages []*domain.OutboxMessage
+	for rows.Next() {
+		var message domain.OutboxMessage
+		var eventType string
+
+		err := rows.Scan(
+			&message.ID,
+			&eventType,
+			&message.AggregateID,
+			&message.AccountIDs,
+			&message.Attempts,
+			&message.LastError,
+			&message.CreatedAt,
+			&message.NextAttemptAt,
+			&message.DispatchedAt,
+			&message.ClaimedUntil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+
+		message.EventType = domain.OutboxEventType(eventType)
+		messages = append(messages, &message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox messages: %w", err)
+	}
+
+	// UPDATE ... RETURNING does not keep the order of the claimed rows
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+
+	return messages, nil
+}
+
+// Update persists changes to an existing outbox message.
+func (r *OutboxRepository) Update(ctx context.Context, message *domain.OutboxMessage) error {
+	query := `
+		UPDATE outbox
+		SET attempts = $2,
+		    last_error = NULLIF($3, ''),
+		    next_attempt_at = $4,
+		    dispatched_at = $5,
+		    claimed_until = $6
+		WHERE id = $1
+	`
+
+	var err error
+	var rowsAffected int64
+
+	// Use transaction if available, otherwise use pool
+	if tx := getTx(ctx); tx != nil {
+		result, execErr := tx.Exec(ctx, query,
+			message.ID,
+			message.Attempts,
+			message.LastError,
+			message.NextAttemptAt,
+			message.DispatchedAt,
+			message.ClaimedUntil,
+		)
+		err = execErr
+		rowsAffected = result.RowsAffected()
+	} else {
+		result, execErr := r.pool.Exec(ctx, query,
+			message.ID,
+			message.Attempts,
+			message.LastError,
+			message.NextAttemptAt,
+			message.DispatchedAt,
+			message.ClaimedUntil,
+		)
+		err = execErr
+		rowsAffected = result.RowsAffected()
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("outbox message not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db/dbtest"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/migrate"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/migrations"
+)
+
+// TestContract runs the repository contract test suite against PostgreSQL.
+func TestContract(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	container, dbURL := startPostgresContainer(t, ctx)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres container: %v", err)
+		}
+	}()
+
+	pool, err := db.NewPool(ctx, dbURL)
+	if err != nil {
+		t.Fatalf("failed to create database pool: %v", err)
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool.Pool, migrations.FS)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	dbtest.Run(t, func(t *testing.T) dbtest.Repositories {
+		// Every test starts with empty tables, including the seed data
+		if _, err := pool.Exec(ctx, "TRUNCATE accounts, transfers, topups, outbox CASCADE"); err != nil {
+			t.Fatalf("failed to truncate tables: %v", err)
+		}
+
+		return dbtest.Repositories{
+			Accounts:  db.NewAccountRepository(pool.Pool),
+			Transfers: db.NewTransferRepository(pool.Pool),
+			TxManager: db.NewTransactionManager(pool.Pool),
+		}
+	})
+}
+
+// startPostgresContainer starts a PostgreSQL testcontainer and returns the connection URL.
+func startPostgresContainer(t *testing.T, ctx context.Context) (testcontainers.Container, string) {
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:15",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     "testuser",
+			"POSTGRES_PASSWORD": "testpass",
+			"POSTGRES_DB":       "testdb",
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get postgres host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "5432")
+	if err != nil {
+		t.Fatalf("failed to get postgres port: %v", err)
+	}
+
+	dbURL := fmt.Sprintf("postgres://testuser:testpass@%s:%s/testdb?sslmode=disable", host, port.Port())
+	return container, dbURL
+}
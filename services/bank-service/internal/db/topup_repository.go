@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// TopUpRepository implements domain.TopUpRepository using PostgreSQL.
+type TopUpRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTopUpRepository creates a new TopUpRepository.
+func NewTopUpRepository(pool *pgxpool.Pool) *TopUpRepository {
+	return &TopUpRepository{
+		pool: pool,
+	}
+}
+
+// Create persists a new top-up record.
+func (r *TopUpRepository) Create(ctx context.Context, topUp *domain.TopUp) error {
+	query := `
+		INSERT INTO topups (
+			id, account_id,
+			amount_value, amount_currency_code,
+			idempotency_key, source, external_transaction_id,
+			status, message, new_balance_value,
+			created_at, completed_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11, $12)
+	`
+
+	var err error
+
+	// Use transaction if available, otherwise use pool
+	if tx := getTx(ctx); tx != nil {
+		_, err = tx.Exec(ctx, query,
+			topUp.ID,
+			topUp.AccountID,
+			topUp.Amount.Value.String(),
+			topUp.Amount.CurrencyCode,
+			topUp.IdempotencyKey,
+			topUp.Source,
+			topUp.ExternalTransactionID,
+			string(topUp.Status),
+			topUp.Message,
+			topUp.NewBalance.Value.String(),
+			topUp.CreatedAt,
+			topUp.CompletedAt,
+		)
+	} else {
+		_, err = r.pool.Exec(ctx, query,
+			topUp.ID,
+			topUp.AccountID,
+			topUp.Amount.Value.String(),
+			topUp.Amount.CurrencyCode,
+			topUp.IdempotencyKey,
+			topUp.Source,
+			topUp.ExternalTransactionID,
+			string(topUp.Status),
+			topUp.Message,
+			topUp.NewBalance.Value.String(),
+			topUp.CreatedAt,
+			topUp.CompletedAt,
+		)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to create top-up: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// LockIdempotencyKey acquires a transaction-scoped advisory lock on the idempotency key.
+// This method MUST be called within a transaction context.
+// Concurrent top-ups with the same key wait until the holder commits or rolls back,
+// so the later one finds the top-up recorded by the first.
+// The lock has its own class, so that it does not share a lock with a transfer using the same key.
+func (r *TopUpRepository) LockIdempotencyKey(ctx context.Context, idempotencyKey string) error {
+	tx := getTx(ctx)
+	if tx == nil {
+		return fmt.Errorf("failed to lock idempotency key: no transaction in context")
+	}
+
+	if _, err := tx.Exec(ctx, advisoryXactLockQuery, lockClassTopUpKey, idempotencyKey); err != nil {
+		return fmt.Errorf("failed to lock idempotency key: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// GetByIdempotencyKey retrieves a top-up by its idempotency key.
+func (r *TopUpRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.TopUp, error) {
+	query := `
+		SELECT id, account_id,
+		       amount_value, amount_currency_code,
+		       idempotency_key, COALESCE(source, ''), COALESCE(external_transaction_id, ''),
+		       status, message, new_balance_value,
+		       created_at, completed_at
+		FROM topups
+		WHERE idempotency_key = $1
+	`
+
+	// Use transaction if available, otherwise use pool
+	var row pgx.Row
+	if tx := getTx(ctx); tx != nil {
+		row = tx.QueryRow(ctx, query, idempotencyKey)
+	} else {
+		row = r.pool.QueryRow(ctx, query, idempotencyKey)
+	}
+
+	topUp, err := scanTopUp(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // No top-up found with this idempotency key
+		}
+		return nil, fmt.Errorf("failed to get top-up by idempotency key: %w", err)
+	}
+
+	return topUp, nil
+}
+
+// GetByID retrieves a top-up by its unique identifier.
+func (r *TopUpRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.TopUp, error) {
+	query := `
+		SELECT id, account_id,
+		       amount_value, amount_currency_code,
+		       idempotency_key, COALESCE(source, ''), COALESCE(external_transaction_id, ''),
+		       status, message, new_balance_value,
+		       created_at, completed_at
+		FROM topups
+		WHERE id = $1
+	`
+
+	// Use transaction if available, otherwise use pool
+	var row pgx.Row
+	if tx := getTx(ctx); tx != nil {
+		row = tx.QueryRow(ctx, query, id)
+	} else {
+		row = r.pool.QueryRow(ctx, query, id)
+	}
+
+	topUp, err := scanTopUp(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("top-up not found")
+		}
+		return nil, fmt.Errorf("failed to get top-up by ID: %w", err)
+	}
+
+	return topUp, nil
+}
+
+// scanTopUp scans a topups row selected in the column order used by this repository.
+func scanTopUp(row pgx.Row) (*domain.TopUp, error) {
+	var topUp domain.TopUp
+	var amount, newBalance string
+	var status string
+
+	err := row.Scan(
+		&topUp.ID,
+		&topUp.AccountID,
+		&amount,
+		&topUp.Amount.CurrencyCode,
+		&topUp.IdempotencyKey,
+		&topUp.Source,
+		&topUp.ExternalTransactionID,
+		&status,
+		&topUp.Message,
+		&newBalance,
+		&topUp.CreatedAt,
+		&topUp.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if topUp.Amount.Value, err = domain.ParseMoney(amount); err != nil {
+		return nil, fmt.Errorf("failed to parse top-up amount: %w", err)
+	}
+	if topUp.NewBalance.Value, err = domain.ParseMoney(newBalance); err != nil {
+		return nil, fmt.Errorf("failed to parse top-up balance: %w", err)
+	}
+
+	topUp.Status = domain.TopUpStatus(status)
+	topUp.NewBalance.CurrencyCode = topUp.Amount.CurrencyCode
+	return &topUp, nil
+}
@@ -2,24 +2,68 @@ package db
 
 import (
 	"context"
+	"expvar"
 	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
 )
 
 // txKey is the key type for storing transaction in context.
 type txKey struct{}
 
+// TransactionConfig holds the settings of the transaction manager.
+type TransactionConfig struct {
+	IsolationLevel domain.IsolationLevel // Isolation level used unless a call overrides it
+	MaxAttempts    int                   // Maximum number of attempts of a transaction, including the first one
+	RetryBackoff   time.Duration         // Delay before the first retry of an aborted transaction
+	MaxBackoff     time.Duration         // Upper bound for the exponential retry delay
+}
+
+// DefaultTransactionConfig returns the default transaction manager settings.
+func DefaultTransactionConfig() TransactionConfig {
+	return TransactionConfig{
+		IsolationLevel: domain.IsolationReadCommitted,
+		MaxAttempts:    5,
+		RetryBackoff:   10 * time.Millisecond,
+		MaxBackoff:     500 * time.Millisecond,
+	}
+}
+
+// TransactionStats holds the transaction manager counters.
+type TransactionStats struct {
+	Transactions     uint64 `json:"transactions"`      // WithTransaction calls
+	Retries          uint64 `json:"retries"`           // Attempts run again after a serialization failure or deadlock
+	RetriesExhausted uint64 `json:"retries_exhausted"` // Calls that failed because every attempt was aborted
+}
+
 // TransactionManager implements domain.TransactionManager using PostgreSQL.
+// Transactions aborted by a serialization failure or a deadlock are retried
+// with exponential backoff and jitter, up to the configured number of attempts.
 type TransactionManager struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	config TransactionConfig
+
+	transactions     atomic.Uint64
+	retries          atomic.Uint64
+	retriesExhausted atomic.Uint64
 }
 
-// NewTransactionManager creates a new TransactionManager.
+// NewTransactionManager creates a new TransactionManager with the default settings.
 func NewTransactionManager(pool *pgxpool.Pool) *TransactionManager {
+	return NewTransactionManagerWithConfig(pool, DefaultTransactionConfig())
+}
+
+// NewTransactionManagerWithConfig creates a new TransactionManager with the given settings.
+func NewTransactionManagerWithConfig(pool *pgxpool.Pool, config TransactionConfig) *TransactionManager {
 	return &TransactionManager{
-		pool: pool,
+		pool:   pool,
+		config: config,
 	}
 }
 
@@ -27,9 +71,73 @@ func NewTransactionManager(pool *pgxpool.Pool) *TransactionManager {
 // If the function returns an error, the transaction is rolled back.
 // Otherwise, the transaction is committed.
 // The transaction is stored in the context and can be retrieved using getTx.
-func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+// If the transaction is aborted by a serialization failure or a deadlock,
+// the function is executed again in a new transaction.
+func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...domain.TxOption) error {
+	options := domain.TxOptions{IsolationLevel: tm.config.IsolationLevel}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	isoLevel, err := pgIsolationLevel(options.IsolationLevel)
+	if err != nil {
+		return err
+	}
+
+	tm.transactions.Add(1)
+	return tm.withRetry(ctx, func() error {
+		return tm.runTransaction(ctx, pgx.TxOptions{IsoLevel: isoLevel}, fn)
+	})
+}
+
+// Stats returns the transaction manager counters.
+func (tm *TransactionManager) Stats() TransactionStats {
+	return TransactionStats{
+		Transactions:     tm.transactions.Load(),
+		Retries:          tm.retries.Load(),
+		RetriesExhausted: tm.retriesExhausted.Load(),
+	}
+}
+
+// Metrics returns the transaction manager counters as an expvar variable,
+// which renders the current Stats as JSON, e.g. to publish them on a metrics endpoint.
+func (tm *TransactionManager) Metrics() expvar.Var {
+	return expvar.Func(func() any {
+		return tm.Stats()
+	})
+}
+
+// withRetry runs attempt until it succeeds, fails with a non-retryable error,
+// the attempts are exhausted or the context is cancelled.
+func (tm *TransactionManager) withRetry(ctx context.Context, attempt func() error) error {
+	for n := 1; ; n++ {
+		err := attempt()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+
+		if n >= tm.config.MaxAttempts {
+			tm.retriesExhausted.Add(1)
+			return err
+		}
+
+		delay := retryDelay(n, tm.config.RetryBackoff, tm.config.MaxBackoff)
+		log.Printf("transaction aborted by a concurrent update (attempt %d of %d), retrying in %s: %v",
+			n, tm.config.MaxAttempts, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		tm.retries.Add(1)
+	}
+}
+
+// runTransaction executes the function within a single database transaction.
+func (tm *TransactionManager) runTransaction(ctx context.Context, txOptions pgx.TxOptions, fn func(ctx context.Context) error) error {
 	// Begin transaction
-	tx, err := tm.pool.Begin(ctx)
+	tx, err := tm.pool.BeginTx(ctx, txOptions)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -51,14 +159,46 @@ func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx c
 		return err // Transaction will be rolled back by defer
 	}
 
-	// Commit transaction
+	// Commit transaction; serialization failures may only be detected at commit
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
 	}
 
 	return nil
 }
 
+// retryDelay returns the delay before the given retry: exponential backoff capped at maxBackoff,
+// of which the upper half is random, so that conflicting transactions do not retry in lockstep.
+func retryDelay(attempt int, backoff, maxBackoff time.Duration) time.Duration {
+	delay := backoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// pgIsolationLevel maps a domain isolation level to the pgx one.
+func pgIsolationLevel(level domain.IsolationLevel) (pgx.TxIsoLevel, error) {
+	switch level {
+	case domain.IsolationReadCommitted:
+		return pgx.ReadCommitted, nil
+	case domain.IsolationRepeatableRead:
+		return pgx.RepeatableRead, nil
+	case domain.IsolationSerializable:
+		return pgx.Serializable, nil
+	default:
+		return "", fmt.Errorf("unsupported isolation level: %q", level)
+	}
+}
+
 // getTx retrieves the transaction from context.
 // If no transaction is found, returns nil.
 func getTx(ctx context.Context) pgx.Tx {
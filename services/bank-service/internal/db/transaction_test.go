@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+func TestWithRetry(t *testing.T) {
+	serializationFailure := fmt.Errorf("failed to commit transaction: %w", &pgconn.PgError{Code: pgSerializationFailure})
+	deadlock := fmt.Errorf("failed to lock account: %w", &pgconn.PgError{Code: pgDeadlockDetected})
+	permanent := errors.New("insufficient funds")
+
+	tests := []struct {
+		name             string
+		errs             []error // Errors returned by consecutive attempts; nil once exhausted
+		expectedAttempts int
+		expectedErr      error
+		expectedStats    TransactionStats
+	}{
+		{"success at once", nil, 1, nil, TransactionStats{}},
+		{"retried until success", []error{serializationFailure, deadlock}, 3, nil, TransactionStats{Retries: 2}},
+		{"non-retryable error", []error{permanent}, 1, permanent, TransactionStats{}},
+		{"attempts exhausted", []error{deadlock, deadlock, deadlock, deadlock}, 3, domain.ErrSerializationFailure, TransactionStats{Retries: 2, RetriesExhausted: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TransactionManager{config: TransactionConfig{
+				MaxAttempts:  3,
+				RetryBackoff: time.Millisecond,
+				MaxBackoff:   2 * time.Millisecond,
+			}}
+
+			attempts := 0
+			err := tm.withRetry(context.Background(), func() error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+
+			if attempts != tt.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.expectedAttempts, attempts)
+			}
+			if tt.expectedErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.expectedErr != nil && !errors.Is(translateError(err), tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+			if stats := tm.Stats(); stats != tt.expectedStats {
+				t.Errorf("expected stats %+v, got %+v", tt.expectedStats, stats)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tm := &TransactionManager{}
+	tm.transactions.Add(3)
+	tm.retries.Add(2)
+	tm.retriesExhausted.Add(1)
+
+	expected := `{"transactions":3,"retries":2,"retries_exhausted":1}`
+	if metrics := tm.Metrics().String(); metrics != expected {
+		t.Errorf("expected metrics %s, got %s", expected, metrics)
+	}
+}
+
+func TestWithRetry_ContextCancelled(t *testing.T) {
+	tm := &TransactionManager{config: TransactionConfig{
+		MaxAttempts:  5,
+		RetryBackoff: time.Hour,
+		MaxBackoff:   time.Hour,
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := tm.withRetry(ctx, func() error {
+		attempts++
+		return &pgconn.PgError{Code: pgSerializationFailure}
+	})
+
+	if attempts != 1 || err == nil {
+		t.Errorf("expected a single failed attempt, got %d attempts and error %v", attempts, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	backoff := 10 * time.Millisecond
+	maxBackoff := 50 * time.Millisecond
+
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{1, 10 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{3, 40 * time.Millisecond},
+		{4, 50 * time.Millisecond},
+		{10, 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			delay := retryDelay(tt.attempt, backoff, maxBackoff)
+			if delay < tt.ceiling/2 || delay > tt.ceiling {
+				t.Fatalf("attempt %d: expected delay in [%s, %s], got %s", tt.attempt, tt.ceiling/2, tt.ceiling, delay)
+			}
+		}
+	}
+}
+
+func TestPgIsolationLevel(t *testing.T) {
+	for _, level := range []domain.IsolationLevel{
+		domain.IsolationReadCommitted,
+		domain.IsolationRepeatableRead,
+		domain.IsolationSerializable,
+	} {
+		if _, err := pgIsolationLevel(level); err != nil {
+			t.Errorf("unexpected error for %s: %v", level, err)
+		}
+	}
+
+	if _, err := pgIsolationLevel("READ UNCOMMITTED"); err == nil {
+		t.Error("expected error for unsupported isolation level")
+	}
+}
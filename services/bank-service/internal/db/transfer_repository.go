@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,15 +24,23 @@ func NewTransferRepository(pool *pgxpool.Pool) *TransferRepository {
 	}
 }
 
+// transferColumns is the column list scanned by scanTransfer.
+const transferColumns = `
+	id, sender_id, recipient_id,
+	amount_value, amount_currency_code,
+	idempotency_key, status, COALESCE(failure_reason, ''), message,
+	created_at, completed_at
+`
+
 // Create persists a new transfer record.
 func (r *TransferRepository) Create(ctx context.Context, transfer *domain.Transfer) error {
 	query := `
 		INSERT INTO transfers (
 			id, sender_id, recipient_id,
 			amount_value, amount_currency_code,
-			idempotency_key, status, message,
+			idempotency_key, status, failure_reason, message,
 			created_at, completed_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11)
 	`
 
 	var err error
@@ -42,10 +51,11 @@ func (r *TransferRepository) Create(ctx context.Context, transfer *domain.Transf
 			transfer.ID,
 			transfer.SenderID,
 			transfer.RecipientID,
-			transfer.Amount.Value,
+			transfer.Amount.Value.String(),
 			transfer.Amount.CurrencyCode,
 			transfer.IdempotencyKey,
 			string(transfer.Status),
+			string(transfer.FailureReason),
 			transfer.Message,
 			transfer.CreatedAt,
 			transfer.CompletedAt,
@@ -55,10 +65,11 @@ func (r *TransferRepository) Create(ctx context.Context, transfer *domain.Transf
 			transfer.ID,
 			transfer.SenderID,
 			transfer.RecipientID,
-			transfer.Amount.Value,
+			transfer.Amount.Value.String(),
 			transfer.Amount.CurrencyCode,
 			transfer.IdempotencyKey,
 			string(transfer.Status),
+			string(transfer.FailureReason),
 			transfer.Message,
 			transfer.CreatedAt,
 			transfer.CompletedAt,
@@ -66,11 +77,24 @@ func (r *TransferRepository) Create(ctx context.Context, transfer *domain.Transf
 	}
 
 	if err != nil {
-		// Check for unique constraint violation on idempotency_key
-		if isPgUniqueViolation(err) {
-			return fmt.Errorf("transfer with idempotency key already exists: %w", err)
-		}
-		return fmt.Errorf("failed to create transfer: %w", err)
+		return fmt.Errorf("failed to create transfer: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// LockIdempotencyKey acquires a transaction-scoped advisory lock on the idempotency key.
+// This method MUST be called within a transaction context.
+// Concurrent transfers with the same key wait until the holder commits or rolls back,
+// so the later one finds the transfer recorded by the first.
+func (r *TransferRepository) LockIdempotencyKey(ctx context.Context, idempotencyKey string) error {
+	tx := getTx(ctx)
+	if tx == nil {
+		return fmt.Errorf("failed to lock idempotency key: no transaction in context")
+	}
+
+	if _, err := tx.Exec(ctx, advisoryXactLockQuery, lockClassTransferKey, idempotencyKey); err != nil {
+		return fmt.Errorf("failed to lock idempotency key: %w", translateError(err))
 	}
 
 	return nil
@@ -78,17 +102,7 @@ func (r *TransferRepository) Create(ctx context.Context, transfer *domain.Transf
 
 // GetByIdempotencyKey retrieves a transfer by its idempotency key.
 func (r *TransferRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.Transfer, error) {
-	query := `
-		SELECT id, sender_id, recipient_id,
-		       amount_value, amount_currency_code,
-		       idempotency_key, status, message,
-		       created_at, completed_at
-		FROM transfers
-		WHERE idempotency_key = $1
-	`
-
-	var transfer domain.Transfer
-	var status string
+	query := `SELECT ` + transferColumns + ` FROM transfers WHERE idempotency_key = $1`
 
 	// Use transaction if available, otherwise use pool
 	var row pgx.Row
@@ -98,19 +112,7 @@ func (r *TransferRepository) GetByIdempotencyKey(ctx context.Context, idempotenc
 		row = r.pool.QueryRow(ctx, query, idempotencyKey)
 	}
 
-	err := row.Scan(
-		&transfer.ID,
-		&transfer.SenderID,
-		&transfer.RecipientID,
-		&transfer.Amount.Value,
-		&transfer.Amount.CurrencyCode,
-		&transfer.IdempotencyKey,
-		&status,
-		&transfer.Message,
-		&transfer.CreatedAt,
-		&transfer.CompletedAt,
-	)
-
+	transfer, err := scanTransfer(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // No transfer found with this idempotency key
@@ -118,23 +120,12 @@ func (r *TransferRepository) GetByIdempotencyKey(ctx context.Context, idempotenc
 		return nil, fmt.Errorf("failed to get transfer by idempotency key: %w", err)
 	}
 
-	transfer.Status = domain.TransferStatus(status)
-	return &transfer, nil
+	return transfer, nil
 }
 
 // GetByID retrieves a transfer by its unique identifier.
 func (r *TransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Transfer, error) {
-	query := `
-		SELECT id, sender_id, recipient_id,
-		       amount_value, amount_currency_code,
-		       idempotency_key, status, message,
-		       created_at, completed_at
-		FROM transfers
-		WHERE id = $1
-	`
-
-	var transfer domain.Transfer
-	var status string
+	query := `SELECT ` + transferColumns + ` FROM transfers WHERE id = $1`
 
 	// Use transaction if available, otherwise use pool
 	var row pgx.Row
@@ -144,28 +135,63 @@ func (r *TransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 		row = r.pool.QueryRow(ctx, query, id)
 	}
 
-	err := row.Scan(
-		&transfer.ID,
-		&transfer.SenderID,
-		&transfer.RecipientID,
-		&transfer.Amount.Value,
-		&transfer.Amount.CurrencyCode,
-		&transfer.IdempotencyKey,
-		&status,
-		&transfer.Message,
-		&transfer.CreatedAt,
-		&transfer.CompletedAt,
-	)
-
+	transfer, err := scanTransfer(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("transfer not found")
+			return nil, domain.ErrTransferNotFound
 		}
 		return nil, fmt.Errorf("failed to get transfer by ID: %w", err)
 	}
 
-	transfer.Status = domain.TransferStatus(status)
-	return &transfer, nil
+	return transfer, nil
+}
+
+// List retrieves transfers sent or received by the filter account ordered by (created_at, id), most recent first.
+// Uses keyset pagination: the cursor in the filter selects the transfers before the given one.
+func (r *TransferRepository) List(ctx context.Context, filter domain.TransferFilter) ([]*domain.Transfer, error) {
+	args := []interface{}{filter.AccountID}
+	conditions := []string{"(sender_id = $1 OR recipient_id = $1)"}
+
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.BeforeID != uuid.Nil {
+		args = append(args, filter.BeforeCreatedAt, filter.BeforeID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := `SELECT ` + transferColumns + ` FROM transfers WHERE ` + strings.Join(conditions, " AND ")
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	// Use transaction if available, otherwise use pool
+	var rows pgx.Rows
+	var err error
+	if tx := getTx(ctx); tx != nil {
+		rows, err = tx.Query(ctx, query, args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []*domain.Transfer
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transfers: %w", err)
+	}
+
+	return transfers, nil
 }
 
 // Update persists changes to an existing transfer.
@@ -173,8 +199,9 @@ func (r *TransferRepository) Update(ctx context.Context, transfer *domain.Transf
 	query := `
 		UPDATE transfers
 		SET status = $2,
-		    message = $3,
-		    completed_at = $4
+		    failure_reason = NULLIF($3, ''),
+		    message = $4,
+		    completed_at = $5
 		WHERE id = $1
 	`
 
@@ -186,6 +213,7 @@ func (r *TransferRepository) Update(ctx context.Context, transfer *domain.Transf
 		result, execErr := tx.Exec(ctx, query,
 			transfer.ID,
 			string(transfer.Status),
+			string(transfer.FailureReason),
 			transfer.Message,
 			transfer.CompletedAt,
 		)
@@ -195,6 +223,7 @@ func (r *TransferRepository) Update(ctx context.Context, transfer *domain.Transf
 		result, execErr := r.pool.Exec(ctx, query,
 			transfer.ID,
 			string(transfer.Status),
+			string(transfer.FailureReason),
 			transfer.Message,
 			transfer.CompletedAt,
 		)
@@ -203,29 +232,45 @@ func (r *TransferRepository) Update(ctx context.Context, transfer *domain.Transf
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to update transfer: %w", err)
+		return fmt.Errorf("failed to update transfer: %w", translateError(err))
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("transfer not found")
+		return domain.ErrTransferNotFound
 	}
 
 	return nil
 }
 
-// isPgUniqueViolation checks if the error is a PostgreSQL unique constraint violation.
-// PostgreSQL error code 23505 indicates unique_violation.
-func isPgUniqueViolation(err error) bool {
-	if err == nil {
-		return false
-	}
-	// pgx wraps errors, so we need to check the error message
-	return !errors.Is(err, pgx.ErrTxClosed) &&
-		!errors.Is(err, context.Canceled) &&
-		containsString(err.Error(), "unique")
-} // containsString checks if a string contains a substring (case-insensitive).
-func containsString(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr || len(substr) == 0 ||
-			len(s) > 0 && (s[0:len(substr)] == substr || containsString(s[1:], substr)))
+// scanTransfer scans a transfers row selected with transferColumns.
+func scanTransfer(row pgx.Row) (*domain.Transfer, error) {
+	var transfer domain.Transfer
+	var amount string
+	var status string
+	var failureReason string
+
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.SenderID,
+		&transfer.RecipientID,
+		&amount,
+		&transfer.Amount.CurrencyCode,
+		&transfer.IdempotencyKey,
+		&status,
+		&failureReason,
+		&transfer.Message,
+		&transfer.CreatedAt,
+		&transfer.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Amount.Value, err = domain.ParseMoney(amount); err != nil {
+		return nil, fmt.Errorf("failed to parse transfer amount: %w", err)
+	}
+
+	transfer.Status = domain.TransferStatus(status)
+	transfer.FailureReason = domain.TransferFailureReason(failureReason)
+	return &transfer, nil
 }
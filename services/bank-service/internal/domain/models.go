@@ -9,33 +9,88 @@ import (
 // Account represents a bank account in the system.
 // This is the core domain entity that holds account information and balance.
 type Account struct {
-	ID        uuid.UUID // Unique identifier of the account
-	Balance   Amount    // Current account balance
-	CreatedAt time.Time // Timestamp when the account was created
-	UpdatedAt time.Time // Timestamp of the last account update
+	ID        uuid.UUID     // Unique identifier of the account
+	OwnerID   string        // Identifier of the account owner (empty for legacy accounts)
+	OwnerName string        // Display name of the account owner (optional)
+	Balance   Amount        // Current account balance
+	Status    AccountStatus // Lifecycle status of the account
+	CreatedAt time.Time     // Timestamp when the account was created
+	UpdatedAt time.Time     // Timestamp of the last account update
+	ClosedAt  *time.Time    // Timestamp when the account was closed (nullable)
 }
 
 // Transfer represents a money transfer operation between two accounts.
 // This entity captures the complete transfer transaction details.
 type Transfer struct {
-	ID             uuid.UUID      // Unique identifier of the transfer operation
-	SenderID       uuid.UUID      // Account ID of the sender (debited)
-	RecipientID    uuid.UUID      // Account ID of the recipient (credited)
-	Amount         Amount         // Amount transferred
-	IdempotencyKey string         // Unique key to ensure idempotent operations
-	Status         TransferStatus // Current status of the transfer
-	Message        string         // Human-readable message about the transfer
-	CreatedAt      time.Time      // Timestamp when the transfer was initiated
-	CompletedAt    *time.Time     // Timestamp when the transfer was completed (nullable)
+	ID             uuid.UUID             // Unique identifier of the transfer operation
+	SenderID       uuid.UUID             // Account ID of the sender (debited)
+	RecipientID    uuid.UUID             // Account ID of the recipient (credited)
+	Amount         Amount                // Amount transferred
+	IdempotencyKey string                // Unique key to ensure idempotent operations
+	Status         TransferStatus        // Current status of the transfer
+	FailureReason  TransferFailureReason // Machine-readable reason of the failure (empty unless FAILED)
+	Message        string                // Human-readable message about the transfer
+	CreatedAt      time.Time             // Timestamp when the transfer was initiated
+	CompletedAt    *time.Time            // Timestamp when the transfer was completed (nullable)
+}
+
+// TopUp represents an operation that adds funds to an account from an external source
+// (e.g. a bank card payment processed by the BankCardAdapter service).
+type TopUp struct {
+	ID                    uuid.UUID   // Unique identifier of the top-up operation
+	AccountID             uuid.UUID   // Account ID being credited
+	Amount                Amount      // Amount added to the account
+	IdempotencyKey        string      // Unique key to ensure idempotent operations
+	Source                string      // Source of the funds (e.g., "bank_card", "external_transfer")
+	ExternalTransactionID string      // Reference to the payment gateway transaction (optional)
+	Status                TopUpStatus // Current status of the top-up
+	Message               string      // Human-readable message about the top-up
+	NewBalance            Amount      // Account balance right after the top-up
+	CreatedAt             time.Time   // Timestamp when the top-up was initiated
+	CompletedAt           *time.Time  // Timestamp when the top-up was completed (nullable)
 }
 
 // Amount represents a monetary value with currency.
-// Uses string for value to preserve decimal precision and avoid floating point errors.
+// Uses the fixed-point Money type for value to preserve decimal precision and avoid floating point errors.
 type Amount struct {
-	Value        string // Decimal string with up to 2 decimal places (e.g., "100.00")
+	Value        Money  // Exact value with 2 decimal places (e.g., 100.00)
 	CurrencyCode string // ISO 4217 currency code (e.g., "RUB")
 }
 
+// AccountStatus represents the lifecycle states of an account.
+type AccountStatus string
+
+const (
+	// AccountStatusActive indicates the account can send and receive funds
+	AccountStatusActive AccountStatus = "ACTIVE"
+
+	// AccountStatusFrozen indicates the account is temporarily blocked
+	AccountStatusFrozen AccountStatus = "FROZEN"
+
+	// AccountStatusClosed indicates the account is permanently closed
+	AccountStatusClosed AccountStatus = "CLOSED"
+)
+
+// AccountFilter narrows down and paginates account listings.
+// Accounts are ordered by (CreatedAt, ID); the cursor fields select the accounts after the given one.
+type AccountFilter struct {
+	OwnerID        string        // Only accounts of this owner (empty for all owners)
+	Status         AccountStatus // Only accounts in this status (empty for any status)
+	AfterCreatedAt time.Time     // Creation time of the last account of the previous page
+	AfterID        uuid.UUID     // ID of the last account of the previous page (uuid.Nil for the first page)
+	Limit          int           // Maximum number of accounts to return
+}
+
+// TransferFilter narrows down and paginates the transfer history of an account.
+// Transfers are ordered by (CreatedAt, ID) descending; the cursor fields select the transfers before the given one.
+type TransferFilter struct {
+	AccountID       uuid.UUID      // Only transfers sent or received by this account
+	Status          TransferStatus // Only transfers in this status (empty for any status)
+	BeforeCreatedAt time.Time      // Creation time of the last transfer of the previous page
+	BeforeID        uuid.UUID      // ID of the last transfer of the previous page (uuid.Nil for the first page)
+	Limit           int            // Maximum number of transfers to return
+}
+
 // TransferStatus represents the possible states of a transfer operation.
 type TransferStatus string
 
@@ -50,17 +105,81 @@ const (
 	TransferStatusFailed TransferStatus = "FAILED"
 )
 
-// NewAccount creates a new Account with the given ID and initial balance.
+// TransferFailureReason represents the reasons a transfer can fail for.
+type TransferFailureReason string
+
+const (
+	// TransferFailureInsufficientFunds indicates the sender's balance is lower than the amount
+	TransferFailureInsufficientFunds TransferFailureReason = "INSUFFICIENT_FUNDS"
+
+	// TransferFailureAccountNotActive indicates the sender or recipient account is frozen or closed
+	TransferFailureAccountNotActive TransferFailureReason = "ACCOUNT_NOT_ACTIVE"
+
+	// TransferFailureCurrencyMismatch indicates an account currency differs from the transfer currency
+	TransferFailureCurrencyMismatch TransferFailureReason = "CURRENCY_MISMATCH"
+
+	// TransferFailureInternal indicates the transfer failed because of an internal error
+	TransferFailureInternal TransferFailureReason = "INTERNAL"
+)
+
+// TopUpStatus represents the possible states of a top-up operation.
+type TopUpStatus string
+
+const (
+	// TopUpStatusPending indicates the top-up is being processed
+	TopUpStatusPending TopUpStatus = "PENDING"
+
+	// TopUpStatusSuccess indicates the top-up completed successfully
+	TopUpStatusSuccess TopUpStatus = "SUCCESS"
+)
+
+// OutboxMessage represents a domain event recorded in the transactional outbox.
+// It is written in the same transaction as the business change and later
+// published by the OutboxRelay.
+type OutboxMessage struct {
+	ID            int64           // Monotonic identifier defining the publication order
+	EventType     OutboxEventType // Type of the event
+	AggregateID   uuid.UUID       // Identifier of the operation the event is about (e.g. transfer ID)
+	AccountIDs    []uuid.UUID     // Accounts affected by the event, used for per-account ordering
+	Attempts      int             // Number of failed publication attempts
+	LastError     string          // Error of the last failed publication attempt
+	CreatedAt     time.Time       // Timestamp when the event was recorded
+	NextAttemptAt time.Time       // Earliest time of the next publication attempt
+	DispatchedAt  *time.Time      // Timestamp when the event was published (nullable)
+	ClaimedUntil  *time.Time      // End of the lease of the relay publishing the event (nullable)
+}
+
+// OutboxEventType represents the type of event stored in the outbox.
+type OutboxEventType string
+
+const (
+	// OutboxEventTransferCompleted is recorded when a transfer completes successfully
+	OutboxEventTransferCompleted OutboxEventType = "transfer.completed"
+
+	// OutboxEventTopUpCompleted is recorded when a top-up completes successfully
+	OutboxEventTopUpCompleted OutboxEventType = "topup.completed"
+)
+
+// NewAccount creates a new ACTIVE Account with the given ID and initial balance.
 func NewAccount(id uuid.UUID, balance Amount) *Account {
 	now := time.Now()
 	return &Account{
 		ID:        id,
 		Balance:   balance,
+		Status:    AccountStatusActive,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
 
+// OpenAccount creates a new ACTIVE Account for the given owner with a zero balance.
+func OpenAccount(ownerID, ownerName, currencyCode string) *Account {
+	account := NewAccount(uuid.New(), Amount{CurrencyCode: currencyCode})
+	account.OwnerID = ownerID
+	account.OwnerName = ownerName
+	return account
+}
+
 // NewTransfer creates a new Transfer with the given parameters.
 // The transfer is created in PENDING status.
 func NewTransfer(senderID, recipientID uuid.UUID, amount Amount, idempotencyKey string) *Transfer {
@@ -76,6 +195,15 @@ func NewTransfer(senderID, recipientID uuid.UUID, amount Amount, idempotencyKey
 	}
 }
 
+// MatchesRequest reports whether the transfer was created for the same sender,
+// recipient and amount, i.e. whether a request reusing its idempotency key is a replay.
+func (t *Transfer) MatchesRequest(senderID, recipientID uuid.UUID, amount Amount) bool {
+	return t.SenderID == senderID &&
+		t.RecipientID == recipientID &&
+		t.Amount.CurrencyCode == amount.CurrencyCode &&
+		t.Amount.Value.Cmp(amount.Value) == 0
+}
+
 // MarkAsSuccess marks the transfer as successfully completed.
 func (t *Transfer) MarkAsSuccess(message string) {
 	now := time.Now()
@@ -84,22 +212,59 @@ func (t *Transfer) MarkAsSuccess(message string) {
 	t.CompletedAt = &now
 }
 
-// MarkAsFailed marks the transfer as failed.
-func (t *Transfer) MarkAsFailed(message string) {
+// MarkAsFailed marks the transfer as failed for the given reason.
+func (t *Transfer) MarkAsFailed(reason TransferFailureReason, message string) {
 	now := time.Now()
 	t.Status = TransferStatusFailed
+	t.FailureReason = reason
 	t.Message = message
 	t.CompletedAt = &now
 }
 
+// NewTopUp creates a new TopUp with the given parameters.
+// The top-up is created in PENDING status.
+func NewTopUp(accountID uuid.UUID, amount Amount, idempotencyKey, source, externalTransactionID string) *TopUp {
+	now := time.Now()
+	return &TopUp{
+		ID:                    uuid.New(),
+		AccountID:             accountID,
+		Amount:                amount,
+		IdempotencyKey:        idempotencyKey,
+		Source:                source,
+		ExternalTransactionID: externalTransactionID,
+		Status:                TopUpStatusPending,
+		CreatedAt:             now,
+	}
+}
+
+// MatchesRequest reports whether the top-up was created for the same account and amount,
+// i.e. whether a request reusing its idempotency key is a replay.
+func (t *TopUp) MatchesRequest(accountID uuid.UUID, amount Amount) bool {
+	return t.AccountID == accountID &&
+		t.Amount.CurrencyCode == amount.CurrencyCode &&
+		t.Amount.Value.Cmp(amount.Value) == 0
+}
+
+// MarkAsSuccess marks the top-up as successfully completed and records the resulting balance.
+func (t *TopUp) MarkAsSuccess(message string, newBalance Amount) {
+	now := time.Now()
+	t.Status = TopUpStatusSuccess
+	t.Message = message
+	t.NewBalance = newBalance
+	t.CompletedAt = &now
+}
+
 // Debit subtracts the given amount from the account balance.
-// Returns an error if the account has insufficient funds.
+// Returns an error if the amount is not positive or the account has insufficient funds.
 func (a *Account) Debit(amount Amount) error {
-	if err := ValidateAmount(amount.Value); err != nil {
-		return err
+	if !amount.Value.IsPositive() {
+		return ErrInvalidAmount
+	}
+	if !a.HasSufficientFunds(amount) {
+		return ErrInsufficientFunds
 	}
 
-	newBalance, err := SubtractAmounts(a.Balance.Value, amount.Value)
+	newBalance, err := a.Balance.Value.Sub(amount.Value)
 	if err != nil {
 		return err
 	}
@@ -110,12 +275,13 @@ func (a *Account) Debit(amount Amount) error {
 }
 
 // Credit adds the given amount to the account balance.
+// Returns an error if the amount is not positive or the balance would overflow.
 func (a *Account) Credit(amount Amount) error {
-	if err := ValidateAmount(amount.Value); err != nil {
-		return err
+	if !amount.Value.IsPositive() {
+		return ErrInvalidAmount
 	}
 
-	newBalance, err := AddAmounts(a.Balance.Value, amount.Value)
+	newBalance, err := a.Balance.Value.Add(amount.Value)
 	if err != nil {
 		return err
 	}
@@ -127,9 +293,71 @@ func (a *Account) Credit(amount Amount) error {
 
 // HasSufficientFunds checks if the account has enough balance for the given amount.
 func (a *Account) HasSufficientFunds(amount Amount) bool {
-	cmp, err := CompareAmounts(a.Balance.Value, amount.Value)
-	if err != nil {
-		return false
+	return a.Balance.Value.Cmp(amount.Value) >= 0
+}
+
+// IsActive reports whether the account can send and receive funds.
+func (a *Account) IsActive() bool {
+	return a.Status == AccountStatusActive
+}
+
+// Close closes the account permanently.
+// Returns an error if the account is already closed or still holds funds.
+func (a *Account) Close() error {
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	if !a.Balance.Value.IsZero() {
+		return ErrAccountHasBalance
 	}
-	return cmp >= 0
+
+	now := time.Now()
+	a.Status = AccountStatusClosed
+	a.ClosedAt = &now
+	a.UpdatedAt = now
+	return nil
+}
+
+// NewTransferCompletedMessage creates an outbox message for a successfully completed transfer.
+func NewTransferCompletedMessage(transfer *Transfer) *OutboxMessage {
+	now := time.Now()
+	return &OutboxMessage{
+		EventType:     OutboxEventTransferCompleted,
+		AggregateID:   transfer.ID,
+		AccountIDs:    []uuid.UUID{transfer.SenderID, transfer.RecipientID},
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+}
+
+// NewTopUpCompletedMessage creates an outbox message for a successfully completed top-up.
+func NewTopUpCompletedMessage(topUp *TopUp) *OutboxMessage {
+	now := time.Now()
+	return &OutboxMessage{
+		EventType:     OutboxEventTopUpCompleted,
+		AggregateID:   topUp.ID,
+		AccountIDs:    []uuid.UUID{topUp.AccountID},
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+}
+
+// MarkAsDispatched marks the outbox message as published and releases its claim.
+func (m *OutboxMessage) MarkAsDispatched() {
+	now := time.Now()
+	m.DispatchedAt = &now
+	m.ClaimedUntil = nil
+}
+
+// MarkAsFailed records a failed publication attempt, schedules the next one and releases the claim.
+func (m *OutboxMessage) MarkAsFailed(reason string, nextAttemptAt time.Time) {
+	m.Attempts++
+	m.LastError = reason
+	m.NextAttemptAt = nextAttemptAt
+	m.ClaimedUntil = nil
+}
+
+// Release releases the claim of a message that was not attempted, so that a later batch publishes it.
+func (m *OutboxMessage) Release() {
+	m.ClaimedUntil = nil
 }
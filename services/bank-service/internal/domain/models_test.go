@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAccount_Close(t *testing.T) {
+	account := OpenAccount("owner-1", "Test Owner", "RUB")
+	if !account.IsActive() {
+		t.Fatalf("expected new account to be ACTIVE, got %s", account.Status)
+	}
+
+	// Accounts holding funds cannot be closed
+	account.Balance.Value = NewMoney(1)
+	if err := account.Close(); !errors.Is(err, ErrAccountHasBalance) {
+		t.Errorf("expected ErrAccountHasBalance, got %v", err)
+	}
+	if account.Status != AccountStatusActive {
+		t.Errorf("status changed after rejected close: %s", account.Status)
+	}
+
+	account.Balance.Value = NewMoney(0)
+	if err := account.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.Status != AccountStatusClosed || account.ClosedAt == nil || account.IsActive() {
+		t.Errorf("expected CLOSED account with closed_at, got %+v", account)
+	}
+
+	if err := account.Close(); !errors.Is(err, ErrAccountClosed) {
+		t.Errorf("expected ErrAccountClosed, got %v", err)
+	}
+}
+
+func TestAccount_FrozenIsNotActive(t *testing.T) {
+	account := OpenAccount("owner-1", "", "RUB")
+	account.Status = AccountStatusFrozen
+
+	if account.IsActive() {
+		t.Error("expected FROZEN account not to be active")
+	}
+
+	// Frozen accounts can still be closed once empty
+	if err := account.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTransfer_MarkAsFailed(t *testing.T) {
+	transfer := NewTransfer(uuid.New(), uuid.New(), Amount{Value: NewMoney(100), CurrencyCode: "RUB"}, "key-1")
+	if transfer.Status != TransferStatusPending || transfer.FailureReason != "" {
+		t.Fatalf("expected PENDING transfer without failure reason, got %s %q", transfer.Status, transfer.FailureReason)
+	}
+
+	transfer.MarkAsFailed(TransferFailureInsufficientFunds, "Insufficient funds")
+
+	if transfer.Status != TransferStatusFailed || transfer.CompletedAt == nil {
+		t.Errorf("expected FAILED transfer with completed_at, got %+v", transfer)
+	}
+	if transfer.FailureReason != TransferFailureInsufficientFunds {
+		t.Errorf("expected INSUFFICIENT_FUNDS, got %q", transfer.FailureReason)
+	}
+}
+
+func TestTransfer_MatchesRequest(t *testing.T) {
+	senderID, recipientID := uuid.New(), uuid.New()
+	amount := Amount{Value: NewMoney(10050), CurrencyCode: "RUB"}
+	transfer := NewTransfer(senderID, recipientID, amount, "key-1")
+
+	tests := []struct {
+		name        string
+		senderID    uuid.UUID
+		recipientID uuid.UUID
+		amount      Amount
+		expected    bool
+	}{
+		{"same request", senderID, recipientID, Amount{Value: NewMoney(10050), CurrencyCode: "RUB"}, true},
+		{"different sender", uuid.New(), recipientID, amount, false},
+		{"different recipient", senderID, uuid.New(), amount, false},
+		{"different amount", senderID, recipientID, Amount{Value: NewMoney(10000), CurrencyCode: "RUB"}, false},
+		{"different currency", senderID, recipientID, Amount{Value: NewMoney(10050), CurrencyCode: "USD"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transfer.MatchesRequest(tt.senderID, tt.recipientID, tt.amount); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTopUp_MatchesRequest(t *testing.T) {
+	accountID := uuid.New()
+	amount := Amount{Value: NewMoney(5000), CurrencyCode: "RUB"}
+	topUp := NewTopUp(accountID, amount, "key-1", "bank_card", "ext-1")
+
+	tests := []struct {
+		name      string
+		accountID uuid.UUID
+		amount    Amount
+		expected  bool
+	}{
+		{"same request", accountID, Amount{Value: NewMoney(5000), CurrencyCode: "RUB"}, true},
+		{"different account", uuid.New(), amount, false},
+		{"different amount", accountID, Amount{Value: NewMoney(7500), CurrencyCode: "RUB"}, false},
+		{"different currency", accountID, Amount{Value: NewMoney(5000), CurrencyCode: "USD"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := topUp.MatchesRequest(tt.accountID, tt.amount); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// minorUnitsPerMajor is the number of minor units (e.g. kopecks) in one major unit (e.g. ruble).
+// All supported currencies use 2 decimal places.
+const minorUnitsPerMajor = 100
+
+// maxMinorUnits is the largest absolute value in minor units that fits into the NUMERIC(15,2)
+// columns storing amounts and balances, i.e. 9999999999999.99.
+const maxMinorUnits = 999_999_999_999_999
+
+var (
+	// ErrInvalidMoney is returned when a string is not a valid monetary value
+	ErrInvalidMoney = errors.New("invalid money format: must be a decimal with up to 2 decimal places")
+
+	// ErrMoneyOverflow is returned when a monetary value or the result of an operation
+	// does not fit into the supported range of ±9999999999999.99
+	ErrMoneyOverflow = errors.New("money overflow")
+)
+
+// Money is an exact fixed-point monetary value stored as an integer number of minor units.
+// It replaces floating point arithmetic for balances and amounts, so that values are
+// never rounded. The zero value is 0.00.
+//
+// Parsing and arithmetic keep values within ±9999999999999.99, the range of the NUMERIC(15,2)
+// columns, so that a value the database cannot store is rejected before it is written.
+type Money struct {
+	minor int64 // Number of minor units (e.g., 10050 for "100.50")
+}
+
+// NewMoney creates Money from a number of minor units (e.g., NewMoney(10050) is "100.50").
+func NewMoney(minorUnits int64) Money {
+	return Money{minor: minorUnits}
+}
+
+// ParseMoney parses a decimal string with up to 2 decimal places (e.g., "100", "100.5", "-0.01").
+// Returns ErrInvalidMoney for malformed input and ErrMoneyOverflow if the value is out of range.
+func ParseMoney(value string) (Money, error) {
+	s := value
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	}
+
+	integer, fraction, hasPoint := strings.Cut(s, ".")
+	if integer == "" || !isDigits(integer) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+	if hasPoint && (len(fraction) == 0 || len(fraction) > 2 || !isDigits(fraction)) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	major, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyOverflow, value)
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+
+	if major > (maxMinorUnits-cents)/minorUnitsPerMajor {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyOverflow, value)
+	}
+	minor := major*minorUnitsPerMajor + cents
+	if negative {
+		minor = -minor
+	}
+
+	return Money{minor: minor}, nil
+}
+
+// MinorUnits returns the value as a number of minor units.
+func (m Money) MinorUnits() int64 {
+	return m.minor
+}
+
+// Add returns m + other. Returns ErrMoneyOverflow if the result is out of range.
+func (m Money) Add(other Money) (Money, error) {
+	if (other.minor > 0 && m.minor > math.MaxInt64-other.minor) ||
+		(other.minor < 0 && m.minor < math.MinInt64-other.minor) ||
+		!inRange(m.minor+other.minor) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrMoneyOverflow, m, other)
+	}
+	return Money{minor: m.minor + other.minor}, nil
+}
+
+// Sub returns m - other. Returns ErrMoneyOverflow if the result is out of range.
+func (m Money) Sub(other Money) (Money, error) {
+	if (other.minor < 0 && m.minor > math.MaxInt64+other.minor) ||
+		(other.minor > 0 && m.minor < math.MinInt64+other.minor) ||
+		!inRange(m.minor-other.minor) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrMoneyOverflow, m, other)
+	}
+	return Money{minor: m.minor - other.minor}, nil
+}
+
+// inRange reports whether the number of minor units fits into the supported range.
+func inRange(minor int64) bool {
+	return minor >= -maxMinorUnits && minor <= maxMinorUnits
+}
+
+// Cmp compares m and other.
+// Returns:
+//   - negative if m < other
+//   - zero if m == other
+//   - positive if m > other
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.minor < other.minor:
+		return -1
+	case m.minor > other.minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsPositive reports whether the value is greater than zero.
+func (m Money) IsPositive() bool {
+	return m.minor > 0
+}
+
+// IsZero reports whether the value is zero.
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+// IsNegative reports whether the value is less than zero.
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// String formats the value with exactly 2 decimal places (e.g., "100.50", "-0.01").
+func (m Money) String() string {
+	// Work with uint64 so that math.MinInt64 can be negated
+	abs := uint64(m.minor)
+	sign := ""
+	if m.minor < 0 {
+		abs = -abs
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/minorUnitsPerMajor, abs%minorUnitsPerMajor)
+}
+
+// isDigits reports whether s consists of ASCII digits only.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
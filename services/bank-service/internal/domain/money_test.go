@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"regexp"
+	"testing"
+	"testing/quick"
+)
+
+// moneyPattern is the amount value pattern used by the event schemas
+var moneyPattern = regexp.MustCompile(`^-?[0-9]+\.[0-9]{2}$`)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0", 0},
+		{"100", 10000},
+		{"100.5", 10050},
+		{"100.50", 10050},
+		{"0.01", 1},
+		{"-0.01", -1},
+		{"007.10", 710},
+		{"9999999999999.99", maxMinorUnits},
+		{"-9999999999999.99", -maxMinorUnits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m, err := ParseMoney(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if m.MinorUnits() != tt.expected {
+				t.Errorf("expected %d minor units, got %d", tt.expected, m.MinorUnits())
+			}
+		})
+	}
+}
+
+func TestParseMoney_Invalid(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedErr error
+	}{
+		{"", ErrInvalidMoney},
+		{"-", ErrInvalidMoney},
+		{"abc", ErrInvalidMoney},
+		{"1.", ErrInvalidMoney},
+		{".5", ErrInvalidMoney},
+		{"1.234", ErrInvalidMoney},
+		{"1,50", ErrInvalidMoney},
+		{"+1.00", ErrInvalidMoney},
+		{"1e3", ErrInvalidMoney},
+		{" 1.00", ErrInvalidMoney},
+		{"--1", ErrInvalidMoney},
+		{"1.-5", ErrInvalidMoney},
+		{"10000000000000.00", ErrMoneyOverflow},
+		{"-10000000000000", ErrMoneyOverflow},
+		{"92233720368547758.08", ErrMoneyOverflow},
+		{"100000000000000000000", ErrMoneyOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseMoney(tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		expected string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{-1, "-0.01"},
+		{10050, "100.50"},
+		{-10050, "-100.50"},
+		{math.MaxInt64, "92233720368547758.07"},
+		{math.MinInt64, "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := NewMoney(tt.minor).String(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// Property tests below compare Money against math/big as the reference implementation.
+
+func TestMoney_StringParseRoundTrip(t *testing.T) {
+	property := func(minor int64) bool {
+		// Only values within the supported range are parseable
+		minor %= maxMinorUnits + 1
+		s := NewMoney(minor).String()
+		if !moneyPattern.MatchString(s) {
+			t.Logf("%s does not match the schema pattern", s)
+			return false
+		}
+		parsed, err := ParseMoney(s)
+		return err == nil && parsed.MinorUnits() == minor
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoney_AddMatchesBigInt(t *testing.T) {
+	property := func(a, b int64) bool {
+		// Operands up to twice the supported range, so that some results are out of range
+		a, b = a%(2*maxMinorUnits), b%(2*maxMinorUnits)
+		expected := new(big.Int).Add(big.NewInt(a), big.NewInt(b))
+		sum, err := NewMoney(a).Add(NewMoney(b))
+		if expected.CmpAbs(big.NewInt(maxMinorUnits)) > 0 {
+			return errors.Is(err, ErrMoneyOverflow)
+		}
+		return err == nil && sum.MinorUnits() == expected.Int64()
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoney_SubMatchesBigInt(t *testing.T) {
+	property := func(a, b int64) bool {
+		// Operands up to twice the supported range, so that some results are out of range
+		a, b = a%(2*maxMinorUnits), b%(2*maxMinorUnits)
+		expected := new(big.Int).Sub(big.NewInt(a), big.NewInt(b))
+		diff, err := NewMoney(a).Sub(NewMoney(b))
+		if expected.CmpAbs(big.NewInt(maxMinorUnits)) > 0 {
+			return errors.Is(err, ErrMoneyOverflow)
+		}
+		return err == nil && diff.MinorUnits() == expected.Int64()
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoney_AddSubInverse(t *testing.T) {
+	// Keep operands small enough for their sum to stay within the supported range
+	const limit = maxMinorUnits / 2
+
+	property := func(a, b int64) bool {
+		x, y := NewMoney(a%limit), NewMoney(b%limit)
+
+		sum, err := x.Add(y)
+		if err != nil {
+			return false
+		}
+		back, err := sum.Sub(y)
+		if err != nil || back != x {
+			return false
+		}
+
+		commuted, err := y.Add(x)
+		return err == nil && commuted == sum
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoney_CmpMatchesBigInt(t *testing.T) {
+	property := func(a, b int64) bool {
+		x, y := NewMoney(a), NewMoney(b)
+		expected := big.NewInt(a).Cmp(big.NewInt(b))
+		return x.Cmp(y) == expected &&
+			y.Cmp(x) == -expected &&
+			x.IsPositive() == (a > 0) &&
+			x.IsNegative() == (a < 0)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoney_Overflow(t *testing.T) {
+	max := NewMoney(maxMinorUnits)
+	min := NewMoney(-maxMinorUnits)
+	cent := NewMoney(1)
+
+	if _, err := max.Add(cent); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("expected overflow on 9999999999999.99 + 0.01, got %v", err)
+	}
+	if _, err := min.Sub(cent); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("expected overflow on -9999999999999.99 - 0.01, got %v", err)
+	}
+	if _, err := NewMoney(math.MaxInt64).Add(cent); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("expected overflow on MaxInt64 + 0.01, got %v", err)
+	}
+	if _, err := NewMoney(0).Sub(NewMoney(math.MinInt64)); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("expected overflow on 0 - MinInt64, got %v", err)
+	}
+	if got, err := max.Sub(cent); err != nil || got.MinorUnits() != maxMinorUnits-1 {
+		t.Errorf("expected 9999999999999.98, got %v (%v)", got, err)
+	}
+}
+
+func TestAccount_DebitCredit(t *testing.T) {
+	account := &Account{Balance: Amount{Value: NewMoney(10000), CurrencyCode: "RUB"}}
+
+	if err := account.Debit(Amount{Value: NewMoney(3333), CurrencyCode: "RUB"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := account.Credit(Amount{Value: NewMoney(1), CurrencyCode: "RUB"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := account.Balance.Value.String(); got != "66.68" {
+		t.Errorf("expected balance 66.68, got %s", got)
+	}
+
+	if err := account.Debit(Amount{Value: NewMoney(6669), CurrencyCode: "RUB"}); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("expected ErrInsufficientFunds, got %v", err)
+	}
+	if err := account.Credit(Amount{Value: NewMoney(0), CurrencyCode: "RUB"}); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount, got %v", err)
+	}
+	if got := account.Balance.Value.String(); got != "66.68" {
+		t.Errorf("balance changed after rejected operations: %s", got)
+	}
+}
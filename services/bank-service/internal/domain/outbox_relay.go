@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRelayConfig holds the settings of the outbox relay worker.
+type OutboxRelayConfig struct {
+	PollInterval time.Duration // Delay between polls when the outbox is drained
+	BatchSize    int           // Maximum number of messages processed per transaction
+	RetryBackoff time.Duration // Delay before the first retry of a failed message
+	MaxBackoff   time.Duration // Upper bound for the exponential retry delay
+	ClaimLease   time.Duration // Time a claimed batch is reserved for the relay before others may claim it
+}
+
+// DefaultOutboxRelayConfig returns the default outbox relay settings.
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		PollInterval: 1 * time.Second,
+		BatchSize:    100,
+		RetryBackoff: 1 * time.Second,
+		MaxBackoff:   5 * time.Minute,
+		ClaimLease:   5 * time.Minute,
+	}
+}
+
+// OutboxRelay drains the transactional outbox to the EventPublisher.
+//
+// Messages are processed in outbox order. If a message fails to publish, it is
+// retried with exponential backoff, and later messages touching any of the same
+// accounts are held back until it succeeds, so events are delivered in order per account.
+// Several relays may run concurrently; each publishes the batches it has claimed.
+// Delivery is at-least-once: a crash between publishing and committing the
+// dispatch mark results in the event being published again once the claim expires.
+type OutboxRelay struct {
+	outboxRepo   OutboxRepository
+	transferRepo TransferRepository
+	topUpRepo    TopUpRepository
+	txManager    TransactionManager
+	publisher    EventPublisher
+	config       OutboxRelayConfig
+}
+
+// NewOutboxRelay creates a new instance of OutboxRelay.
+func NewOutboxRelay(
+	outboxRepo OutboxRepository,
+	transferRepo TransferRepository,
+	topUpRepo TopUpRepository,
+	txManager TransactionManager,
+	publisher EventPublisher,
+	config OutboxRelayConfig,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo:   outboxRepo,
+		transferRepo: transferRepo,
+		topUpRepo:    topUpRepo,
+		txManager:    txManager,
+		publisher:    publisher,
+		config:       config,
+	}
+}
+
+// Run drains the outbox until the context is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	log.Printf("outbox relay started (poll interval %s, batch size %d)", r.config.PollInterval, r.config.BatchSize)
+
+	for {
+		dispatched, err := r.DispatchBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: failed to dispatch batch: %v", err)
+		}
+
+		// Continue immediately while a full batch went through, otherwise wait for new events
+		if err == nil && dispatched == r.config.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("outbox relay stopped")
+			return
+		case <-time.After(r.config.PollInterval):
+		}
+	}
+}
+
+// DispatchBatch claims a batch of due outbox messages, publishes them and records the outcome.
+// Claiming and recording run in short transactions and publishing happens in between,
+// so no row locks are held while waiting for broker confirmations.
+// Returns the number of messages successfully published.
+func (r *OutboxRelay) DispatchBatch(ctx context.Context) (int, error) {
+	var messages []*OutboxMessage
+	err := r.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		messages, err = r.outboxRepo.ClaimPending(txCtx, r.config.BatchSize, r.config.ClaimLease)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim pending outbox messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	// Stop publishing well before the claim expires, so that no other relay publishes the same messages meanwhile
+	deadline := time.Now().Add(r.config.ClaimLease / 2)
+	dispatched := 0
+	blocked := make(map[uuid.UUID]bool)
+
+	for _, message := range messages {
+		// Preserve per-account ordering: skip messages queued behind a failed one
+		if touchesAny(message.AccountIDs, blocked) || ctx.Err() != nil || time.Now().After(deadline) {
+			block(message.AccountIDs, blocked)
+			message.Release()
+			continue
+		}
+
+		if err := r.dispatch(ctx, message); err != nil {
+			block(message.AccountIDs, blocked)
+			if ctx.Err() != nil {
+				// Interrupted by shutdown rather than failed
+				message.Release()
+				continue
+			}
+			log.Printf("outbox relay: failed to publish message %d (attempt %d): %v",
+				message.ID, message.Attempts+1, err)
+			message.MarkAsFailed(err.Error(), time.Now().Add(r.backoff(message.Attempts)))
+		} else {
+			message.MarkAsDispatched()
+			dispatched++
+		}
+	}
+
+	// Record the outcome even when shutting down, so that published events are not published again
+	err = r.txManager.WithTransaction(context.WithoutCancel(ctx), func(txCtx context.Context) error {
+		for _, message := range messages {
+			if err := r.outboxRepo.Update(txCtx, message); err != nil {
+				return fmt.Errorf("failed to update outbox message %d: %w", message.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return dispatched, nil
+}
+
+// dispatch publishes a single outbox message.
+func (r *OutboxRelay) dispatch(ctx context.Context, message *OutboxMessage) error {
+	switch message.EventType {
+	case OutboxEventTransferCompleted:
+		transfer, err := r.transferRepo.GetByID(ctx, message.AggregateID)
+		if err != nil {
+			return fmt.Errorf("failed to load transfer %s: %w", message.AggregateID, err)
+		}
+		return r.publisher.PublishTransferCompleted(ctx, transfer)
+	case OutboxEventTopUpCompleted:
+		topUp, err := r.topUpRepo.GetByID(ctx, message.AggregateID)
+		if err != nil {
+			return fmt.Errorf("failed to load top-up %s: %w", message.AggregateID, err)
+		}
+		return r.publisher.PublishTopUpCompleted(ctx, topUp)
+	default:
+		return fmt.Errorf("unknown outbox event type: %s", message.EventType)
+	}
+}
+
+// backoff returns the retry delay after the given number of previous failures.
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.config.RetryBackoff
+	for i := 0; i < attempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.config.MaxBackoff {
+		delay = r.config.MaxBackoff
+	}
+	return delay
+}
+
+// touchesAny reports whether any of the accounts is in the blocked set.
+func touchesAny(accountIDs []uuid.UUID, blocked map[uuid.UUID]bool) bool {
+	for _, id := range accountIDs {
+		if blocked[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// block adds the accounts to the blocked set.
+func block(accountIDs []uuid.UUID, blocked map[uuid.UUID]bool) {
+	for _, id := range accountIDs {
+		blocked[id] = true
+	}
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeOutboxRepository is an in-memory OutboxRepository for relay tests
+type fakeOutboxRepository struct {
+	messages []*OutboxMessage
+}
+
+func (f *fakeOutboxRepository) Create(ctx context.Context, message *OutboxMessage) error {
+	message.ID = int64(len(f.messages) + 1)
+	f.messages = append(f.messages, message)
+	return nil
+}
+
+// ClaimPending claims due messages like the PostgreSQL repository: messages behind an earlier
+// undispatched message of the same account that is waiting for a retry or claimed are left out
+func (f *fakeOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*OutboxMessage, error) {
+	now := time.Now()
+	held := make(map[uuid.UUID]bool)
+	var claimed []*OutboxMessage
+	for _, m := range f.messages {
+		if m.DispatchedAt != nil {
+			continue
+		}
+		waiting := m.NextAttemptAt.After(now) || (m.ClaimedUntil != nil && m.ClaimedUntil.After(now))
+		if !waiting && !touchesAny(m.AccountIDs, held) && len(claimed) < limit {
+			claimedUntil := now.Add(lease)
+			m.ClaimedUntil = &claimedUntil
+			copied := *m
+			claimed = append(claimed, &copied)
+		}
+		if waiting {
+			block(m.AccountIDs, held)
+		}
+	}
+	return claimed, nil
+}
+
+func (f *fakeOutboxRepository) Update(ctx context.Context, message *OutboxMessage) error {
+	copied := *message
+	f.messages[message.ID-1] = &copied
+	return nil
+}
+
+// fakeTransferLookup serves transfers by ID for the relay
+type fakeTransferLookup struct {
+	TransferRepository
+	transfers map[uuid.UUID]*Transfer
+}
+
+func (f *fakeTransferLookup) GetByID(ctx context.Context, id uuid.UUID) (*Transfer, error) {
+	return f.transfers[id], nil
+}
+
+// fakeTopUpLookup serves top-ups by ID for the relay
+type fakeTopUpLookup struct {
+	TopUpRepository
+	topUps map[uuid.UUID]*TopUp
+}
+
+func (f *fakeTopUpLookup) GetByID(ctx context.Context, id uuid.UUID) (*TopUp, error) {
+	return f.topUps[id], nil
+}
+
+// noTxManager runs the function without a real transaction
+type noTxManager struct{}
+
+func (noTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	return fn(ctx)
+}
+
+// recordingPublisher records published operations and fails for selected ones
+type recordingPublisher struct {
+	failFor   map[uuid.UUID]bool
+	published []uuid.UUID
+}
+
+func (p *recordingPublisher) PublishTransferCompleted(ctx context.Context, transfer *Transfer) error {
+	if p.failFor[transfer.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, transfer.ID)
+	return nil
+}
+
+func (p *recordingPublisher) PublishTopUpCompleted(ctx context.Context, topUp *TopUp) error {
+	if p.failFor[topUp.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, topUp.ID)
+	return nil
+}
+
+func TestOutboxRelay_PreservesPerAccountOrderOnFailure(t *testing.T) {
+	accountA, accountB, accountC, accountD := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	first := &Transfer{ID: uuid.New(), SenderID: accountA, RecipientID: accountB}
+	second := &Transfer{ID: uuid.New(), SenderID: accountB, RecipientID: accountC} // shares accountB with first
+	third := &Transfer{ID: uuid.New(), SenderID: accountC, RecipientID: accountD}  // shares accountC with second
+	unrelated := &Transfer{ID: uuid.New(), SenderID: uuid.New(), RecipientID: uuid.New()}
+	topUp := &TopUp{ID: uuid.New(), AccountID: accountD} // shares accountD with third
+
+	outbox := &fakeOutboxRepository{}
+	transfers := &fakeTransferLookup{transfers: map[uuid.UUID]*Transfer{}}
+	for _, tr := range []*Transfer{first, second, third, unrelated} {
+		transfers.transfers[tr.ID] = tr
+		if err := outbox.Create(context.Background(), NewTransferCompletedMessage(tr)); err != nil {
+			t.Fatalf("failed to create outbox message: %v", err)
+		}
+	}
+	topUps := &fakeTopUpLookup{topUps: map[uuid.UUID]*TopUp{topUp.ID: topUp}}
+	if err := outbox.Create(context.Background(), NewTopUpCompletedMessage(topUp)); err != nil {
+		t.Fatalf("failed to create outbox message: %v", err)
+	}
+
+	publisher := &recordingPublisher{failFor: map[uuid.UUID]bool{first.ID: true}}
+	config := DefaultOutboxRelayConfig()
+	config.RetryBackoff = 0
+	relay := NewOutboxRelay(outbox, transfers, topUps, noTxManager{}, publisher, config)
+
+	dispatched, err := relay.DispatchBatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the unrelated transfer may overtake the failed one
+	if dispatched != 1 || len(publisher.published) != 1 || publisher.published[0] != unrelated.ID {
+		t.Fatalf("expected only the unrelated transfer to be published, got %v", publisher.published)
+	}
+	if outbox.messages[0].Attempts != 1 || outbox.messages[0].LastError == "" {
+		t.Errorf("expected failed attempt to be recorded, got %+v", outbox.messages[0])
+	}
+
+	// Broker recovers: the remaining events are published in order
+	publisher.failFor = nil
+	if _, err := relay.DispatchBatch(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []uuid.UUID{unrelated.ID, first.ID, second.ID, third.ID, topUp.ID}
+	if len(publisher.published) != len(expected) {
+		t.Fatalf("expected %d published events, got %d", len(expected), len(publisher.published))
+	}
+	for i, id := range expected {
+		if publisher.published[i] != id {
+			t.Errorf("event %d: expected %s, got %s", i, id, publisher.published[i])
+		}
+	}
+	for _, m := range outbox.messages {
+		if m.DispatchedAt == nil {
+			t.Errorf("message %d was not marked as dispatched", m.ID)
+		}
+	}
+}
+
+func TestOutboxRelay_Backoff(t *testing.T) {
+	relay := NewOutboxRelay(nil, nil, nil, nil, nil, OutboxRelayConfig{
+		RetryBackoff: time.Second,
+		MaxBackoff:   10 * time.Second,
+	})
+
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempts); got != tt.expected {
+			t.Errorf("backoff(%d): expected %s, got %s", tt.attempts, tt.expected, got)
+		}
+	}
+}
+
+func TestOutboxRelay_SkipsMessagesWaitingForRetry(t *testing.T) {
+	account := uuid.New()
+	waiting := &Transfer{ID: uuid.New(), SenderID: account, RecipientID: uuid.New()}
+	behind := &Transfer{ID: uuid.New(), SenderID: uuid.New(), RecipientID: account} // shares account with waiting
+	unrelated := &Transfer{ID: uuid.New(), SenderID: uuid.New(), RecipientID: uuid.New()}
+
+	outbox := &fakeOutboxRepository{}
+	transfers := &fakeTransferLookup{transfers: map[uuid.UUID]*Transfer{}}
+	for _, tr := range []*Transfer{waiting, behind, unrelated} {
+		transfers.transfers[tr.ID] = tr
+		if err := outbox.Create(context.Background(), NewTransferCompletedMessage(tr)); err != nil {
+			t.Fatalf("failed to create outbox message: %v", err)
+		}
+	}
+	outbox.messages[0].MarkAsFailed("broker unavailable", time.Now().Add(time.Hour))
+
+	// A batch of one is not taken up by the message waiting for its retry
+	publisher := &recordingPublisher{}
+	config := DefaultOutboxRelayConfig()
+	config.BatchSize = 1
+	relay := NewOutboxRelay(outbox, transfers, nil, noTxManager{}, publisher, config)
+
+	dispatched, err := relay.DispatchBatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dispatched != 1 || len(publisher.published) != 1 || publisher.published[0] != unrelated.ID {
+		t.Fatalf("expected only the unrelated transfer to be published, got %v", publisher.published)
+	}
+	for _, m := range outbox.messages {
+		if m.ClaimedUntil != nil {
+			t.Errorf("expected the claim of message %d to be released, got %s", m.ID, m.ClaimedUntil)
+		}
+	}
+	if outbox.messages[1].DispatchedAt != nil {
+		t.Error("expected the transfer behind the waiting one to stay pending")
+	}
+}
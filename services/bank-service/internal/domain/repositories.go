@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -9,6 +10,9 @@ import (
 // AccountRepository defines the interface for account data access operations.
 // This follows the Repository pattern to abstract data persistence logic.
 type AccountRepository interface {
+	// Create persists a new account.
+	Create(ctx context.Context, account *Account) error
+
 	// GetByID retrieves an account by its unique identifier.
 	// Returns an error if the account doesn't exist.
 	GetByID(ctx context.Context, id uuid.UUID) (*Account, error)
@@ -21,6 +25,10 @@ type AccountRepository interface {
 	// This prevents concurrent modifications and ensures consistency.
 	// Must be called within a transaction context.
 	Lock(ctx context.Context, id uuid.UUID) (*Account, error)
+
+	// List retrieves accounts matching the filter ordered by creation time and ID.
+	// Returns at most filter.Limit accounts.
+	List(ctx context.Context, filter AccountFilter) ([]*Account, error)
 }
 
 // TransferRepository defines the interface for transfer data access operations.
@@ -29,16 +37,64 @@ type TransferRepository interface {
 	// Returns an error if a transfer with the same idempotency key already exists.
 	Create(ctx context.Context, transfer *Transfer) error
 
+	// LockIdempotencyKey serializes transfers with the same idempotency key
+	// for the duration of the transaction.
+	// Must be called within a transaction context.
+	LockIdempotencyKey(ctx context.Context, idempotencyKey string) error
+
 	// GetByIdempotencyKey retrieves a transfer by its idempotency key.
 	// Used to implement idempotent transfer operations.
 	// Returns nil if no transfer is found with the given key.
 	GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*Transfer, error)
 
 	// GetByID retrieves a transfer by its unique identifier.
+	// Returns ErrTransferNotFound if the transfer doesn't exist.
 	GetByID(ctx context.Context, id uuid.UUID) (*Transfer, error)
 
 	// Update persists changes to an existing transfer.
 	Update(ctx context.Context, transfer *Transfer) error
+
+	// List retrieves transfers matching the filter ordered by creation time and ID, most recent first.
+	// Returns at most filter.Limit transfers.
+	List(ctx context.Context, filter TransferFilter) ([]*Transfer, error)
+}
+
+// TopUpRepository defines the interface for top-up data access operations.
+type TopUpRepository interface {
+	// Create persists a new top-up record.
+	// Returns an error if a top-up with the same idempotency key already exists.
+	Create(ctx context.Context, topUp *TopUp) error
+
+	// LockIdempotencyKey serializes top-ups with the same idempotency key
+	// for the duration of the transaction.
+	// Must be called within a transaction context.
+	LockIdempotencyKey(ctx context.Context, idempotencyKey string) error
+
+	// GetByIdempotencyKey retrieves a top-up by its idempotency key.
+	// Used to implement idempotent top-up operations.
+	// Returns nil if no top-up is found with the given key.
+	GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*TopUp, error)
+
+	// GetByID retrieves a top-up by its unique identifier.
+	GetByID(ctx context.Context, id uuid.UUID) (*TopUp, error)
+}
+
+// OutboxRepository defines the interface for transactional outbox data access operations.
+type OutboxRepository interface {
+	// Create persists a new outbox message.
+	// Should be called within the same transaction as the business change it describes.
+	Create(ctx context.Context, message *OutboxMessage) error
+
+	// ClaimPending claims up to limit undispatched messages that are due for publication,
+	// in publication order, for the duration of the lease, so that no other relay claims them.
+	// Messages queued behind an earlier undispatched message of one of their accounts
+	// that is waiting for a retry or claimed by another relay are left out, so events stay ordered per account.
+	// Must be called within a transaction context.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*OutboxMessage, error)
+
+	// Update persists changes to an existing outbox message
+	// (dispatch time, attempts, last error, next attempt time and claim).
+	Update(ctx context.Context, message *OutboxMessage) error
 }
 
 // TransactionManager defines the interface for managing database transactions.
@@ -48,5 +104,38 @@ type TransactionManager interface {
 	// WithTransaction executes the given function within a database transaction.
 	// If the function returns an error, the transaction is rolled back.
 	// Otherwise, the transaction is committed.
-	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// If the transaction is aborted by a concurrent one (ErrSerializationFailure),
+	// the function may be executed again in a new transaction, so it must not
+	// keep state from a previous attempt.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error
+}
+
+// IsolationLevel represents the isolation level of a database transaction.
+type IsolationLevel string
+
+const (
+	// IsolationReadCommitted sees data committed before each statement
+	IsolationReadCommitted IsolationLevel = "READ COMMITTED"
+
+	// IsolationRepeatableRead sees data committed before the first statement of the transaction
+	IsolationRepeatableRead IsolationLevel = "REPEATABLE READ"
+
+	// IsolationSerializable behaves as if transactions were executed one after another
+	IsolationSerializable IsolationLevel = "SERIALIZABLE"
+)
+
+// TxOptions holds the settings of a single transaction.
+// Unset fields fall back to the transaction manager defaults.
+type TxOptions struct {
+	IsolationLevel IsolationLevel
+}
+
+// TxOption configures a single WithTransaction call.
+type TxOption func(*TxOptions)
+
+// WithIsolationLevel runs the transaction at the given isolation level.
+func WithIsolationLevel(level IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.IsolationLevel = level
+	}
 }
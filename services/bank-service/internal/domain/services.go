@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 
 	"github.com/google/uuid"
 )
@@ -23,52 +24,91 @@ var (
 
 	// ErrCurrencyMismatch is returned when account and transfer currencies don't match
 	ErrCurrencyMismatch = errors.New("currency mismatch between accounts and transfer")
+
+	// ErrAccountNotActive is returned when a frozen or closed account is asked to send or receive funds
+	ErrAccountNotActive = errors.New("account is not active")
+
+	// ErrAccountClosed is returned when closing an account that is already closed
+	ErrAccountClosed = errors.New("account is already closed")
+
+	// ErrAccountHasBalance is returned when closing an account that still holds funds
+	ErrAccountHasBalance = errors.New("account balance must be zero to close the account")
+
+	// ErrTransferNotFound is returned when a transfer doesn't exist
+	ErrTransferNotFound = errors.New("transfer not found")
+
+	// ErrIdempotencyKeyReused is returned when an idempotency key is reused for a different transfer or top-up
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different operation")
+
+	// ErrDuplicateIdempotencyKey is returned when an operation with the same idempotency key
+	// was recorded concurrently
+	ErrDuplicateIdempotencyKey = errors.New("operation with this idempotency key already exists")
+
+	// ErrSerializationFailure is returned when the transaction was aborted because of
+	// a concurrent transaction (serialization failure or deadlock) and may be retried
+	ErrSerializationFailure = errors.New("transaction aborted due to a concurrent update")
+
+	// ErrConstraintViolation is returned when a change violates a database check constraint
+	ErrConstraintViolation = errors.New("change violates a data constraint")
 )
 
-// TransferService handles the business logic for money transfers.
+// TransferService handles the business logic for money transfers and account top-ups.
 // It coordinates between repositories and ensures transactional consistency.
 type TransferService struct {
 	accountRepo  AccountRepository
 	transferRepo TransferRepository
+	topUpRepo    TopUpRepository
 	txManager    TransactionManager
-	// Optional event publisher to emit domain events (e.g. transfer completed)
-	eventPublisher EventPublisher
+	// Optional outbox for domain events (e.g. transfer completed), drained by OutboxRelay
+	outboxRepo OutboxRepository
 }
 
-// NewTransferService creates a new instance of TransferService.
 // EventPublisher publishes domain events to external systems (e.g. RabbitMQ).
 type EventPublisher interface {
 	PublishTransferCompleted(ctx context.Context, transfer *Transfer) error
+	PublishTopUpCompleted(ctx context.Context, topUp *TopUp) error
 }
 
 // NewTransferService creates a new instance of TransferService.
-// Pass nil for eventPublisher if no events should be emitted.
+// Pass nil for outboxRepo if no events should be recorded.
 func NewTransferService(
 	accountRepo AccountRepository,
 	transferRepo TransferRepository,
+	topUpRepo TopUpRepository,
+	outboxRepo OutboxRepository,
 	txManager TransactionManager,
-	eventPublisher EventPublisher,
 ) *TransferService {
 	return &TransferService{
-		accountRepo:    accountRepo,
-		transferRepo:   transferRepo,
-		txManager:      txManager,
-		eventPublisher: eventPublisher,
+		accountRepo:  accountRepo,
+		transferRepo: transferRepo,
+		topUpRepo:    topUpRepo,
+		txManager:    txManager,
+		outboxRepo:   outboxRepo,
 	}
 }
 
 // ExecuteTransfer processes a money transfer from sender to recipient.
 // This operation is idempotent - calling it multiple times with the same
 // idempotency key will return the same result without executing the transfer again.
+// Reusing the key with a different sender, recipient or amount fails with ErrIdempotencyKeyReused.
+//
+// A transfer declined by business rules (inactive account, currency mismatch or
+// insufficient funds) is recorded and returned in FAILED status with its failure reason,
+// without an error, so replaying its idempotency key returns the same failure.
+// A transfer declined while the accounts are updated (see recordsFailure) is rolled back
+// and then recorded in FAILED status in a separate transaction. Any other error, such as
+// a lost database connection, is not recorded, so a retry with the same key executes the transfer.
 //
 // The transfer is executed atomically within a database transaction:
-// 1. Check if transfer already exists (idempotency)
+// 1. Lock the idempotency key and check if transfer already exists (idempotency)
 // 2. Lock both accounts to prevent concurrent modifications
-// 3. Validate sender has sufficient funds
-// 4. Debit sender account
-// 5. Credit recipient account
-// 6. Create transfer record
-// 7. Commit transaction
+// 3. Validate both accounts are active
+// 4. Validate sender has sufficient funds
+// 5. Debit sender account
+// 6. Credit recipient account
+// 7. Create transfer record
+// 8. Record transfer completed event in the outbox
+// 9. Commit transaction
 //
 // Returns the created/existing transfer or an error if the operation fails.
 func (s *TransferService) ExecuteTransfer(
@@ -83,19 +123,33 @@ func (s *TransferService) ExecuteTransfer(
 		return nil, err
 	}
 
-	// Check for existing transfer with the same idempotency key (idempotency check)
-	existingTransfer, err := s.transferRepo.GetByIdempotencyKey(ctx, idempotencyKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check idempotency: %w", err)
-	}
-	if existingTransfer != nil {
-		// Transfer already processed, return existing result
-		return existingTransfer, nil
-	}
-
-	// Execute transfer within a transaction
+	// Execute transfer within a transaction. It runs at READ COMMITTED whatever the default isolation
+	// level: the idempotency lookup after waiting for the key lock must see a transfer committed
+	// meanwhile, and the locked accounts already prevent concurrent balance changes
 	var transfer *Transfer
-	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Forget the transfer of a previous attempt, so a retried transaction never returns or records it
+		transfer = nil
+
+		// Check for existing transfer with the same idempotency key (idempotency check).
+		// The key is locked first, so a concurrent request with the same key waits
+		// for this transaction and then finds its transfer instead of executing it again.
+		if err := s.transferRepo.LockIdempotencyKey(txCtx, idempotencyKey); err != nil {
+			return err
+		}
+		existingTransfer, err := s.transferRepo.GetByIdempotencyKey(txCtx, idempotencyKey)
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency: %w", err)
+		}
+		if existingTransfer != nil {
+			if !existingTransfer.MatchesRequest(senderID, recipientID, amount) {
+				return ErrIdempotencyKeyReused
+			}
+			// Transfer already processed, return existing result
+			transfer = existingTransfer
+			return nil
+		}
+
 		// Create transfer record in PENDING status
 		transfer = NewTransfer(senderID, recipientID, amount, idempotencyKey)
 
@@ -130,35 +184,31 @@ func (s *TransferService) ExecuteTransfer(
 			return ErrAccountNotFound
 		}
 
+		// Frozen and closed accounts can neither send nor receive funds
+		if !senderAccount.IsActive() || !recipientAccount.IsActive() {
+			return s.declineTransfer(txCtx, transfer, TransferFailureAccountNotActive)
+		}
+
 		// Validate currency consistency
 		if senderAccount.Balance.CurrencyCode != amount.CurrencyCode ||
 			recipientAccount.Balance.CurrencyCode != amount.CurrencyCode {
-			return ErrCurrencyMismatch
+			return s.declineTransfer(txCtx, transfer, TransferFailureCurrencyMismatch)
 		}
 
 		// Check sufficient funds
 		if !senderAccount.HasSufficientFunds(amount) {
-			transfer.MarkAsFailed("Insufficient funds")
-			if err := s.transferRepo.Create(txCtx, transfer); err != nil {
-				return fmt.Errorf("failed to create failed transfer record: %w", err)
-			}
-			return ErrInsufficientFunds
+			return s.declineTransfer(txCtx, transfer, TransferFailureInsufficientFunds)
 		}
 
-		// Execute the transfer
+		// Execute the transfer. A failure here leaves the accounts half-updated, so the
+		// transaction is rolled back and the failed transfer is recorded afterwards
 		if err := senderAccount.Debit(amount); err != nil {
-			transfer.MarkAsFailed(fmt.Sprintf("Failed to debit sender: %v", err))
-			if err := s.transferRepo.Create(txCtx, transfer); err != nil {
-				return fmt.Errorf("failed to create failed transfer record: %w", err)
-			}
+			transfer.MarkAsFailed(TransferFailureInternal, fmt.Sprintf("Failed to debit sender: %v", err))
 			return fmt.Errorf("failed to debit sender account: %w", err)
 		}
 
 		if err := recipientAccount.Credit(amount); err != nil {
-			transfer.MarkAsFailed(fmt.Sprintf("Failed to credit recipient: %v", err))
-			if err := s.transferRepo.Create(txCtx, transfer); err != nil {
-				return fmt.Errorf("failed to create failed transfer record: %w", err)
-			}
+			transfer.MarkAsFailed(TransferFailureInternal, fmt.Sprintf("Failed to credit recipient: %v", err))
 			return fmt.Errorf("failed to credit recipient account: %w", err)
 		}
 
@@ -178,29 +228,188 @@ func (s *TransferService) ExecuteTransfer(
 			return fmt.Errorf("failed to create transfer record: %w", err)
 		}
 
+		// Record the event in the same transaction so it is published if and only if
+		// the transfer commits (transactional outbox)
+		if s.outboxRepo != nil {
+			if err := s.outboxRepo.Create(txCtx, NewTransferCompletedMessage(transfer)); err != nil {
+				return fmt.Errorf("failed to record transfer completed event: %w", err)
+			}
+		}
+
 		return nil
-	})
+	}, WithIsolationLevel(IsolationReadCommitted))
+
+	if err != nil {
+		// The rollback discarded the failed transfer, record it on its own
+		if transfer != nil && recordsFailure(err) {
+			if transfer.Status != TransferStatusFailed {
+				transfer.MarkAsFailed(TransferFailureInternal, "Transfer failed due to an internal error")
+			}
+			s.recordFailedTransfer(ctx, transfer)
+		}
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// recordedFailures are the errors of a rolled-back transfer that are recorded as its failure:
+// business declines detected once the accounts are being updated, which fail again on a retry.
+var recordedFailures = []error{
+	ErrInsufficientFunds,
+	ErrAccountNotActive,
+	ErrCurrencyMismatch,
+	ErrMoneyOverflow,
+}
 
+// recordsFailure reports whether a transfer whose transaction failed with err is recorded as failed.
+// Only the business declines of recordedFailures are, so that infrastructure errors do not
+// bind the idempotency key to a failure the client cannot retry.
+func recordsFailure(err error) bool {
+	for _, failure := range recordedFailures {
+		if errors.Is(err, failure) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordFailedTransfer persists a transfer that failed in a rolled-back transaction,
+// so replays of its idempotency key, audits and ListTransfers see the failure.
+// Recording is best-effort: the original error is returned to the caller either way.
+func (s *TransferService) recordFailedTransfer(ctx context.Context, transfer *Transfer) {
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.transferRepo.LockIdempotencyKey(txCtx, transfer.IdempotencyKey); err != nil {
+			return err
+		}
+		existing, err := s.transferRepo.GetByIdempotencyKey(txCtx, transfer.IdempotencyKey)
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency: %w", err)
+		}
+		if existing != nil {
+			// A concurrent request with the same key has been recorded meanwhile
+			return nil
+		}
+		return s.transferRepo.Create(txCtx, transfer)
+	})
 	if err != nil {
+		log.Printf("failed to record failed transfer %s: %v", transfer.ID, err)
+	}
+}
+
+// declineTransfer marks the transfer as failed for the given reason and records it.
+// It must be called before any account is changed, since the transaction is committed.
+func (s *TransferService) declineTransfer(ctx context.Context, transfer *Transfer, reason TransferFailureReason) error {
+	transfer.MarkAsFailed(reason, declineMessages[reason])
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		return fmt.Errorf("failed to create failed transfer record: %w", err)
+	}
+	return nil
+}
+
+// declineMessages are the human-readable messages of declined transfers
+var declineMessages = map[TransferFailureReason]string{
+	TransferFailureInsufficientFunds: "Insufficient funds",
+	TransferFailureAccountNotActive:  "Sender or recipient account is not active",
+	TransferFailureCurrencyMismatch:  "Currency mismatch between accounts and transfer",
+}
+
+// ExecuteTopUp adds funds to an account from an external source.
+// This operation is idempotent - calling it multiple times with the same
+// idempotency key will return the same result without crediting the account again.
+// Reusing the key with a different account or amount fails with ErrIdempotencyKeyReused.
+//
+// The top-up is executed atomically within a database transaction:
+// 1. Lock the idempotency key and check if top-up already exists (idempotency)
+// 2. Lock the account to prevent concurrent modifications
+// 3. Validate the account is active
+// 4. Credit the account
+// 5. Create top-up record with the resulting balance
+// 6. Record top-up completed event in the outbox
+// 7. Commit transaction
+//
+// Returns the created/existing top-up or an error if the operation fails.
+func (s *TransferService) ExecuteTopUp(
+	ctx context.Context,
+	accountID uuid.UUID,
+	amount Amount,
+	idempotencyKey string,
+	source string,
+	externalTransactionID string,
+) (*TopUp, error) {
+	// Validate input parameters
+	if err := s.validateTopUpRequest(amount); err != nil {
 		return nil, err
 	}
 
-	// After successful transaction commit, publish transfer completed event (best-effort).
-	// We publish asynchronously so that transient RabbitMQ failures don't make the
-	// already-committed transfer appear to fail. Production systems should use
-	// a durable outbox or at-least-once delivery with retry for stronger guarantees.
-	if s.eventPublisher != nil {
-		// capture transfer for goroutine
-		go func(t *Transfer) {
-			if err := s.eventPublisher.PublishTransferCompleted(context.Background(), t); err != nil {
-				// Best-effort: log the failure. Domain package doesn't have structured
-				// logging; print to stderr for now. Consider replacing with a logger.
-				fmt.Printf("warning: failed to publish transfer completed event: %v\n", err)
+	// Execute top-up within a transaction, at READ COMMITTED for the same reasons as ExecuteTransfer
+	var topUp *TopUp
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Check for existing top-up with the same idempotency key (idempotency check).
+		// The key is locked first, so a concurrent request with the same key waits
+		// for this transaction and then finds its top-up instead of crediting the account again.
+		if err := s.topUpRepo.LockIdempotencyKey(txCtx, idempotencyKey); err != nil {
+			return err
+		}
+		existingTopUp, err := s.topUpRepo.GetByIdempotencyKey(txCtx, idempotencyKey)
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency: %w", err)
+		}
+		if existingTopUp != nil {
+			if !existingTopUp.MatchesRequest(accountID, amount) {
+				return ErrIdempotencyKeyReused
+			}
+			// Top-up already processed, return existing result
+			topUp = existingTopUp
+			return nil
+		}
+
+		topUp = NewTopUp(accountID, amount, idempotencyKey, source, externalTransactionID)
+
+		// Lock account to prevent concurrent modifications
+		account, err := s.accountRepo.Lock(txCtx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		if account == nil {
+			return ErrAccountNotFound
+		}
+		if !account.IsActive() {
+			return ErrAccountNotActive
+		}
+
+		// Validate currency consistency
+		if account.Balance.CurrencyCode != amount.CurrencyCode {
+			return ErrCurrencyMismatch
+		}
+
+		if err := account.Credit(amount); err != nil {
+			return fmt.Errorf("failed to credit account: %w", err)
+		}
+		if err := s.accountRepo.Update(txCtx, account); err != nil {
+			return fmt.Errorf("failed to update account: %w", err)
+		}
+
+		// Mark top-up as successful and create the record
+		topUp.MarkAsSuccess("Top-up completed successfully", account.Balance)
+		if err := s.topUpRepo.Create(txCtx, topUp); err != nil {
+			return fmt.Errorf("failed to create top-up record: %w", err)
+		}
+
+		if s.outboxRepo != nil {
+			if err := s.outboxRepo.Create(txCtx, NewTopUpCompletedMessage(topUp)); err != nil {
+				return fmt.Errorf("failed to record top-up completed event: %w", err)
 			}
-		}(transfer)
+		}
+
+		return nil
+	}, WithIsolationLevel(IsolationReadCommitted))
+
+	if err != nil {
+		return nil, err
 	}
 
-	return transfer, nil
+	return topUp, nil
 }
 
 // GetAccountBalance retrieves the current balance of an account.
@@ -215,6 +424,54 @@ func (s *TransferService) GetAccountBalance(ctx context.Context, accountID uuid.
 	return account, nil
 }
 
+// GetTransfer retrieves a transfer by its operation ID.
+func (s *TransferService) GetTransfer(ctx context.Context, id uuid.UUID) (*Transfer, error) {
+	transfer, err := s.transferRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrTransferNotFound) {
+			return nil, ErrTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// GetTransferByIdempotencyKey retrieves a transfer by the idempotency key it was requested with.
+func (s *TransferService) GetTransferByIdempotencyKey(ctx context.Context, idempotencyKey string) (*Transfer, error) {
+	transfer, err := s.transferRepo.GetByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+	if transfer == nil {
+		return nil, ErrTransferNotFound
+	}
+	return transfer, nil
+}
+
+// ListTransfers returns a page of the transfers of an account matching the filter, most recent first.
+// The returned flag reports whether more transfers follow the page.
+func (s *TransferService) ListTransfers(ctx context.Context, filter TransferFilter) ([]*Transfer, bool, error) {
+	if filter.Limit <= 0 {
+		return nil, false, errors.New("limit must be positive")
+	}
+
+	// Fetch one extra transfer to find out whether another page exists
+	pageSize := filter.Limit
+	filter.Limit++
+
+	transfers, err := s.transferRepo.List(ctx, filter)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list transfers: %w", err)
+	}
+
+	hasMore := len(transfers) > pageSize
+	if hasMore {
+		transfers = transfers[:pageSize]
+	}
+
+	return transfers, hasMore, nil
+}
+
 // validateTransferRequest validates the transfer request parameters.
 func (s *TransferService) validateTransferRequest(senderID, recipientID uuid.UUID, amount Amount) error {
 	// Check sender and recipient are different
@@ -223,8 +480,7 @@ func (s *TransferService) validateTransferRequest(senderID, recipientID uuid.UUI
 	}
 
 	// Validate amount is positive
-	// TODO: Implement proper decimal validation
-	if amount.Value == "" || amount.Value == "0" || amount.Value == "0.00" {
+	if !amount.Value.IsPositive() {
 		return ErrInvalidAmount
 	}
 
@@ -235,3 +491,106 @@ func (s *TransferService) validateTransferRequest(senderID, recipientID uuid.UUI
 
 	return nil
 }
+
+// validateTopUpRequest validates the top-up request parameters.
+func (s *TransferService) validateTopUpRequest(amount Amount) error {
+	if !amount.Value.IsPositive() {
+		return ErrInvalidAmount
+	}
+
+	if amount.CurrencyCode == "" {
+		return errors.New("currency code is required")
+	}
+
+	return nil
+}
+
+// AccountService handles the business logic of the account lifecycle:
+// opening, closing and listing accounts.
+type AccountService struct {
+	accountRepo AccountRepository
+	txManager   TransactionManager
+}
+
+// NewAccountService creates a new instance of AccountService.
+func NewAccountService(accountRepo AccountRepository, txManager TransactionManager) *AccountService {
+	return &AccountService{
+		accountRepo: accountRepo,
+		txManager:   txManager,
+	}
+}
+
+// CreateAccount opens a new ACTIVE account with a zero balance for the given owner.
+func (s *AccountService) CreateAccount(ctx context.Context, ownerID, ownerName, currencyCode string) (*Account, error) {
+	if ownerID == "" {
+		return nil, errors.New("owner id is required")
+	}
+	if err := ValidateCurrencyCode(currencyCode); err != nil {
+		return nil, err
+	}
+
+	account := OpenAccount(ownerID, ownerName, currencyCode)
+	if err := s.accountRepo.Create(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	return account, nil
+}
+
+// CloseAccount closes an account permanently.
+// The account is locked so that no transfer or top-up can credit it while it is being closed.
+// Returns ErrAccountHasBalance if the account still holds funds and ErrAccountClosed
+// if it is already closed.
+func (s *AccountService) CloseAccount(ctx context.Context, accountID uuid.UUID) (*Account, error) {
+	var account *Account
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		account, err = s.accountRepo.Lock(txCtx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		if account == nil {
+			return ErrAccountNotFound
+		}
+
+		if err := account.Close(); err != nil {
+			return err
+		}
+
+		if err := s.accountRepo.Update(txCtx, account); err != nil {
+			return fmt.Errorf("failed to update account: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// ListAccounts returns a page of accounts matching the filter, ordered by creation time.
+// The returned flag reports whether more accounts follow the page.
+func (s *AccountService) ListAccounts(ctx context.Context, filter AccountFilter) ([]*Account, bool, error) {
+	if filter.Limit <= 0 {
+		return nil, false, errors.New("limit must be positive")
+	}
+
+	// Fetch one extra account to find out whether another page exists
+	pageSize := filter.Limit
+	filter.Limit++
+
+	accounts, err := s.accountRepo.List(ctx, filter)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	hasMore := len(accounts) > pageSize
+	if hasMore {
+		accounts = accounts[:pageSize]
+	}
+
+	return accounts, hasMore, nil
+}
@@ -0,0 +1,212 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db/memory"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// newTestTransferService creates a transfer service over in-memory repositories with the given accounts.
+// Events are recorded in the outbox if one is given.
+func newTestTransferService(t *testing.T, outbox domain.OutboxRepository, accounts ...*domain.Account) (*domain.TransferService, *memory.AccountRepository, *memory.TransferRepository) {
+	t.Helper()
+
+	store := memory.NewStore()
+	accountRepo := memory.NewAccountRepository(store)
+	for _, account := range accounts {
+		if err := accountRepo.Create(context.Background(), account); err != nil {
+			t.Fatalf("failed to create account: %v", err)
+		}
+	}
+	transferRepo := memory.NewTransferRepository(store)
+	txManager := memory.NewTransactionManager(store)
+	return domain.NewTransferService(accountRepo, transferRepo, nil, outbox, txManager), accountRepo, transferRepo
+}
+
+// fakeOutbox records outbox messages and fails to record events of one type.
+// Messages are not rolled back with the transaction, so only successful transactions may record them.
+type fakeOutbox struct {
+	domain.OutboxRepository
+	failWith error
+	failFor  domain.OutboxEventType
+	messages []*domain.OutboxMessage
+}
+
+func (o *fakeOutbox) Create(ctx context.Context, message *domain.OutboxMessage) error {
+	if message.EventType == o.failFor {
+		return o.failWith
+	}
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+// balanceOf returns the committed balance of the account
+func balanceOf(t *testing.T, accountRepo *memory.AccountRepository, id uuid.UUID) domain.Money {
+	t.Helper()
+
+	account, err := accountRepo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	return account.Balance.Value
+}
+
+func TestExecuteTransfer_RecordsDecline(t *testing.T) {
+	sender := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(1000), CurrencyCode: "RUB"})
+	recipient := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(0), CurrencyCode: "RUB"})
+	service, accountRepo, transferRepo := newTestTransferService(t, nil, sender, recipient)
+
+	amount := domain.Amount{Value: domain.NewMoney(5000), CurrencyCode: "RUB"}
+	transfer, err := service.ExecuteTransfer(context.Background(), sender.ID, recipient.ID, amount, "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transfer.Status != domain.TransferStatusFailed || transfer.FailureReason != domain.TransferFailureInsufficientFunds {
+		t.Errorf("expected FAILED with INSUFFICIENT_FUNDS, got %s %q", transfer.Status, transfer.FailureReason)
+	}
+
+	stored, err := transferRepo.GetByIdempotencyKey(context.Background(), "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || stored.ID != transfer.ID || stored.Status != domain.TransferStatusFailed {
+		t.Errorf("expected declined transfer to be recorded, got %+v", stored)
+	}
+	if balance := balanceOf(t, accountRepo, sender.ID); balance != domain.NewMoney(1000) {
+		t.Errorf("sender balance changed: %s", balance)
+	}
+}
+
+func TestExecuteTransfer_RecordsInternalFailure(t *testing.T) {
+	sender := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(1000), CurrencyCode: "RUB"})
+	// Crediting the recipient overflows its balance
+	recipient := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(999_999_999_999_999), CurrencyCode: "RUB"})
+	service, accountRepo, transferRepo := newTestTransferService(t, nil, sender, recipient)
+
+	amount := domain.Amount{Value: domain.NewMoney(100), CurrencyCode: "RUB"}
+	_, err := service.ExecuteTransfer(context.Background(), sender.ID, recipient.ID, amount, "key-1")
+	if !errors.Is(err, domain.ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow, got %v", err)
+	}
+
+	// The failure is recorded although the transaction was rolled back
+	stored, err := transferRepo.GetByIdempotencyKey(context.Background(), "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || stored.Status != domain.TransferStatusFailed || stored.FailureReason != domain.TransferFailureInternal {
+		t.Fatalf("expected FAILED transfer with INTERNAL reason to be recorded, got %+v", stored)
+	}
+	if balance := balanceOf(t, accountRepo, sender.ID); balance != domain.NewMoney(1000) {
+		t.Errorf("sender balance changed: %s", balance)
+	}
+
+	// Replaying the key returns the recorded failure instead of running the transfer again
+	replayed, err := service.ExecuteTransfer(context.Background(), sender.ID, recipient.ID, amount, "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error on replay: %v", err)
+	}
+	if replayed.ID != stored.ID || replayed.FailureReason != domain.TransferFailureInternal {
+		t.Errorf("expected recorded failure %s, got %+v", stored.ID, replayed)
+	}
+}
+
+func TestExecuteTransfer_DoesNotRecordInfrastructureFailure(t *testing.T) {
+	sender := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(1000), CurrencyCode: "RUB"})
+	recipient := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(0), CurrencyCode: "RUB"})
+	// Recording the completed event fails after both accounts were updated
+	outbox := &fakeOutbox{failFor: domain.OutboxEventTransferCompleted, failWith: errors.New("connection reset")}
+	service, accountRepo, transferRepo := newTestTransferService(t, outbox, sender, recipient)
+
+	amount := domain.Amount{Value: domain.NewMoney(100), CurrencyCode: "RUB"}
+	if _, err := service.ExecuteTransfer(context.Background(), sender.ID, recipient.ID, amount, "key-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	stored, err := transferRepo.GetByIdempotencyKey(context.Background(), "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != nil {
+		t.Fatalf("expected nothing to be recorded, got %+v", stored)
+	}
+	if balance := balanceOf(t, accountRepo, sender.ID); balance != domain.NewMoney(1000) {
+		t.Errorf("sender balance changed: %s", balance)
+	}
+
+	// Once the outbox recovers, a retry with the same key executes the transfer
+	outbox.failFor = ""
+	transfer, err := service.ExecuteTransfer(context.Background(), sender.ID, recipient.ID, amount, "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if transfer.Status != domain.TransferStatusSuccess {
+		t.Errorf("expected retry to succeed, got %s", transfer.Status)
+	}
+}
+
+func TestExecuteTransfer_DoesNotRecordRetryableFailure(t *testing.T) {
+	sender := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(1000), CurrencyCode: "RUB"})
+	recipient := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(0), CurrencyCode: "RUB"})
+	outbox := &fakeOutbox{failFor: domain.OutboxEventTransferCompleted, failWith: domain.ErrSerializationFailure}
+	service, _, transferRepo := newTestTransferService(t, outbox, sender, recipient)
+
+	amount := domain.Amount{Value: domain.NewMoney(100), CurrencyCode: "RUB"}
+	if _, err := service.ExecuteTransfer(context.Background(), sender.ID, recipient.ID, amount, "key-1"); !errors.Is(err, domain.ErrSerializationFailure) {
+		t.Fatalf("expected ErrSerializationFailure, got %v", err)
+	}
+
+	// A retry with the same key executes the transfer
+	stored, err := transferRepo.GetByIdempotencyKey(context.Background(), "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != nil || len(outbox.messages) != 0 {
+		t.Errorf("expected nothing to be recorded, got %+v and %+v", stored, outbox.messages)
+	}
+}
+
+// isolationRecorder records the isolation level requested by each transaction
+type isolationRecorder struct {
+	*memory.TransactionManager
+	levels []domain.IsolationLevel
+}
+
+func (r *isolationRecorder) WithTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...domain.TxOption) error {
+	var options domain.TxOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	r.levels = append(r.levels, options.IsolationLevel)
+	return r.TransactionManager.WithTransaction(ctx, fn, opts...)
+}
+
+func TestExecuteTransfer_RunsAtReadCommitted(t *testing.T) {
+	sender := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(1000), CurrencyCode: "RUB"})
+	recipient := domain.NewAccount(uuid.New(), domain.Amount{Value: domain.NewMoney(0), CurrencyCode: "RUB"})
+
+	store := memory.NewStore()
+	accountRepo := memory.NewAccountRepository(store)
+	for _, account := range []*domain.Account{sender, recipient} {
+		if err := accountRepo.Create(context.Background(), account); err != nil {
+			t.Fatalf("failed to create account: %v", err)
+		}
+	}
+	txManager := &isolationRecorder{TransactionManager: memory.NewTransactionManager(store)}
+	service := domain.NewTransferService(accountRepo, memory.NewTransferRepository(store), nil, nil, txManager)
+
+	amount := domain.Amount{Value: domain.NewMoney(100), CurrencyCode: "RUB"}
+	if _, err := service.ExecuteTransfer(context.Background(), sender.ID, recipient.ID, amount, "key-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The idempotency lookup must see transfers committed while it waited for the key lock
+	if len(txManager.levels) != 1 || txManager.levels[0] != domain.IsolationReadCommitted {
+		t.Errorf("expected a single READ COMMITTED transaction, got %v", txManager.levels)
+	}
+}
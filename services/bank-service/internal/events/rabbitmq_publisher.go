@@ -0,0 +1,359 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+const (
+	// confirmTimeout bounds how long a publish waits for a broker confirmation.
+	confirmTimeout = 5 * time.Second
+
+	// Reconnect backoff bounds used after a connection or channel loss.
+	initialReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay     = 30 * time.Second
+)
+
+var (
+	// ErrPublisherClosed is returned when publishing on a closed publisher
+	ErrPublisherClosed = errors.New("rabbitmq publisher is closed")
+
+	// ErrPublisherDisconnected is returned by CheckConnection while the publisher has no open channel
+	ErrPublisherDisconnected = errors.New("rabbitmq publisher is not connected")
+
+	// ErrPublishNotConfirmed is returned when the broker nacks a publishing
+	// or the confirmation does not arrive in time
+	ErrPublishNotConfirmed = errors.New("publishing was not confirmed by broker")
+)
+
+// RabbitMQPublisher implements domain.EventPublisher using RabbitMQ.
+// Publishings are sent in confirm mode, so a successful return means the broker
+// has taken responsibility for the message. The publisher transparently
+// re-establishes the connection and channel if either is closed.
+type RabbitMQPublisher struct {
+	url             string
+	exchange        string
+	routingKey      string // Routing key for transfer.completed events
+	topUpRoutingKey string // Routing key for topup.completed events
+
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	closed  bool
+	done    chan struct{}
+
+	reconnectMu sync.Mutex // Serializes reconnects, so that concurrent callers dial only once
+}
+
+// NewRabbitMQPublisher connects to RabbitMQ, declares the topic exchange
+// and puts the channel into confirm mode.
+func NewRabbitMQPublisher(url, exchange, routingKey, topUpRoutingKey string) (*RabbitMQPublisher, error) {
+	p := &RabbitMQPublisher{
+		url:             url,
+		exchange:        exchange,
+		routingKey:      routingKey,
+		topUpRoutingKey: topUpRoutingKey,
+		done:            make(chan struct{}),
+	}
+
+	if err := p.connect(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("RabbitMQ publisher initialized: exchange=%s, routing_keys=%s,%s", exchange, routingKey, topUpRoutingKey)
+	return p, nil
+}
+
+// PublishTransferCompleted publishes a transfer.completed event for the given transfer
+// and waits for the broker confirmation.
+func (p *RabbitMQPublisher) PublishTransferCompleted(ctx context.Context, transfer *domain.Transfer) error {
+	if transfer == nil {
+		return fmt.Errorf("transfer is required")
+	}
+
+	event := NewTransferCompletedEvent(transfer)
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transfer completed event: %w", err)
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.EventID,
+		Timestamp:    time.Now().UTC(),
+		Type:         event.EventType,
+		Body:         body,
+	}
+
+	return p.publish(ctx, p.routingKey, msg)
+}
+
+// PublishTopUpCompleted publishes a topup.completed event for the given top-up
+// and waits for the broker confirmation.
+func (p *RabbitMQPublisher) PublishTopUpCompleted(ctx context.Context, topUp *domain.TopUp) error {
+	if topUp == nil {
+		return fmt.Errorf("top-up is required")
+	}
+
+	event := NewTopUpCompletedEvent(topUp)
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal top-up completed event: %w", err)
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.EventID,
+		Timestamp:    time.Now().UTC(),
+		Type:         event.EventType,
+		Body:         body,
+	}
+
+	return p.publish(ctx, p.topUpRoutingKey, msg)
+}
+
+// publish sends a message and waits for its confirmation.
+// If the channel turns out to be closed, it reconnects and retries once.
+func (p *RabbitMQPublisher) publish(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	err := p.publishOnce(ctx, routingKey, msg)
+	if err == nil || !errors.Is(err, amqp.ErrClosed) {
+		return err
+	}
+
+	log.Printf("RabbitMQ channel closed while publishing, reconnecting: %v", err)
+	if err := p.reconnect(); err != nil {
+		return err
+	}
+	return p.publishOnce(ctx, routingKey, msg)
+}
+
+// publishOnce sends a single message on the current channel and waits for the confirmation.
+func (p *RabbitMQPublisher) publishOnce(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	channel, err := p.currentChannel()
+	if err != nil {
+		return err
+	}
+
+	confirmCtx, cancel := context.WithTimeout(ctx, confirmTimeout)
+	defer cancel()
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		confirmCtx,
+		p.exchange, // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		msg,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	if !confirmation.Wait() {
+		if channel.IsClosed() {
+			return fmt.Errorf("%w: %w", ErrPublishNotConfirmed, amqp.ErrClosed)
+		}
+		return ErrPublishNotConfirmed
+	}
+
+	return nil
+}
+
+// currentChannel returns an open channel, reconnecting if necessary.
+func (p *RabbitMQPublisher) currentChannel() (*amqp.Channel, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPublisherClosed
+	}
+	channel := p.channel
+	p.mu.Unlock()
+
+	if channel != nil && !channel.IsClosed() {
+		return channel, nil
+	}
+
+	if err := p.reconnect(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.channel, nil
+}
+
+// CheckConnection returns an error unless the publisher has an open channel to RabbitMQ,
+// e.g. while it reconnects after the connection was lost. It does not contact the broker;
+// a dead connection is detected by the client's heartbeats.
+func (p *RabbitMQPublisher) CheckConnection() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	if p.channel == nil || p.channel.IsClosed() {
+		return ErrPublisherDisconnected
+	}
+	return nil
+}
+
+// connect dials RabbitMQ, opens a confirm-mode channel, declares the exchange
+// and starts watching for connection and channel closures.
+func (p *RabbitMQPublisher) connect() error {
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	// Declare exchange (topic exchange per AsyncAPI spec)
+	err = channel.ExchangeDeclare(
+		p.exchange, // name
+		"topic",    // type
+		true,       // durable
+		false,      // auto-deleted
+		false,      // internal
+		false,      // no-wait
+		nil,        // arguments
+	)
+	if err != nil {
+		channel.Close()
+		conn.Close()
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	// Enable publisher confirms
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		conn.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		channel.Close()
+		conn.Close()
+		return ErrPublisherClosed
+	}
+	previous := p.conn
+	p.conn = conn
+	p.channel = channel
+	p.mu.Unlock()
+
+	// Closing the replaced connection also stops its watcher
+	if previous != nil && !previous.IsClosed() {
+		previous.Close()
+	}
+
+	go p.watch(conn, channel)
+
+	return nil
+}
+
+// reconnect replaces the current connection and channel if they are closed.
+// Publishers and the watcher may call it concurrently; reconnects are serialized,
+// and callers waiting for a reconnect in progress return once it has succeeded.
+func (p *RabbitMQPublisher) reconnect() error {
+	p.reconnectMu.Lock()
+	defer p.reconnectMu.Unlock()
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPublisherClosed
+	}
+	if p.channel != nil && !p.channel.IsClosed() {
+		// Another goroutine already reconnected
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	return p.connect()
+}
+
+// watch waits for the connection or channel to close and re-establishes them
+// in the background with exponential backoff until the publisher is closed.
+func (p *RabbitMQPublisher) watch(conn *amqp.Connection, channel *amqp.Channel) {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	channelClosed := channel.NotifyClose(make(chan *amqp.Error, 1))
+
+	var reason *amqp.Error
+	select {
+	case <-p.done:
+		return
+	case reason = <-connClosed:
+	case reason = <-channelClosed:
+	}
+
+	if reason == nil {
+		// Graceful close initiated by us (Close or reconnect)
+		return
+	}
+	log.Printf("RabbitMQ publisher connection lost: %v", reason)
+
+	delay := initialReconnectDelay
+	for {
+		err := p.reconnect()
+		if err == nil {
+			log.Println("RabbitMQ publisher reconnected")
+			return
+		}
+		if errors.Is(err, ErrPublisherClosed) {
+			return
+		}
+		log.Printf("RabbitMQ publisher reconnect failed, retrying in %s: %v", delay, err)
+
+		select {
+		case <-p.done:
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// Close closes the RabbitMQ channel and connection.
+func (p *RabbitMQPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+
+	if p.channel != nil {
+		if err := p.channel.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+			log.Printf("Error closing channel: %v", err)
+		}
+	}
+	if p.conn != nil {
+		if err := p.conn.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+			return fmt.Errorf("failed to close connection: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"time"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// EventTypeTopUpCompleted is the eventType value for top-up completion events.
+const EventTypeTopUpCompleted = "topup.completed"
+
+// TopUpCompletedEvent represents the payload published when a top-up is completed.
+// This matches the schema defined in services/common/analytics-service-kafka-spec/schemas/topup_event.json
+type TopUpCompletedEvent struct {
+	EventID               string `json:"eventId"`
+	EventType             string `json:"eventType"`
+	EventTimestamp        string `json:"eventTimestamp"`
+	OperationID           string `json:"operationId"`
+	AccountID             string `json:"accountId"`
+	Amount                Amount `json:"amount"`
+	IdempotencyKey        string `json:"idempotencyKey"`
+	Source                string `json:"source,omitempty"`                // Optional field
+	ExternalTransactionID string `json:"externalTransactionId,omitempty"` // Optional field
+	Status                string `json:"status"`
+	Timestamp             string `json:"timestamp"`
+	Message               string `json:"message,omitempty"` // Optional field
+}
+
+// NewTopUpCompletedEvent builds a TopUpCompletedEvent from a domain top-up.
+// The event timestamp is taken from the top-up completion time when available.
+func NewTopUpCompletedEvent(topUp *domain.TopUp) TopUpCompletedEvent {
+	executedAt := topUp.CreatedAt
+	if topUp.CompletedAt != nil {
+		executedAt = *topUp.CompletedAt
+	}
+
+	return TopUpCompletedEvent{
+		EventID:        eventID(topUp.ID, EventTypeTopUpCompleted),
+		EventType:      EventTypeTopUpCompleted,
+		EventTimestamp: formatTimestamp(time.Now()),
+		OperationID:    topUp.ID.String(),
+		AccountID:      topUp.AccountID.String(),
+		Amount: Amount{
+			Value:        topUp.Amount.Value.String(),
+			CurrencyCode: topUp.Amount.CurrencyCode,
+		},
+		IdempotencyKey:        topUp.IdempotencyKey,
+		Source:                topUp.Source,
+		ExternalTransactionID: topUp.ExternalTransactionID,
+		Status:                string(domain.TopUpStatusSuccess),
+		Timestamp:             formatTimestamp(executedAt),
+		Message:               topUp.Message,
+	}
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// TestNewTopUpCompletedEvent_MatchesSchema verifies the event payload
+// follows services/common/analytics-service-kafka-spec/schemas/topup_event.json
+func TestNewTopUpCompletedEvent_MatchesSchema(t *testing.T) {
+	completedAt := time.Date(2025, 11, 8, 14, 30, 0, 0, time.UTC)
+	topUp := &domain.TopUp{
+		ID:             uuid.New(),
+		AccountID:      uuid.New(),
+		Amount:         domain.Amount{Value: domain.NewMoney(50000), CurrencyCode: "RUB"},
+		IdempotencyKey: uuid.New().String(),
+		Source:         "bank_card",
+		Status:         domain.TopUpStatusSuccess,
+		Message:        "Top-up completed successfully",
+		NewBalance:     domain.Amount{Value: domain.NewMoney(150000), CurrencyCode: "RUB"},
+		CreatedAt:      completedAt.Add(-time.Second),
+		CompletedAt:    &completedAt,
+	}
+
+	body, err := json.Marshal(NewTopUpCompletedEvent(topUp))
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to unmarshal event: %v", err)
+	}
+
+	required := []string{
+		"eventId", "eventType", "eventTimestamp", "operationId", "accountId",
+		"amount", "idempotencyKey", "status", "timestamp",
+	}
+	for _, field := range required {
+		if _, ok := payload[field]; !ok {
+			t.Errorf("required field %q is missing", field)
+		}
+	}
+
+	if payload["eventType"] != "topup.completed" {
+		t.Errorf("expected eventType topup.completed, got %v", payload["eventType"])
+	}
+	if payload["accountId"] != topUp.AccountID.String() {
+		t.Errorf("expected accountId %s, got %v", topUp.AccountID, payload["accountId"])
+	}
+	if payload["source"] != "bank_card" {
+		t.Errorf("expected source bank_card, got %v", payload["source"])
+	}
+	if payload["timestamp"] != "2025-11-08T14:30:00.000Z" {
+		t.Errorf("expected timestamp 2025-11-08T14:30:00.000Z, got %v", payload["timestamp"])
+	}
+	if _, ok := payload["externalTransactionId"]; ok {
+		t.Error("expected empty externalTransactionId to be omitted")
+	}
+
+	amount, ok := payload["amount"].(map[string]interface{})
+	if !ok {
+		t.Fatal("amount is not an object")
+	}
+	if amount["value"] != "500.00" {
+		t.Errorf("expected amount value 500.00, got %v", amount["value"])
+	}
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+const (
+	// EventTypeTransferCompleted is the eventType value for transfer completion events.
+	EventTypeTransferCompleted = "transfer.completed"
+
+	// timestampLayout is the ISO 8601 layout used by the AsyncAPI spec (e.g. "2025-11-08T14:30:00.000Z").
+	timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// TransferCompletedEvent represents the payload published when a transfer is completed.
+// This matches the schema defined in services/common/analytics-service-kafka-spec/schemas/transfer_event.json
+type TransferCompletedEvent struct {
+	EventID        string `json:"eventId"`
+	EventType      string `json:"eventType"`
+	EventTimestamp string `json:"eventTimestamp"`
+	OperationID    string `json:"operationId"`
+	SenderID       string `json:"senderId"`
+	RecipientID    string `json:"recipientId"`
+	Amount         Amount `json:"amount"`
+	IdempotencyKey string `json:"idempotencyKey"`
+	Status         string `json:"status"`
+	Timestamp      string `json:"timestamp"`
+	Message        string `json:"message,omitempty"` // Optional field
+}
+
+// Amount represents a monetary value with its currency in event payloads.
+type Amount struct {
+	Value        string `json:"value"`        // Decimal string with exactly 2 decimal places (e.g., "150.50")
+	CurrencyCode string `json:"currencyCode"` // ISO 4217 currency code (e.g., "RUB")
+}
+
+// NewTransferCompletedEvent builds a TransferCompletedEvent from a domain transfer.
+// The event timestamp is taken from the transfer completion time when available.
+func NewTransferCompletedEvent(transfer *domain.Transfer) TransferCompletedEvent {
+	executedAt := transfer.CreatedAt
+	if transfer.CompletedAt != nil {
+		executedAt = *transfer.CompletedAt
+	}
+
+	return TransferCompletedEvent{
+		EventID:        eventID(transfer.ID, EventTypeTransferCompleted),
+		EventType:      EventTypeTransferCompleted,
+		EventTimestamp: formatTimestamp(time.Now()),
+		OperationID:    transfer.ID.String(),
+		SenderID:       transfer.SenderID.String(),
+		RecipientID:    transfer.RecipientID.String(),
+		Amount: Amount{
+			Value:        transfer.Amount.Value.String(),
+			CurrencyCode: transfer.Amount.CurrencyCode,
+		},
+		IdempotencyKey: transfer.IdempotencyKey,
+		Status:         string(domain.TransferStatusSuccess),
+		Timestamp:      formatTimestamp(executedAt),
+		Message:        transfer.Message,
+	}
+}
+
+// eventID derives the ID of the event of the given type for an operation.
+// The ID is the same every time the event is built, so redeliveries of an outbox message
+// carry the ID of the first attempt and consumers can deduplicate them.
+func eventID(operationID uuid.UUID, eventType string) string {
+	return uuid.NewSHA1(operationID, []byte(eventType)).String()
+}
+
+// formatTimestamp formats a time.Time to ISO 8601 with millisecond precision in UTC.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/domain"
+)
+
+// TestNewTransferCompletedEvent_MatchesSchema verifies the event payload
+// follows services/common/analytics-service-kafka-spec/schemas/transfer_event.json
+func TestNewTransferCompletedEvent_MatchesSchema(t *testing.T) {
+	completedAt := time.Date(2025, 11, 8, 14, 30, 0, 0, time.UTC)
+	transfer := &domain.Transfer{
+		ID:             uuid.New(),
+		SenderID:       uuid.New(),
+		RecipientID:    uuid.New(),
+		Amount:         domain.Amount{Value: domain.NewMoney(15050), CurrencyCode: "RUB"},
+		IdempotencyKey: uuid.New().String(),
+		Status:         domain.TransferStatusSuccess,
+		Message:        "Transfer completed successfully",
+		CreatedAt:      completedAt.Add(-time.Second),
+		CompletedAt:    &completedAt,
+	}
+
+	body, err := json.Marshal(NewTransferCompletedEvent(transfer))
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to unmarshal event: %v", err)
+	}
+
+	required := []string{
+		"eventId", "eventType", "eventTimestamp", "operationId", "senderId",
+		"recipientId", "amount", "idempotencyKey", "status", "timestamp",
+	}
+	for _, field := range required {
+		if _, ok := payload[field]; !ok {
+			t.Errorf("required field %q is missing", field)
+		}
+	}
+
+	if payload["eventType"] != "transfer.completed" {
+		t.Errorf("expected eventType transfer.completed, got %v", payload["eventType"])
+	}
+	if payload["status"] != "SUCCESS" {
+		t.Errorf("expected status SUCCESS, got %v", payload["status"])
+	}
+	if payload["operationId"] != transfer.ID.String() {
+		t.Errorf("expected operationId %s, got %v", transfer.ID, payload["operationId"])
+	}
+	if payload["timestamp"] != "2025-11-08T14:30:00.000Z" {
+		t.Errorf("expected timestamp 2025-11-08T14:30:00.000Z, got %v", payload["timestamp"])
+	}
+	if _, err := uuid.Parse(payload["eventId"].(string)); err != nil {
+		t.Errorf("eventId is not a UUID: %v", err)
+	}
+	if again := NewTransferCompletedEvent(transfer); again.EventID != payload["eventId"] {
+		t.Errorf("expected the event of the same transfer to keep eventId %v, got %s", payload["eventId"], again.EventID)
+	}
+	if _, err := time.Parse(time.RFC3339, payload["eventTimestamp"].(string)); err != nil {
+		t.Errorf("eventTimestamp is not ISO 8601: %v", err)
+	}
+
+	amount, ok := payload["amount"].(map[string]interface{})
+	if !ok {
+		t.Fatal("amount is not an object")
+	}
+	if amount["value"] != "150.50" {
+		t.Errorf("expected amount value 150.50, got %v", amount["value"])
+	}
+	if amount["currencyCode"] != "RUB" {
+		t.Errorf("expected currencyCode RUB, got %v", amount["currencyCode"])
+	}
+}
@@ -2,8 +2,10 @@ package grpc
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,16 +16,26 @@ import (
 	pb "github.com/spbu-ds-practicum-2025/example-project/services/bank-service/proto/bank.v1"
 )
 
+const (
+	// defaultPageSize is used by list RPCs when the request does not specify a page size
+	defaultPageSize = 50
+
+	// maxPageSize is the upper bound for page sizes; larger requests are clamped
+	maxPageSize = 100
+)
+
 // BankServiceServer implements the BankService gRPC service.
 type BankServiceServer struct {
 	pb.UnimplementedBankServiceServer
 	transferService *domain.TransferService
+	accountService  *domain.AccountService
 }
 
 // NewBankServiceServer creates a new BankServiceServer.
-func NewBankServiceServer(transferService *domain.TransferService) *BankServiceServer {
+func NewBankServiceServer(transferService *domain.TransferService, accountService *domain.AccountService) *BankServiceServer {
 	return &BankServiceServer{
 		transferService: transferService,
+		accountService:  accountService,
 	}
 }
 
@@ -47,8 +59,12 @@ func (s *BankServiceServer) TransferMoney(ctx context.Context, req *pb.TransferM
 	}
 
 	// Convert proto Amount to domain Amount
+	value, err := domain.ParseMoney(req.Amount.Value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid amount.value: %v", err)
+	}
 	amount := domain.Amount{
-		Value:        req.Amount.Value,
+		Value:        value,
 		CurrencyCode: req.Amount.CurrencyCode,
 	}
 
@@ -66,12 +82,13 @@ func (s *BankServiceServer) TransferMoney(ctx context.Context, req *pb.TransferM
 		return nil, mapDomainErrorToGRPC(err)
 	}
 
-	// Build response
+	// Build response; declined transfers are returned as FAILED with their reason
 	response := &pb.TransferMoneyResponse{
-		OperationId: transfer.ID.String(),
-		Status:      mapDomainStatusToProto(transfer.Status),
-		Message:     transfer.Message,
-		Timestamp:   formatTimestamp(transfer.CreatedAt),
+		OperationId:   transfer.ID.String(),
+		Status:        mapDomainStatusToProto(transfer.Status),
+		Message:       transfer.Message,
+		Timestamp:     formatTimestamp(transfer.CreatedAt),
+		FailureReason: mapDomainFailureReasonToProto(transfer.FailureReason),
 	}
 
 	// If transfer was completed, use completion timestamp
@@ -105,10 +122,12 @@ func (s *BankServiceServer) GetAccount(ctx context.Context, req *pb.GetAccountRe
 	response := &pb.GetAccountResponse{
 		AccountId: account.ID.String(),
 		Balance: &pb.Amount{
-			Value:        account.Balance.Value,
+			Value:        account.Balance.Value.String(),
 			CurrencyCode: account.Balance.CurrencyCode,
 		},
 		Timestamp: formatTimestamp(time.Now()),
+		Status:    mapDomainAccountStatusToProto(account.Status),
+		OwnerId:   account.OwnerID,
 	}
 
 	return response, nil
@@ -117,8 +136,251 @@ func (s *BankServiceServer) GetAccount(ctx context.Context, req *pb.GetAccountRe
 // TopUp adds funds to a specific account.
 // This operation is idempotent when called with the same idempotency key.
 func (s *BankServiceServer) TopUp(ctx context.Context, req *pb.TopUpRequest) (*pb.TopUpResponse, error) {
-	// TopUp is not yet implemented
-	return nil, status.Error(codes.Unimplemented, "TopUp operation is not yet implemented")
+	// Validate request
+	if err := validateTopUpRequest(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Parse UUID
+	accountID, err := uuid.Parse(req.AccountId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id: %v", err)
+	}
+
+	// Convert proto Amount to domain Amount
+	value, err := domain.ParseMoney(req.Amount.Value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid amount.value: %v", err)
+	}
+	amount := domain.Amount{
+		Value:        value,
+		CurrencyCode: req.Amount.CurrencyCode,
+	}
+
+	// Execute top-up using domain service
+	topUp, err := s.transferService.ExecuteTopUp(
+		ctx,
+		accountID,
+		amount,
+		req.IdempotencyKey,
+		req.Source,
+		req.ExternalTransactionId,
+	)
+
+	if err != nil {
+		// Map domain errors to gRPC status codes
+		return nil, mapDomainErrorToGRPC(err)
+	}
+
+	// Build response
+	response := &pb.TopUpResponse{
+		OperationId: topUp.ID.String(),
+		Status:      mapDomainTopUpStatusToProto(topUp.Status),
+		Message:     topUp.Message,
+		Timestamp:   formatTimestamp(topUp.CreatedAt),
+		NewBalance: &pb.Amount{
+			Value:        topUp.NewBalance.Value.String(),
+			CurrencyCode: topUp.NewBalance.CurrencyCode,
+		},
+		AccountId: topUp.AccountID.String(),
+		Amount: &pb.Amount{
+			Value:        topUp.Amount.Value.String(),
+			CurrencyCode: topUp.Amount.CurrencyCode,
+		},
+	}
+
+	// If top-up was completed, use completion timestamp
+	if topUp.CompletedAt != nil {
+		response.Timestamp = formatTimestamp(*topUp.CompletedAt)
+	}
+
+	return response, nil
+}
+
+// CreateAccount opens a new account for the given owner with a zero balance.
+func (s *BankServiceServer) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	// Validate request
+	if err := validateCreateAccountRequest(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	account, err := s.accountService.CreateAccount(ctx, req.OwnerId, req.OwnerName, req.CurrencyCode)
+	if err != nil {
+		return nil, mapDomainErrorToGRPC(err)
+	}
+
+	return &pb.CreateAccountResponse{
+		Account: mapDomainAccountToProto(account),
+	}, nil
+}
+
+// CloseAccount closes an account permanently.
+func (s *BankServiceServer) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	// Validate request
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	// Parse UUID
+	accountID, err := uuid.Parse(req.AccountId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id: %v", err)
+	}
+
+	account, err := s.accountService.CloseAccount(ctx, accountID)
+	if err != nil {
+		return nil, mapDomainErrorToGRPC(err)
+	}
+
+	return &pb.CloseAccountResponse{
+		Account: mapDomainAccountToProto(account),
+	}, nil
+}
+
+// ListAccounts returns a page of accounts ordered by creation time.
+func (s *BankServiceServer) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	// Validate request
+	if req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+
+	filter := domain.AccountFilter{
+		OwnerID: req.OwnerId,
+		Limit:   int(req.PageSize),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	if req.Status != pb.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED {
+		accountStatus, ok := mapProtoAccountStatusToDomain(req.Status)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid status: %v", req.Status)
+		}
+		filter.Status = accountStatus
+	}
+
+	if req.PageToken != "" {
+		createdAt, id, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token: %v", err)
+		}
+		filter.AfterCreatedAt = createdAt
+		filter.AfterID = id
+	}
+
+	accounts, hasMore, err := s.accountService.ListAccounts(ctx, filter)
+	if err != nil {
+		return nil, mapDomainErrorToGRPC(err)
+	}
+
+	response := &pb.ListAccountsResponse{
+		Accounts: make([]*pb.Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, mapDomainAccountToProto(account))
+	}
+	if hasMore && len(accounts) > 0 {
+		last := accounts[len(accounts)-1]
+		response.NextPageToken = encodePageToken(last.CreatedAt, last.ID)
+	}
+
+	return response, nil
+}
+
+// GetTransfer retrieves a transfer by its operation ID or idempotency key.
+func (s *BankServiceServer) GetTransfer(ctx context.Context, req *pb.GetTransferRequest) (*pb.GetTransferResponse, error) {
+	// Validate request
+	if (req.OperationId == "") == (req.IdempotencyKey == "") {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of operation_id and idempotency_key is required")
+	}
+
+	var transfer *domain.Transfer
+	if req.OperationId != "" {
+		operationID, err := uuid.Parse(req.OperationId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid operation_id: %v", err)
+		}
+		transfer, err = s.transferService.GetTransfer(ctx, operationID)
+		if err != nil {
+			return nil, mapDomainErrorToGRPC(err)
+		}
+	} else {
+		var err error
+		transfer, err = s.transferService.GetTransferByIdempotencyKey(ctx, req.IdempotencyKey)
+		if err != nil {
+			return nil, mapDomainErrorToGRPC(err)
+		}
+	}
+
+	return &pb.GetTransferResponse{
+		Transfer: mapDomainTransferToProto(transfer),
+	}, nil
+}
+
+// ListTransfers returns a page of the transfers of an account, most recent first.
+func (s *BankServiceServer) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	// Validate request
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+	if req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+
+	accountID, err := uuid.Parse(req.AccountId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id: %v", err)
+	}
+
+	filter := domain.TransferFilter{
+		AccountID: accountID,
+		Limit:     int(req.PageSize),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	if req.Status != pb.TransferStatus_TRANSFER_STATUS_UNSPECIFIED {
+		transferStatus, ok := mapProtoStatusToDomain(req.Status)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid status: %v", req.Status)
+		}
+		filter.Status = transferStatus
+	}
+
+	if req.PageToken != "" {
+		createdAt, id, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token: %v", err)
+		}
+		filter.BeforeCreatedAt = createdAt
+		filter.BeforeID = id
+	}
+
+	transfers, hasMore, err := s.transferService.ListTransfers(ctx, filter)
+	if err != nil {
+		return nil, mapDomainErrorToGRPC(err)
+	}
+
+	response := &pb.ListTransfersResponse{
+		Transfers: make([]*pb.Transfer, 0, len(transfers)),
+	}
+	for _, transfer := range transfers {
+		response.Transfers = append(response.Transfers, mapDomainTransferToProto(transfer))
+	}
+	if hasMore && len(transfers) > 0 {
+		last := transfers[len(transfers)-1]
+		response.NextPageToken = encodePageToken(last.CreatedAt, last.ID)
+	}
+
+	return response, nil
 }
 
 // validateTransferMoneyRequest validates the TransferMoneyRequest.
@@ -144,6 +406,40 @@ func validateTransferMoneyRequest(req *pb.TransferMoneyRequest) error {
 	return nil
 }
 
+// validateTopUpRequest validates the TopUpRequest.
+func validateTopUpRequest(req *pb.TopUpRequest) error {
+	if req.AccountId == "" {
+		return fmt.Errorf("account_id is required")
+	}
+	if req.Amount == nil {
+		return fmt.Errorf("amount is required")
+	}
+	if req.Amount.Value == "" {
+		return fmt.Errorf("amount.value is required")
+	}
+	if req.Amount.CurrencyCode == "" {
+		return fmt.Errorf("amount.currency_code is required")
+	}
+	if req.IdempotencyKey == "" {
+		return fmt.Errorf("idempotency_key is required")
+	}
+	return nil
+}
+
+// validateCreateAccountRequest validates the CreateAccountRequest.
+func validateCreateAccountRequest(req *pb.CreateAccountRequest) error {
+	if req.OwnerId == "" {
+		return fmt.Errorf("owner_id is required")
+	}
+	if req.CurrencyCode == "" {
+		return fmt.Errorf("currency_code is required")
+	}
+	if err := domain.ValidateCurrencyCode(req.CurrencyCode); err != nil {
+		return fmt.Errorf("invalid currency_code: %w", err)
+	}
+	return nil
+}
+
 // mapDomainErrorToGRPC maps domain errors to gRPC status codes.
 func mapDomainErrorToGRPC(err error) error {
 	if err == nil {
@@ -162,6 +458,24 @@ func mapDomainErrorToGRPC(err error) error {
 		return status.Error(codes.InvalidArgument, "sender and recipient must be different")
 	case errors.Is(err, domain.ErrCurrencyMismatch):
 		return status.Error(codes.InvalidArgument, "currency mismatch")
+	case errors.Is(err, domain.ErrAccountNotActive):
+		return status.Error(codes.FailedPrecondition, "account is not active")
+	case errors.Is(err, domain.ErrMoneyOverflow):
+		return status.Error(codes.FailedPrecondition, "resulting balance exceeds the supported maximum")
+	case errors.Is(err, domain.ErrAccountClosed):
+		return status.Error(codes.FailedPrecondition, "account is already closed")
+	case errors.Is(err, domain.ErrAccountHasBalance):
+		return status.Error(codes.FailedPrecondition, "account balance must be zero to close the account")
+	case errors.Is(err, domain.ErrTransferNotFound):
+		return status.Error(codes.NotFound, "transfer not found")
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return status.Error(codes.AlreadyExists, "idempotency key was already used for a different operation")
+	case errors.Is(err, domain.ErrDuplicateIdempotencyKey):
+		return status.Error(codes.AlreadyExists, "operation with this idempotency key already exists")
+	case errors.Is(err, domain.ErrSerializationFailure):
+		return status.Error(codes.Aborted, "transaction aborted due to a concurrent update, retry the request")
+	case errors.Is(err, domain.ErrConstraintViolation):
+		return status.Error(codes.FailedPrecondition, "change violates a data constraint")
 	default:
 		// Generic internal error
 		return status.Errorf(codes.Internal, "internal error: %v", err)
@@ -174,14 +488,157 @@ func mapDomainStatusToProto(domainStatus domain.TransferStatus) pb.TransferStatu
 	case domain.TransferStatusSuccess:
 		return pb.TransferStatus_TRANSFER_STATUS_SUCCESS
 	case domain.TransferStatusFailed:
-		return pb.TransferStatus_TRANSFER_STATUS_UNSPECIFIED // Failed maps to unspecified in proto
+		return pb.TransferStatus_TRANSFER_STATUS_FAILED
 	case domain.TransferStatusPending:
-		return pb.TransferStatus_TRANSFER_STATUS_UNSPECIFIED // Pending maps to unspecified in proto
+		return pb.TransferStatus_TRANSFER_STATUS_PENDING
 	default:
 		return pb.TransferStatus_TRANSFER_STATUS_UNSPECIFIED
 	}
 }
 
+// mapDomainFailureReasonToProto maps domain transfer failure reason to proto failure reason.
+func mapDomainFailureReasonToProto(reason domain.TransferFailureReason) pb.TransferFailureReason {
+	switch reason {
+	case domain.TransferFailureInsufficientFunds:
+		return pb.TransferFailureReason_TRANSFER_FAILURE_REASON_INSUFFICIENT_FUNDS
+	case domain.TransferFailureAccountNotActive:
+		return pb.TransferFailureReason_TRANSFER_FAILURE_REASON_ACCOUNT_NOT_ACTIVE
+	case domain.TransferFailureCurrencyMismatch:
+		return pb.TransferFailureReason_TRANSFER_FAILURE_REASON_CURRENCY_MISMATCH
+	case domain.TransferFailureInternal:
+		return pb.TransferFailureReason_TRANSFER_FAILURE_REASON_INTERNAL
+	default:
+		return pb.TransferFailureReason_TRANSFER_FAILURE_REASON_UNSPECIFIED
+	}
+}
+
+// mapProtoStatusToDomain maps proto transfer status to domain status.
+// Returns false for unspecified or unknown values.
+func mapProtoStatusToDomain(protoStatus pb.TransferStatus) (domain.TransferStatus, bool) {
+	switch protoStatus {
+	case pb.TransferStatus_TRANSFER_STATUS_SUCCESS:
+		return domain.TransferStatusSuccess, true
+	case pb.TransferStatus_TRANSFER_STATUS_PENDING:
+		return domain.TransferStatusPending, true
+	case pb.TransferStatus_TRANSFER_STATUS_FAILED:
+		return domain.TransferStatusFailed, true
+	default:
+		return "", false
+	}
+}
+
+// mapDomainTransferToProto maps a domain transfer to the proto Transfer message.
+func mapDomainTransferToProto(transfer *domain.Transfer) *pb.Transfer {
+	result := &pb.Transfer{
+		OperationId: transfer.ID.String(),
+		SenderId:    transfer.SenderID.String(),
+		RecipientId: transfer.RecipientID.String(),
+		Amount: &pb.Amount{
+			Value:        transfer.Amount.Value.String(),
+			CurrencyCode: transfer.Amount.CurrencyCode,
+		},
+		IdempotencyKey: transfer.IdempotencyKey,
+		Status:         mapDomainStatusToProto(transfer.Status),
+		Message:        transfer.Message,
+		CreatedAt:      formatTimestamp(transfer.CreatedAt),
+		FailureReason:  mapDomainFailureReasonToProto(transfer.FailureReason),
+	}
+	if transfer.CompletedAt != nil {
+		result.CompletedAt = formatTimestamp(*transfer.CompletedAt)
+	}
+	return result
+}
+
+// mapDomainTopUpStatusToProto maps domain top-up status to proto status.
+func mapDomainTopUpStatusToProto(domainStatus domain.TopUpStatus) pb.TopUpStatus {
+	switch domainStatus {
+	case domain.TopUpStatusSuccess:
+		return pb.TopUpStatus_TOP_UP_STATUS_SUCCESS
+	default:
+		return pb.TopUpStatus_TOP_UP_STATUS_UNSPECIFIED
+	}
+}
+
+// mapDomainAccountStatusToProto maps domain account status to proto status.
+func mapDomainAccountStatusToProto(domainStatus domain.AccountStatus) pb.AccountStatus {
+	switch domainStatus {
+	case domain.AccountStatusActive:
+		return pb.AccountStatus_ACCOUNT_STATUS_ACTIVE
+	case domain.AccountStatusFrozen:
+		return pb.AccountStatus_ACCOUNT_STATUS_FROZEN
+	case domain.AccountStatusClosed:
+		return pb.AccountStatus_ACCOUNT_STATUS_CLOSED
+	default:
+		return pb.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+	}
+}
+
+// mapProtoAccountStatusToDomain maps proto account status to domain status.
+// Returns false for unspecified or unknown values.
+func mapProtoAccountStatusToDomain(protoStatus pb.AccountStatus) (domain.AccountStatus, bool) {
+	switch protoStatus {
+	case pb.AccountStatus_ACCOUNT_STATUS_ACTIVE:
+		return domain.AccountStatusActive, true
+	case pb.AccountStatus_ACCOUNT_STATUS_FROZEN:
+		return domain.AccountStatusFrozen, true
+	case pb.AccountStatus_ACCOUNT_STATUS_CLOSED:
+		return domain.AccountStatusClosed, true
+	default:
+		return "", false
+	}
+}
+
+// mapDomainAccountToProto maps a domain account to the proto Account message.
+func mapDomainAccountToProto(account *domain.Account) *pb.Account {
+	result := &pb.Account{
+		AccountId: account.ID.String(),
+		OwnerId:   account.OwnerID,
+		OwnerName: account.OwnerName,
+		Balance: &pb.Amount{
+			Value:        account.Balance.Value.String(),
+			CurrencyCode: account.Balance.CurrencyCode,
+		},
+		Status:    mapDomainAccountStatusToProto(account.Status),
+		CreatedAt: formatTimestamp(account.CreatedAt),
+		UpdatedAt: formatTimestamp(account.UpdatedAt),
+	}
+	if account.ClosedAt != nil {
+		result.ClosedAt = formatTimestamp(*account.ClosedAt)
+	}
+	return result
+}
+
+// encodePageToken encodes a keyset pagination cursor into an opaque page token.
+func encodePageToken(createdAt time.Time, id uuid.UUID) string {
+	cursor := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+// decodePageToken decodes a page token produced by encodePageToken.
+func decodePageToken(token string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed token")
+	}
+
+	timestamp, rawID, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed token")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed token timestamp")
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed token id")
+	}
+
+	return createdAt, id, nil
+}
+
 // formatTimestamp formats a time.Time to ISO 8601 format.
 func formatTimestamp(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
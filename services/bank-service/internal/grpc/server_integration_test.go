@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,7 +14,9 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db"
@@ -70,7 +73,8 @@ func TestTransferMoneyIntegration(t *testing.T) {
 	// Initialize RabbitMQ publisher
 	exchange := "bank.operations"
 	routingKey := "bank.operations.transfer.completed"
-	publisher, err := events.NewRabbitMQPublisher(rabbitURL, exchange, routingKey)
+	topUpRoutingKey := "bank.operations.topup.completed"
+	publisher, err := events.NewRabbitMQPublisher(rabbitURL, exchange, routingKey, topUpRoutingKey)
 	if err != nil {
 		t.Fatalf("failed to create rabbitmq publisher: %v", err)
 	}
@@ -79,9 +83,20 @@ func TestTransferMoneyIntegration(t *testing.T) {
 	// Create domain service and gRPC server
 	accountRepo := db.NewAccountRepository(pool.Pool)
 	transferRepo := db.NewTransferRepository(pool.Pool)
+	topUpRepo := db.NewTopUpRepository(pool.Pool)
+	outboxRepo := db.NewOutboxRepository(pool.Pool)
 	txManager := db.NewTransactionManager(pool.Pool)
-	transferService := domain.NewTransferService(accountRepo, transferRepo, txManager, publisher)
-	bankServer := grpcserver.NewBankServiceServer(transferService)
+	transferService := domain.NewTransferService(accountRepo, transferRepo, topUpRepo, outboxRepo, txManager)
+	accountService := domain.NewAccountService(accountRepo, txManager)
+	bankServer := grpcserver.NewBankServiceServer(transferService, accountService)
+
+	// Start outbox relay to publish recorded events
+	relayConfig := domain.DefaultOutboxRelayConfig()
+	relayConfig.PollInterval = 100 * time.Millisecond
+	relay := domain.NewOutboxRelay(outboxRepo, transferRepo, topUpRepo, txManager, publisher, relayConfig)
+	relayCtx, stopRelay := context.WithCancel(ctx)
+	defer stopRelay()
+	go relay.Run(relayCtx)
 
 	// Start in-memory gRPC server using bufconn
 	lis := bufconn.Listen(bufSize)
@@ -115,6 +130,10 @@ func TestTransferMoneyIntegration(t *testing.T) {
 	stopConsumer := startEventConsumer(t, ctx, rabbitURL, exchange, routingKey, eventChan)
 	defer stopConsumer()
 
+	topUpEventChan := make(chan map[string]interface{}, 1)
+	stopTopUpConsumer := startEventConsumer(t, ctx, rabbitURL, exchange, topUpRoutingKey, topUpEventChan)
+	defer stopTopUpConsumer()
+
 	// Give consumer a moment to start
 	time.Sleep(500 * time.Millisecond)
 
@@ -210,6 +229,293 @@ func TestTransferMoneyIntegration(t *testing.T) {
 	if senderResp2.Balance.Value != "899.50" {
 		t.Errorf("sender balance changed on idempotent call: %s", senderResp2.Balance.Value)
 	}
+
+	// Reusing the idempotency key for a different transfer is rejected
+	_, err = client.TransferMoney(ctx, &pb.TransferMoneyRequest{
+		SenderId:       senderID.String(),
+		RecipientId:    recipientID.String(),
+		Amount:         &pb.Amount{Value: "200.00", CurrencyCode: "RUB"},
+		IdempotencyKey: idempotencyKey,
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists for reused idempotency key, got %v", err)
+	}
+
+	// The stored transfer can be looked up by operation ID and by idempotency key
+	getResp, err := client.GetTransfer(ctx, &pb.GetTransferRequest{OperationId: resp.OperationId})
+	if err != nil {
+		t.Fatalf("GetTransfer by operation_id failed: %v", err)
+	}
+	if getResp.Transfer.Status != pb.TransferStatus_TRANSFER_STATUS_SUCCESS ||
+		getResp.Transfer.SenderId != senderID.String() ||
+		getResp.Transfer.RecipientId != recipientID.String() ||
+		getResp.Transfer.Amount.Value != "100.50" ||
+		getResp.Transfer.CompletedAt == "" {
+		t.Errorf("unexpected transfer: %v", getResp.Transfer)
+	}
+
+	getResp, err = client.GetTransfer(ctx, &pb.GetTransferRequest{IdempotencyKey: idempotencyKey})
+	if err != nil {
+		t.Fatalf("GetTransfer by idempotency_key failed: %v", err)
+	}
+	if getResp.Transfer.OperationId != resp.OperationId {
+		t.Errorf("expected operation_id %s, got %s", resp.OperationId, getResp.Transfer.OperationId)
+	}
+
+	_, err = client.GetTransfer(ctx, &pb.GetTransferRequest{OperationId: uuid.New().String()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for unknown transfer, got %v", err)
+	}
+
+	// Transfer history is paginated most recent first and includes both directions
+	backResp, err := client.TransferMoney(ctx, &pb.TransferMoneyRequest{
+		SenderId:       recipientID.String(),
+		RecipientId:    senderID.String(),
+		Amount:         &pb.Amount{Value: "0.50", CurrencyCode: "RUB"},
+		IdempotencyKey: uuid.New().String(),
+	})
+	if err != nil {
+		t.Fatalf("TransferMoney back failed: %v", err)
+	}
+	select {
+	case <-eventChan:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for event to be published")
+	}
+
+	firstPage, err := client.ListTransfers(ctx, &pb.ListTransfersRequest{AccountId: senderID.String(), PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListTransfers failed: %v", err)
+	}
+	if len(firstPage.Transfers) != 1 || firstPage.Transfers[0].OperationId != backResp.OperationId {
+		t.Errorf("expected the most recent transfer %s first, got %v", backResp.OperationId, firstPage.Transfers)
+	}
+	if firstPage.NextPageToken == "" {
+		t.Fatal("expected a next page token")
+	}
+
+	secondPage, err := client.ListTransfers(ctx, &pb.ListTransfersRequest{
+		AccountId: senderID.String(),
+		PageSize:  1,
+		PageToken: firstPage.NextPageToken,
+	})
+	if err != nil {
+		t.Fatalf("ListTransfers second page failed: %v", err)
+	}
+	if len(secondPage.Transfers) != 1 || secondPage.Transfers[0].OperationId != resp.OperationId {
+		t.Errorf("expected transfer %s on the second page, got %v", resp.OperationId, secondPage.Transfers)
+	}
+	if secondPage.NextPageToken != "" {
+		t.Errorf("expected no next page, got %q", secondPage.NextPageToken)
+	}
+
+	// Top up the sender account via gRPC
+	topUpReq := &pb.TopUpRequest{
+		AccountId:             senderID.String(),
+		Amount:                &pb.Amount{Value: "50.00", CurrencyCode: "RUB"},
+		IdempotencyKey:        uuid.New().String(),
+		Source:                "bank_card",
+		ExternalTransactionId: "ext-12345",
+	}
+
+	topUpResp, err := client.TopUp(ctx, topUpReq)
+	if err != nil {
+		t.Fatalf("TopUp failed: %v", err)
+	}
+	if topUpResp.Status != pb.TopUpStatus_TOP_UP_STATUS_SUCCESS {
+		t.Errorf("expected status SUCCESS, got %v", topUpResp.Status)
+	}
+	if topUpResp.NewBalance.Value != "950.00" {
+		t.Errorf("expected new balance 950.00, got %s", topUpResp.NewBalance.Value)
+	}
+
+	select {
+	case event := <-topUpEventChan:
+		if event["eventType"] != "topup.completed" {
+			t.Errorf("expected eventType 'topup.completed', got %v", event["eventType"])
+		}
+		if event["operationId"] != topUpResp.OperationId {
+			t.Errorf("expected operationId %s, got %v", topUpResp.OperationId, event["operationId"])
+		}
+		if event["accountId"] != senderID.String() {
+			t.Errorf("expected accountId %s, got %v", senderID.String(), event["accountId"])
+		}
+		if event["source"] != "bank_card" {
+			t.Errorf("expected source bank_card, got %v", event["source"])
+		}
+		if event["externalTransactionId"] != "ext-12345" {
+			t.Errorf("expected externalTransactionId ext-12345, got %v", event["externalTransactionId"])
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for top-up event to be published")
+	}
+
+	// Idempotent top-up returns the original result without crediting again
+	topUpResp2, err := client.TopUp(ctx, topUpReq)
+	if err != nil {
+		t.Fatalf("second TopUp call failed: %v", err)
+	}
+	if topUpResp2.OperationId != topUpResp.OperationId {
+		t.Errorf("idempotent call returned different operation_id: %s vs %s", topUpResp.OperationId, topUpResp2.OperationId)
+	}
+	if topUpResp2.NewBalance.Value != "950.00" {
+		t.Errorf("idempotent call returned different new balance: %s", topUpResp2.NewBalance.Value)
+	}
+
+	senderResp3, _ := client.GetAccount(ctx, &pb.GetAccountRequest{AccountId: senderID.String()})
+	if senderResp3.Balance.Value != "950.00" {
+		t.Errorf("sender balance changed on idempotent top-up: %s", senderResp3.Balance.Value)
+	}
+
+	// Reusing the idempotency key for a different top-up is rejected
+	_, err = client.TopUp(ctx, &pb.TopUpRequest{
+		AccountId:      senderID.String(),
+		Amount:         &pb.Amount{Value: "75.00", CurrencyCode: "RUB"},
+		IdempotencyKey: topUpReq.IdempotencyKey,
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists for reused top-up idempotency key, got %v", err)
+	}
+
+	// Open an account, list it, close it and verify it no longer accepts transfers
+	createResp, err := client.CreateAccount(ctx, &pb.CreateAccountRequest{
+		OwnerId:      "owner-1",
+		OwnerName:    "Test Owner",
+		CurrencyCode: "RUB",
+	})
+	if err != nil {
+		t.Fatalf("CreateAccount failed: %v", err)
+	}
+	newAccount := createResp.Account
+	if newAccount.Status != pb.AccountStatus_ACCOUNT_STATUS_ACTIVE || newAccount.Balance.Value != "0.00" {
+		t.Errorf("expected ACTIVE account with zero balance, got %v %s", newAccount.Status, newAccount.Balance.Value)
+	}
+
+	listResp, err := client.ListAccounts(ctx, &pb.ListAccountsRequest{OwnerId: "owner-1"})
+	if err != nil {
+		t.Fatalf("ListAccounts failed: %v", err)
+	}
+	if len(listResp.Accounts) != 1 || listResp.Accounts[0].AccountId != newAccount.AccountId {
+		t.Errorf("expected only the new account for owner-1, got %d accounts", len(listResp.Accounts))
+	}
+	if listResp.NextPageToken != "" {
+		t.Errorf("expected no next page, got %q", listResp.NextPageToken)
+	}
+
+	// Accounts holding funds cannot be closed
+	_, err = client.CloseAccount(ctx, &pb.CloseAccountRequest{AccountId: senderID.String()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition when closing a funded account, got %v", err)
+	}
+
+	closeResp, err := client.CloseAccount(ctx, &pb.CloseAccountRequest{AccountId: newAccount.AccountId})
+	if err != nil {
+		t.Fatalf("CloseAccount failed: %v", err)
+	}
+	if closeResp.Account.Status != pb.AccountStatus_ACCOUNT_STATUS_CLOSED || closeResp.Account.ClosedAt == "" {
+		t.Errorf("expected CLOSED account with closed_at, got %v %q", closeResp.Account.Status, closeResp.Account.ClosedAt)
+	}
+
+	// Declined transfers are returned as FAILED with a failure reason
+	closedResp, err := client.TransferMoney(ctx, &pb.TransferMoneyRequest{
+		SenderId:       senderID.String(),
+		RecipientId:    newAccount.AccountId,
+		Amount:         &pb.Amount{Value: "10.00", CurrencyCode: "RUB"},
+		IdempotencyKey: uuid.New().String(),
+	})
+	if err != nil {
+		t.Fatalf("TransferMoney to a closed account failed: %v", err)
+	}
+	if closedResp.Status != pb.TransferStatus_TRANSFER_STATUS_FAILED ||
+		closedResp.FailureReason != pb.TransferFailureReason_TRANSFER_FAILURE_REASON_ACCOUNT_NOT_ACTIVE {
+		t.Errorf("expected FAILED with ACCOUNT_NOT_ACTIVE, got %v %v", closedResp.Status, closedResp.FailureReason)
+	}
+
+	declinedReq := &pb.TransferMoneyRequest{
+		SenderId:       senderID.String(),
+		RecipientId:    recipientID.String(),
+		Amount:         &pb.Amount{Value: "100000.00", CurrencyCode: "RUB"},
+		IdempotencyKey: uuid.New().String(),
+	}
+	declinedResp, err := client.TransferMoney(ctx, declinedReq)
+	if err != nil {
+		t.Fatalf("TransferMoney with insufficient funds failed: %v", err)
+	}
+	if declinedResp.Status != pb.TransferStatus_TRANSFER_STATUS_FAILED ||
+		declinedResp.FailureReason != pb.TransferFailureReason_TRANSFER_FAILURE_REASON_INSUFFICIENT_FUNDS {
+		t.Errorf("expected FAILED with INSUFFICIENT_FUNDS, got %v %v", declinedResp.Status, declinedResp.FailureReason)
+	}
+
+	// Replaying a declined transfer returns the stored failure
+	replayResp, err := client.TransferMoney(ctx, declinedReq)
+	if err != nil {
+		t.Fatalf("replayed TransferMoney failed: %v", err)
+	}
+	if replayResp.OperationId != declinedResp.OperationId ||
+		replayResp.FailureReason != pb.TransferFailureReason_TRANSFER_FAILURE_REASON_INSUFFICIENT_FUNDS {
+		t.Errorf("expected stored failure %s, got %s %v", declinedResp.OperationId, replayResp.OperationId, replayResp.FailureReason)
+	}
+
+	storedResp, err := client.GetTransfer(ctx, &pb.GetTransferRequest{OperationId: declinedResp.OperationId})
+	if err != nil {
+		t.Fatalf("GetTransfer for declined transfer failed: %v", err)
+	}
+	if storedResp.Transfer.Status != pb.TransferStatus_TRANSFER_STATUS_FAILED ||
+		storedResp.Transfer.FailureReason != pb.TransferFailureReason_TRANSFER_FAILURE_REASON_INSUFFICIENT_FUNDS {
+		t.Errorf("expected stored FAILED transfer, got %v %v", storedResp.Transfer.Status, storedResp.Transfer.FailureReason)
+	}
+
+	// Balances are unchanged by declined transfers
+	senderResp4, err := client.GetAccount(ctx, &pb.GetAccountRequest{AccountId: senderID.String()})
+	if err != nil {
+		t.Fatalf("GetAccount for sender failed: %v", err)
+	}
+	if senderResp4.Balance.Value != "950.00" {
+		t.Errorf("sender balance changed by declined transfers: %s", senderResp4.Balance.Value)
+	}
+
+	// Concurrent requests with the same idempotency key debit the sender exactly once
+	concurrentReq := &pb.TransferMoneyRequest{
+		SenderId:       senderID.String(),
+		RecipientId:    recipientID.String(),
+		Amount:         &pb.Amount{Value: "10.00", CurrencyCode: "RUB"},
+		IdempotencyKey: uuid.New().String(),
+	}
+	const concurrency = 10
+	operationIDs := make([]string, concurrency)
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.TransferMoney(ctx, concurrentReq)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			operationIDs[i] = resp.OperationId
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < concurrency; i++ {
+		if errs[i] != nil {
+			t.Fatalf("concurrent TransferMoney %d failed: %v", i, errs[i])
+		}
+		if operationIDs[i] != operationIDs[0] {
+			t.Errorf("concurrent calls returned different operation_ids: %s vs %s", operationIDs[0], operationIDs[i])
+		}
+	}
+
+	senderResp5, err := client.GetAccount(ctx, &pb.GetAccountRequest{AccountId: senderID.String()})
+	if err != nil {
+		t.Fatalf("GetAccount for sender failed: %v", err)
+	}
+	if senderResp5.Balance.Value != "940.00" {
+		t.Errorf("expected sender balance 940.00 after one debit, got %s", senderResp5.Balance.Value)
+	}
 }
 
 // startPostgresContainer starts a PostgreSQL testcontainer and returns the connection URL.
@@ -323,6 +629,46 @@ func runMigrations(t *testing.T, ctx context.Context, pool *db.Pool) {
 			BEFORE UPDATE ON accounts
 			FOR EACH ROW
 			EXECUTE FUNCTION update_updated_at_column();`,
+		// 005_create_outbox_table.up.sql
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id BIGSERIAL PRIMARY KEY,
+			event_type VARCHAR(50) NOT NULL,
+			aggregate_id UUID NOT NULL,
+			account_ids UUID[] NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			dispatched_at TIMESTAMP WITH TIME ZONE
+		);
+		CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE dispatched_at IS NULL;`,
+		// 006_create_topups_table.up.sql
+		`CREATE TABLE IF NOT EXISTS topups (
+			id UUID PRIMARY KEY,
+			account_id UUID NOT NULL REFERENCES accounts(id),
+			amount_value NUMERIC(15, 2) NOT NULL,
+			amount_currency_code VARCHAR(3) NOT NULL,
+			idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+			source VARCHAR(50),
+			external_transaction_id VARCHAR(255),
+			status VARCHAR(20) NOT NULL,
+			message TEXT,
+			new_balance_value NUMERIC(15, 2) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			completed_at TIMESTAMP WITH TIME ZONE
+		);`,
+		// 007_add_account_lifecycle.up.sql
+		`ALTER TABLE accounts ADD COLUMN owner_id VARCHAR(255);
+		ALTER TABLE accounts ADD COLUMN owner_name VARCHAR(255);
+		ALTER TABLE accounts ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE';
+		ALTER TABLE accounts ADD COLUMN closed_at TIMESTAMP WITH TIME ZONE;`,
+		// 008_add_transfer_failure_reason.up.sql
+		`ALTER TABLE transfers ADD COLUMN failure_reason VARCHAR(30)
+			CHECK (failure_reason IN ('INSUFFICIENT_FUNDS', 'ACCOUNT_NOT_ACTIVE', 'CURRENCY_MISMATCH', 'INTERNAL'));
+		ALTER TABLE transfers ADD CONSTRAINT chk_transfers_failure_reason
+			CHECK ((status = 'FAILED') = (failure_reason IS NOT NULL));`,
+		// 009_add_outbox_claims.up.sql
+		`ALTER TABLE outbox ADD COLUMN claimed_until TIMESTAMP WITH TIME ZONE;`,
 	}
 
 	for i, migration := range migrations {
@@ -2,6 +2,8 @@ package grpc_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -78,7 +80,7 @@ func TestTransferMoney_ValidationErrors(t *testing.T) {
 			// Create server - validation errors happen before calling the service
 			// so we don't need a fully working service for these tests
 			transferService := &domain.TransferService{}
-			server := grpcserver.NewBankServiceServer(transferService)
+			server := grpcserver.NewBankServiceServer(transferService, &domain.AccountService{})
 
 			_, err := server.TransferMoney(context.Background(), tt.request)
 			if err == nil {
@@ -129,6 +131,31 @@ func TestTransferMoney_DomainErrors(t *testing.T) {
 			domainError:  domain.ErrCurrencyMismatch,
 			expectedCode: codes.InvalidArgument,
 		},
+		{
+			name:         "balance overflow",
+			domainError:  fmt.Errorf("failed to credit account: %w", domain.ErrMoneyOverflow),
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "idempotency key reused",
+			domainError:  domain.ErrIdempotencyKeyReused,
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "duplicate idempotency key",
+			domainError:  domain.ErrDuplicateIdempotencyKey,
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "serialization failure",
+			domainError:  domain.ErrSerializationFailure,
+			expectedCode: codes.Aborted,
+		},
+		{
+			name:         "constraint violation",
+			domainError:  domain.ErrConstraintViolation,
+			expectedCode: codes.FailedPrecondition,
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,7 +172,7 @@ func TestTransferMoney_DomainErrors(t *testing.T) {
 // TestGetAccount_Validation tests GetAccount request validation
 func TestGetAccount_Validation(t *testing.T) {
 	transferService := &domain.TransferService{}
-	server := grpcserver.NewBankServiceServer(transferService)
+	server := grpcserver.NewBankServiceServer(transferService, &domain.AccountService{})
 
 	// Test empty account_id
 	_, err := server.GetAccount(context.Background(), &pb.GetAccountRequest{})
@@ -178,27 +205,265 @@ func TestGetAccount_Validation(t *testing.T) {
 	}
 }
 
-// TestTopUp_Unimplemented tests that TopUp returns unimplemented
-func TestTopUp_Unimplemented(t *testing.T) {
-	transferService := &domain.TransferService{}
-	server := grpcserver.NewBankServiceServer(transferService)
+// TestTopUp_ValidationErrors tests TopUp request validation
+func TestTopUp_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *pb.TopUpRequest
+		expectedErr codes.Code
+		errContains string
+	}{
+		{
+			name:        "missing account_id",
+			request:     &pb.TopUpRequest{Amount: &pb.Amount{Value: "100", CurrencyCode: "RUB"}, IdempotencyKey: "key1"},
+			expectedErr: codes.InvalidArgument,
+			errContains: "account_id is required",
+		},
+		{
+			name:        "missing amount",
+			request:     &pb.TopUpRequest{AccountId: uuid.New().String(), IdempotencyKey: "key1"},
+			expectedErr: codes.InvalidArgument,
+			errContains: "amount is required",
+		},
+		{
+			name:        "missing currency_code",
+			request:     &pb.TopUpRequest{AccountId: uuid.New().String(), Amount: &pb.Amount{Value: "100"}, IdempotencyKey: "key1"},
+			expectedErr: codes.InvalidArgument,
+			errContains: "amount.currency_code is required",
+		},
+		{
+			name:        "missing idempotency_key",
+			request:     &pb.TopUpRequest{AccountId: uuid.New().String(), Amount: &pb.Amount{Value: "100", CurrencyCode: "RUB"}},
+			expectedErr: codes.InvalidArgument,
+			errContains: "idempotency_key is required",
+		},
+		{
+			name:        "invalid account_id format",
+			request:     &pb.TopUpRequest{AccountId: "invalid-uuid", Amount: &pb.Amount{Value: "100", CurrencyCode: "RUB"}, IdempotencyKey: "key1"},
+			expectedErr: codes.InvalidArgument,
+			errContains: "invalid account_id",
+		},
+		{
+			name:        "non-positive amount",
+			request:     &pb.TopUpRequest{AccountId: uuid.New().String(), Amount: &pb.Amount{Value: "0.00", CurrencyCode: "RUB"}, IdempotencyKey: "key1"},
+			expectedErr: codes.InvalidArgument,
+			errContains: "invalid amount",
+		},
+	}
 
-	_, err := server.TopUp(context.Background(), &pb.TopUpRequest{
-		AccountId:      uuid.New().String(),
-		Amount:         &pb.Amount{Value: "100", CurrencyCode: "RUB"},
-		IdempotencyKey: "key1",
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Validation errors happen before any repository is used
+			transferService := &domain.TransferService{}
+			server := grpcserver.NewBankServiceServer(transferService, &domain.AccountService{})
 
-	if err == nil {
-		t.Fatal("expected unimplemented error")
+			_, err := server.TopUp(context.Background(), tt.request)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("expected gRPC status error, got: %v", err)
+			}
+
+			if st.Code() != tt.expectedErr {
+				t.Errorf("expected error code %v, got %v", tt.expectedErr, st.Code())
+			}
+			if !strings.Contains(st.Message(), tt.errContains) {
+				t.Errorf("expected error message to contain %q, got %q", tt.errContains, st.Message())
+			}
+		})
 	}
+}
 
-	st, ok := status.FromError(err)
-	if !ok {
-		t.Fatalf("expected gRPC status error, got: %v", err)
+// TestAccountLifecycle_ValidationErrors tests CreateAccount, CloseAccount and ListAccounts request validation
+func TestAccountLifecycle_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		call        func(server *grpcserver.BankServiceServer) error
+		errContains string
+	}{
+		{
+			name: "create: missing owner_id",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.CreateAccount(context.Background(), &pb.CreateAccountRequest{CurrencyCode: "RUB"})
+				return err
+			},
+			errContains: "owner_id is required",
+		},
+		{
+			name: "create: missing currency_code",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.CreateAccount(context.Background(), &pb.CreateAccountRequest{OwnerId: "owner-1"})
+				return err
+			},
+			errContains: "currency_code is required",
+		},
+		{
+			name: "create: invalid currency_code",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.CreateAccount(context.Background(), &pb.CreateAccountRequest{OwnerId: "owner-1", CurrencyCode: "rub"})
+				return err
+			},
+			errContains: "invalid currency_code",
+		},
+		{
+			name: "close: missing account_id",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.CloseAccount(context.Background(), &pb.CloseAccountRequest{})
+				return err
+			},
+			errContains: "account_id is required",
+		},
+		{
+			name: "close: invalid account_id format",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.CloseAccount(context.Background(), &pb.CloseAccountRequest{AccountId: "invalid-uuid"})
+				return err
+			},
+			errContains: "invalid account_id",
+		},
+		{
+			name: "list: negative page_size",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.ListAccounts(context.Background(), &pb.ListAccountsRequest{PageSize: -1})
+				return err
+			},
+			errContains: "page_size must not be negative",
+		},
+		{
+			name: "list: malformed page_token",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.ListAccounts(context.Background(), &pb.ListAccountsRequest{PageToken: "not a token"})
+				return err
+			},
+			errContains: "invalid page_token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Validation errors happen before any repository is used
+			server := grpcserver.NewBankServiceServer(&domain.TransferService{}, &domain.AccountService{})
+
+			err := tt.call(server)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("expected gRPC status error, got: %v", err)
+			}
+
+			if st.Code() != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", st.Code())
+			}
+			if !strings.Contains(st.Message(), tt.errContains) {
+				t.Errorf("expected error message to contain %q, got %q", tt.errContains, st.Message())
+			}
+		})
+	}
+}
+
+// TestTransferQueries_ValidationErrors tests request validation of the transfer lookup RPCs
+func TestTransferQueries_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		call        func(server *grpcserver.BankServiceServer) error
+		errContains string
+	}{
+		{
+			name: "get: no lookup key",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.GetTransfer(context.Background(), &pb.GetTransferRequest{})
+				return err
+			},
+			errContains: "exactly one of operation_id and idempotency_key is required",
+		},
+		{
+			name: "get: both lookup keys",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.GetTransfer(context.Background(), &pb.GetTransferRequest{
+					OperationId:    uuid.New().String(),
+					IdempotencyKey: "key-1",
+				})
+				return err
+			},
+			errContains: "exactly one of operation_id and idempotency_key is required",
+		},
+		{
+			name: "get: invalid operation_id format",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.GetTransfer(context.Background(), &pb.GetTransferRequest{OperationId: "invalid-uuid"})
+				return err
+			},
+			errContains: "invalid operation_id",
+		},
+		{
+			name: "list: missing account_id",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.ListTransfers(context.Background(), &pb.ListTransfersRequest{})
+				return err
+			},
+			errContains: "account_id is required",
+		},
+		{
+			name: "list: invalid account_id format",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.ListTransfers(context.Background(), &pb.ListTransfersRequest{AccountId: "invalid-uuid"})
+				return err
+			},
+			errContains: "invalid account_id",
+		},
+		{
+			name: "list: negative page_size",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.ListTransfers(context.Background(), &pb.ListTransfersRequest{AccountId: uuid.New().String(), PageSize: -1})
+				return err
+			},
+			errContains: "page_size must not be negative",
+		},
+		{
+			name: "list: unknown status",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.ListTransfers(context.Background(), &pb.ListTransfersRequest{AccountId: uuid.New().String(), Status: pb.TransferStatus(42)})
+				return err
+			},
+			errContains: "invalid status",
+		},
+		{
+			name: "list: malformed page_token",
+			call: func(server *grpcserver.BankServiceServer) error {
+				_, err := server.ListTransfers(context.Background(), &pb.ListTransfersRequest{AccountId: uuid.New().String(), PageToken: "not a token"})
+				return err
+			},
+			errContains: "invalid page_token",
+		},
 	}
 
-	if st.Code() != codes.Unimplemented {
-		t.Errorf("expected Unimplemented, got %v", st.Code())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Validation errors happen before any repository is used
+			server := grpcserver.NewBankServiceServer(&domain.TransferService{}, &domain.AccountService{})
+
+			err := tt.call(server)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("expected gRPC status error, got: %v", err)
+			}
+
+			if st.Code() != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", st.Code())
+			}
+			if !strings.Contains(st.Message(), tt.errContains) {
+				t.Errorf("expected error message to contain %q, got %q", tt.errContains, st.Message())
+			}
+		})
 	}
 }
@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int
+		wantErr  bool
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"010_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+				"010_add_index.down.sql":    {Data: []byte("DROP INDEX")},
+				"002_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"002_create_table.down.sql": {Data: []byte("DROP TABLE")},
+				"README.md":                 {Data: []byte("# Migrations")},
+			},
+			versions: []int{2, 10},
+		},
+		{
+			name: "missing down file",
+			files: fstest.MapFS{
+				"001_create_table.up.sql": {Data: []byte("CREATE TABLE")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same version with different names",
+			files: fstest.MapFS{
+				"001_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+				"001_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+				"001_add_index.down.sql":    {Data: []byte("DROP INDEX")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version zero",
+			files: fstest.MapFS{
+				"000_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"000_create_table.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := Load(tt.files)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d migrations", len(loaded))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(loaded) != len(tt.versions) {
+				t.Fatalf("expected %d migrations, got %d", len(tt.versions), len(loaded))
+			}
+			for i, version := range tt.versions {
+				if loaded[i].Version != version {
+					t.Errorf("expected migration %d to have version %d, got %d", i, version, loaded[i].Version)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loaded) == 0 || loaded[0].String() != "001_create_accounts_table" {
+		t.Errorf("expected embedded migrations starting with 001_create_accounts_table, got %v", loaded)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	loaded := []Migration{{Version: 1, Name: "a"}, {Version: 2, Name: "b"}, {Version: 5, Name: "c"}}
+
+	type expectedStep struct {
+		version int
+		up      bool
+		after   int
+	}
+	tests := []struct {
+		name    string
+		current int
+		target  int
+		steps   []expectedStep
+		wantErr bool
+	}{
+		{name: "up from empty", current: 0, target: 5, steps: []expectedStep{{1, true, 1}, {2, true, 2}, {5, true, 5}}},
+		{name: "up from version", current: 2, target: 5, steps: []expectedStep{{5, true, 5}}},
+		{name: "down one", current: 5, target: 2, steps: []expectedStep{{5, false, 2}}},
+		{name: "down to empty", current: 2, target: 0, steps: []expectedStep{{2, false, 1}, {1, false, 0}}},
+		{name: "nothing to do", current: 2, target: 2},
+		{name: "unknown target", current: 2, target: 3, wantErr: true},
+		{name: "unknown current", current: 7, target: 5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := plan(loaded, tt.current, tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d steps", len(steps))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(steps) != len(tt.steps) {
+				t.Fatalf("expected %d steps, got %d", len(tt.steps), len(steps))
+			}
+			for i, expected := range tt.steps {
+				s := steps[i]
+				if s.migration.Version != expected.version || s.up != expected.up || s.version != expected.after {
+					t.Errorf("step %d: expected %+v, got migration %d up=%v version %d",
+						i, expected, s.migration.Version, s.up, s.version)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockName identifies the advisory lock serializing migration runs against the database
+const lockName = "bank-service:schema_migrations"
+
+// pgUndefinedTable is the SQLSTATE of a query on a missing table
+const pgUndefinedTable = "42P01"
+
+// ErrDirty is returned when a previous run failed in the middle of a migration,
+// which leaves the schema in an unknown state. Fix the schema manually and set the version with Force.
+var ErrDirty = errors.New("database is dirty")
+
+// Status describes the schema version of the database.
+type Status struct {
+	Version int  // Version of the last applied migration, 0 if none is applied
+	Dirty   bool // Whether a migration failed in the middle
+
+	Applied []Migration
+	Pending []Migration
+}
+
+// Migrator applies migrations to a PostgreSQL database.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// New creates a new Migrator of the migrations in the root of fsys.
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(current int) (int, error) {
+		if len(m.migrations) == 0 {
+			return 0, nil
+		}
+		return m.migrations[len(m.migrations)-1].Version, nil
+	})
+}
+
+// Down reverts the given number of the last applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("number of migrations to revert must be positive, got %d", steps)
+	}
+
+	return m.run(ctx, func(current int) (int, error) {
+		index, err := indexOf(m.migrations, current)
+		if err != nil {
+			return 0, fmt.Errorf("database version: %w", err)
+		}
+		if index-steps < 0 {
+			return 0, nil
+		}
+		return m.migrations[index-steps].Version, nil
+	})
+}
+
+// To applies or reverts migrations until the database has the given version.
+// Version 0 reverts all migrations.
+func (m *Migrator) To(ctx context.Context, version int) error {
+	if _, err := indexOf(m.migrations, version); err != nil {
+		return err
+	}
+
+	return m.run(ctx, func(current int) (int, error) {
+		return version, nil
+	})
+}
+
+// Force sets the database version without running migrations and clears the dirty flag.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if _, err := indexOf(m.migrations, version); err != nil {
+		return err
+	}
+
+	return m.withLock(ctx, func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			return setVersion(ctx, tx, version)
+		})
+	})
+}
+
+// Status returns the schema version of the database and the applied and pending migrations.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	version, dirty, err := readVersion(ctx, m.pool)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUndefinedTable {
+		version, dirty, err = 0, false, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Version: version, Dirty: dirty}
+	for _, migration := range m.migrations {
+		if migration.Version <= version {
+			status.Applied = append(status.Applied, migration)
+		} else {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+
+	return status, nil
+}
+
+// run migrates the database to the version returned by target for the current version.
+func (m *Migrator) run(ctx context.Context, target func(current int) (int, error)) error {
+	return m.withLock(ctx, func(conn *pgx.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+
+		version, err := target(current)
+		if err != nil {
+			return err
+		}
+		steps, err := plan(m.migrations, current, version)
+		if err != nil {
+			return err
+		}
+
+		if len(steps) == 0 {
+			log.Printf("database is at version %d, no migrations to run", current)
+			return nil
+		}
+
+		for _, s := range steps {
+			if err := applyStep(ctx, conn, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applyStep runs the migration and records the new version in one transaction,
+// so a failed migration leaves neither schema changes nor a dirty version behind.
+func applyStep(ctx context.Context, conn *pgx.Conn, s step) error {
+	action, done, sql := "apply", "applied", s.migration.Up
+	if !s.up {
+		action, done, sql = "revert", "reverted", s.migration.Down
+	}
+
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+		return setVersion(ctx, tx, s.version)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s migration %s: %w", action, s.migration, err)
+	}
+
+	log.Printf("%s migration %s", done, s.migration)
+	return nil
+}
+
+// withLock runs fn on a connection holding the migration advisory lock.
+// Concurrent runs, e.g. several replicas migrating at startup, wait for each other.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock(hashtextextended($1, 0))", lockName); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer func() {
+		// Unlock even if ctx is cancelled; a connection that fails to unlock is closed,
+		// which releases the lock instead of returning it to the pool.
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock(hashtextextended($1, 0))", lockName); err != nil {
+			log.Printf("failed to unlock migrations: %v", err)
+			conn.Conn().Close(context.Background())
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty BOOLEAN NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn.Conn())
+}
+
+// querier is a connection, pool or transaction reading the version
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// readVersion returns the current database version and whether it is dirty.
+func readVersion(ctx context.Context, q querier) (int, bool, error) {
+	var version int
+	var dirty bool
+	err := q.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// setVersion records the clean database version; version 0 leaves the table empty.
+func setVersion(ctx context.Context, tx pgx.Tx, version int) error {
+	if _, err := tx.Exec(ctx, "TRUNCATE schema_migrations"); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", version); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return nil
+}
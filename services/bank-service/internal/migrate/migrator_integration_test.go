@@ -0,0 +1,132 @@
+package migrate_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/db"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/internal/migrate"
+	"github.com/spbu-ds-practicum-2025/example-project/services/bank-service/migrations"
+)
+
+// TestMigratorIntegration applies and reverts the embedded migrations on PostgreSQL,
+// checking that every down migration reverts its up migration.
+func TestMigratorIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	container, dbURL := startPostgresContainer(t, ctx)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres container: %v", err)
+		}
+	}()
+
+	pool, err := db.NewPool(ctx, dbURL)
+	if err != nil {
+		t.Fatalf("failed to create database pool: %v", err)
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool.Pool, migrations.FS)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	// Concurrent runs wait for each other and apply every migration once
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- migrator.Up(ctx)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error applying migrations: %v", err)
+		}
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status.Pending) != 0 || status.Dirty || status.Version != status.Applied[len(status.Applied)-1].Version {
+		t.Fatalf("expected all migrations to be applied, got %+v", status)
+	}
+	latest := status.Version
+
+	if err := migrator.Down(ctx, 1); err != nil {
+		t.Fatalf("unexpected error reverting a migration: %v", err)
+	}
+	status, err = migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status.Pending) != 1 || status.Pending[0].Version != latest {
+		t.Errorf("expected only the latest migration to be pending, got %+v", status.Pending)
+	}
+
+	if err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("unexpected error reverting all migrations: %v", err)
+	}
+	var accountsExist bool
+	if err := pool.QueryRow(ctx, "SELECT to_regclass('accounts') IS NOT NULL").Scan(&accountsExist); err != nil {
+		t.Fatalf("failed to check accounts table: %v", err)
+	}
+	if accountsExist {
+		t.Error("expected accounts table to be dropped after reverting all migrations")
+	}
+
+	// Applying again after a full revert succeeds
+	if err := migrator.To(ctx, latest); err != nil {
+		t.Fatalf("unexpected error applying migrations again: %v", err)
+	}
+}
+
+// startPostgresContainer starts a PostgreSQL testcontainer and returns the connection URL.
+func startPostgresContainer(t *testing.T, ctx context.Context) (testcontainers.Container, string) {
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:15",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     "testuser",
+			"POSTGRES_PASSWORD": "testpass",
+			"POSTGRES_DB":       "testdb",
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get postgres host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "5432")
+	if err != nil {
+		t.Fatalf("failed to get postgres port: %v", err)
+	}
+
+	dbURL := fmt.Sprintf("postgres://testuser:testpass@%s:%s/testdb?sslmode=disable", host, port.Port())
+	return container, dbURL
+}
@@ -0,0 +1,4 @@
+-- Rollback: Drop outbox table
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+DROP TABLE IF EXISTS outbox;
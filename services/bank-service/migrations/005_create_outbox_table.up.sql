@@ -0,0 +1,30 @@
+-- Create outbox table
+-- This table stores domain events written in the same transaction as the business change
+-- (transactional outbox pattern). A background relay drains it to RabbitMQ.
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL CHECK (event_type IN ('transfer.completed')),
+    aggregate_id UUID NOT NULL,
+    account_ids UUID[] NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Partial index for the relay: only undispatched events, in creation order
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE dispatched_at IS NULL;
+
+-- Add comments to table and columns
+COMMENT ON TABLE outbox IS 'Transactional outbox of domain events awaiting publication to RabbitMQ';
+COMMENT ON COLUMN outbox.id IS 'Monotonic identifier defining the publication order';
+COMMENT ON COLUMN outbox.event_type IS 'Type of the event (e.g., transfer.completed)';
+COMMENT ON COLUMN outbox.aggregate_id IS 'Identifier of the operation the event is about (e.g., transfer ID)';
+COMMENT ON COLUMN outbox.account_ids IS 'Accounts affected by the event - events are published in order per account';
+COMMENT ON COLUMN outbox.attempts IS 'Number of failed publication attempts';
+COMMENT ON COLUMN outbox.last_error IS 'Error message of the last failed publication attempt';
+COMMENT ON COLUMN outbox.created_at IS 'Timestamp when the event was recorded';
+COMMENT ON COLUMN outbox.next_attempt_at IS 'Earliest time of the next publication attempt (retry backoff)';
+COMMENT ON COLUMN outbox.dispatched_at IS 'Timestamp when the event was confirmed by the broker (NULL if pending)';
@@ -0,0 +1,10 @@
+-- Rollback: Drop topups table
+
+DELETE FROM outbox WHERE event_type = 'topup.completed';
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_event_type_check;
+ALTER TABLE outbox ADD CONSTRAINT outbox_event_type_check
+    CHECK (event_type IN ('transfer.completed'));
+
+DROP INDEX IF EXISTS idx_topups_external_transaction_id;
+DROP INDEX IF EXISTS idx_topups_account_created;
+DROP TABLE IF EXISTS topups;
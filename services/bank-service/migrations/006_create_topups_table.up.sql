@@ -0,0 +1,44 @@
+-- Create topups table
+-- This table stores all operations that add funds to an account from an external source
+
+CREATE TABLE IF NOT EXISTS topups (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL,
+    amount_value NUMERIC(15, 2) NOT NULL CHECK (amount_value > 0),
+    amount_currency_code VARCHAR(3) NOT NULL CHECK (LENGTH(amount_currency_code) = 3),
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    source VARCHAR(50),
+    external_transaction_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'SUCCESS')),
+    message TEXT,
+    new_balance_value NUMERIC(15, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    -- Foreign key constraints
+    CONSTRAINT fk_topup_account FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT
+);
+
+-- Create indexes for efficient queries
+CREATE INDEX idx_topups_account_created ON topups(account_id, created_at DESC);
+CREATE INDEX idx_topups_external_transaction_id ON topups(external_transaction_id);
+
+-- Allow top-up events in the outbox
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_event_type_check;
+ALTER TABLE outbox ADD CONSTRAINT outbox_event_type_check
+    CHECK (event_type IN ('transfer.completed', 'topup.completed'));
+
+-- Add comments to table and columns
+COMMENT ON TABLE topups IS 'Top-up operations crediting accounts from external sources';
+COMMENT ON COLUMN topups.id IS 'Unique identifier of the top-up operation (UUID)';
+COMMENT ON COLUMN topups.account_id IS 'Account ID being credited';
+COMMENT ON COLUMN topups.amount_value IS 'Amount added to the account (decimal with 2 decimal places)';
+COMMENT ON COLUMN topups.amount_currency_code IS 'ISO 4217 currency code (e.g., RUB, USD, EUR)';
+COMMENT ON COLUMN topups.idempotency_key IS 'Unique key to ensure idempotent operations - prevents duplicate top-ups';
+COMMENT ON COLUMN topups.source IS 'Source of the funds (e.g., bank_card, external_transfer), optional';
+COMMENT ON COLUMN topups.external_transaction_id IS 'Reference to the payment gateway transaction for reconciliation (optional)';
+COMMENT ON COLUMN topups.status IS 'Current status of the top-up: PENDING or SUCCESS';
+COMMENT ON COLUMN topups.message IS 'Human-readable message about the top-up result';
+COMMENT ON COLUMN topups.new_balance_value IS 'Account balance right after the top-up, returned on idempotent retries';
+COMMENT ON COLUMN topups.created_at IS 'Timestamp when the top-up was initiated';
+COMMENT ON COLUMN topups.completed_at IS 'Timestamp when the top-up was completed (NULL if still pending)';
@@ -0,0 +1,9 @@
+-- Rollback: Remove owner metadata and lifecycle status from accounts
+
+DROP INDEX IF EXISTS idx_accounts_owner_created_id;
+DROP INDEX IF EXISTS idx_accounts_created_id;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS chk_accounts_closed_at;
+ALTER TABLE accounts DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE accounts DROP COLUMN IF EXISTS status;
+ALTER TABLE accounts DROP COLUMN IF EXISTS owner_name;
+ALTER TABLE accounts DROP COLUMN IF EXISTS owner_id;
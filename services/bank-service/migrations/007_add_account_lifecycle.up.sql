@@ -0,0 +1,22 @@
+-- Add owner metadata and lifecycle status to accounts
+-- Existing accounts (e.g. seed data) have no owner and become ACTIVE
+
+ALTER TABLE accounts ADD COLUMN owner_id VARCHAR(255);
+ALTER TABLE accounts ADD COLUMN owner_name VARCHAR(255);
+ALTER TABLE accounts ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE'
+    CHECK (status IN ('ACTIVE', 'FROZEN', 'CLOSED'));
+ALTER TABLE accounts ADD COLUMN closed_at TIMESTAMP WITH TIME ZONE;
+
+-- A closed account always has a closing timestamp, an open one never has
+ALTER TABLE accounts ADD CONSTRAINT chk_accounts_closed_at
+    CHECK ((status = 'CLOSED') = (closed_at IS NOT NULL));
+
+-- Create indexes for listing accounts in creation order (keyset pagination)
+CREATE INDEX idx_accounts_created_id ON accounts(created_at, id);
+CREATE INDEX idx_accounts_owner_created_id ON accounts(owner_id, created_at, id);
+
+-- Add comments to new columns
+COMMENT ON COLUMN accounts.owner_id IS 'Identifier of the account owner (e.g., wallet user ID), NULL for legacy accounts';
+COMMENT ON COLUMN accounts.owner_name IS 'Display name of the account owner (optional)';
+COMMENT ON COLUMN accounts.status IS 'Lifecycle status of the account: ACTIVE, FROZEN or CLOSED';
+COMMENT ON COLUMN accounts.closed_at IS 'Timestamp when the account was closed (NULL unless CLOSED)';
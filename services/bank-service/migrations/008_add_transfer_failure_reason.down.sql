@@ -0,0 +1,4 @@
+-- Rollback: Remove the failure reason from transfers
+
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS chk_transfers_failure_reason;
+ALTER TABLE transfers DROP COLUMN IF EXISTS failure_reason;
@@ -0,0 +1,14 @@
+-- Add a machine-readable failure reason to transfers
+-- Transfers that failed before the column existed are recorded as INTERNAL failures
+
+ALTER TABLE transfers ADD COLUMN failure_reason VARCHAR(30)
+    CHECK (failure_reason IN ('INSUFFICIENT_FUNDS', 'ACCOUNT_NOT_ACTIVE', 'CURRENCY_MISMATCH', 'INTERNAL'));
+
+UPDATE transfers SET failure_reason = 'INTERNAL' WHERE status = 'FAILED';
+
+-- A failed transfer always has a failure reason, any other transfer never has
+ALTER TABLE transfers ADD CONSTRAINT chk_transfers_failure_reason
+    CHECK ((status = 'FAILED') = (failure_reason IS NOT NULL));
+
+-- Add comments to new columns
+COMMENT ON COLUMN transfers.failure_reason IS 'Reason of the failure: INSUFFICIENT_FUNDS, ACCOUNT_NOT_ACTIVE, CURRENCY_MISMATCH or INTERNAL (NULL unless FAILED)';
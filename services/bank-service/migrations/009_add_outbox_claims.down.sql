@@ -0,0 +1,3 @@
+-- Rollback: Remove claim leases from the outbox
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS claimed_until;
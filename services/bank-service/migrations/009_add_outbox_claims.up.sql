@@ -0,0 +1,8 @@
+-- Add claim leases to the outbox
+-- The relay claims a batch of messages in a short transaction and publishes them after it commits,
+-- so no row locks are held while waiting for broker confirmations
+
+ALTER TABLE outbox ADD COLUMN claimed_until TIMESTAMP WITH TIME ZONE;
+
+-- Add comments to new columns
+COMMENT ON COLUMN outbox.claimed_until IS 'End of the lease of the relay publishing the message (NULL if not claimed)';
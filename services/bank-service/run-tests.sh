@@ -40,6 +40,8 @@ case "$TEST_TYPE" in
         echo -e "${GREEN}Docker is running ✓${NC}"
         echo ""
         
+        go test -v ./internal/db/... -run TestContract -timeout 10m
+        go test -v ./internal/migrate/... -run TestMigratorIntegration -timeout 10m
         go test -v ./internal/grpc/... -run TestTransferMoneyIntegration -timeout 10m
         ;;
     
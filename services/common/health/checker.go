@@ -0,0 +1,149 @@
+// Package health reports the health of the service and its dependencies
+// over the gRPC health checking protocol (grpc.health.v1).
+//
+// It is a module of its own, shared by bank-service, analytics-service and bank-card-adapter,
+// which require it through a replace directive pointing at services/common/health.
+package health
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// CheckFunc checks a dependency and returns an error if it is unhealthy.
+type CheckFunc func(ctx context.Context) error
+
+// Dependency is a dependency of the service whose health is checked periodically.
+type Dependency struct {
+	Name  string // Health service name the status of the dependency is reported under, e.g. "database"
+	Check CheckFunc
+	// Optional dependencies only report their own status and do not make the service NOT_SERVING,
+	// e.g. because the service keeps working without them
+	Optional bool
+}
+
+// Checker periodically checks the dependencies and updates the serving status of a health server.
+//
+// Every dependency is reported under its own name. The overall status, reported under ""
+// and the names of the gRPC services, is SERVING if all required dependencies are healthy.
+// All statuses are NOT_SERVING until the first check completes.
+type Checker struct {
+	server       *health.Server
+	services     []string
+	dependencies []Dependency
+	timeout      time.Duration
+
+	mu      sync.Mutex
+	healthy map[string]bool // Result of the last check per dependency, used to log changes
+}
+
+// NewChecker creates a Checker of the dependencies that reports the overall status under ""
+// and the given gRPC service names. Each check is cancelled after timeout.
+func NewChecker(services []string, dependencies []Dependency, timeout time.Duration) *Checker {
+	c := &Checker{
+		server:       health.NewServer(),
+		services:     append([]string{""}, services...),
+		dependencies: dependencies,
+		timeout:      timeout,
+		healthy:      make(map[string]bool),
+	}
+
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	for _, dependency := range c.dependencies {
+		c.server.SetServingStatus(dependency.Name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return c
+}
+
+// Register registers the grpc.health.v1.Health service with the gRPC server.
+func (c *Checker) Register(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, c.server)
+}
+
+// Run checks the dependencies immediately and then every interval until the context is cancelled.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.CheckAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll checks all dependencies concurrently and updates the serving statuses.
+// It returns whether all required dependencies are healthy.
+func (c *Checker) CheckAll(ctx context.Context) bool {
+	errs := make([]error, len(c.dependencies))
+	var wg sync.WaitGroup
+	for i, dependency := range c.dependencies {
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			errs[i] = check(checkCtx)
+		}(i, dependency.Check)
+	}
+	wg.Wait()
+
+	serving := true
+	for i, dependency := range c.dependencies {
+		c.setStatus(dependency.Name, errs[i])
+		if errs[i] != nil && !dependency.Optional {
+			serving = false
+		}
+	}
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+
+	return serving
+}
+
+// Shutdown sets all statuses to NOT_SERVING and ignores later checks,
+// so that clients stop sending new calls while the gRPC server drains in-flight calls.
+// Open Watch streams stay open until the gRPC server stops them.
+func (c *Checker) Shutdown() {
+	c.server.Shutdown()
+}
+
+// setStatus reports the result of a dependency check and logs when the dependency becomes unhealthy or recovers.
+func (c *Checker) setStatus(name string, err error) {
+	c.mu.Lock()
+	wasHealthy, checked := c.healthy[name]
+	c.healthy[name] = err == nil
+	c.mu.Unlock()
+
+	if err != nil {
+		if wasHealthy || !checked {
+			log.Printf("health check of %s failed: %v", name, err)
+		}
+		c.server.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+
+	if checked && !wasHealthy {
+		log.Printf("health check of %s recovered", name)
+	}
+	c.server.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+}
@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// statusOf returns the serving status the checker reports for the service
+func statusOf(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	resp, err := c.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("unexpected error checking %q: %v", service, err)
+	}
+	return resp.GetStatus()
+}
+
+func TestChecker(t *testing.T) {
+	const (
+		serving    = healthpb.HealthCheckResponse_SERVING
+		notServing = healthpb.HealthCheckResponse_NOT_SERVING
+	)
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	healthy := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name         string
+		dependencies []Dependency
+		expected     map[string]healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name: "all dependencies healthy",
+			dependencies: []Dependency{
+				{Name: "database", Check: healthy},
+				{Name: "broker", Check: healthy, Optional: true},
+			},
+			expected: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": serving, "example.v1.ExampleService": serving, "database": serving, "broker": serving,
+			},
+		},
+		{
+			name: "required dependency unhealthy",
+			dependencies: []Dependency{
+				{Name: "database", Check: failing},
+				{Name: "broker", Check: healthy, Optional: true},
+			},
+			expected: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": notServing, "example.v1.ExampleService": notServing, "database": notServing, "broker": serving,
+			},
+		},
+		{
+			name: "optional dependency unhealthy",
+			dependencies: []Dependency{
+				{Name: "database", Check: healthy},
+				{Name: "broker", Check: failing, Optional: true},
+			},
+			expected: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": serving, "example.v1.ExampleService": serving, "database": serving, "broker": notServing,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker([]string{"example.v1.ExampleService"}, tt.dependencies, time.Second)
+			if status := statusOf(t, c, ""); status != notServing {
+				t.Errorf("expected NOT_SERVING before the first check, got %s", status)
+			}
+
+			c.CheckAll(context.Background())
+
+			for service, expected := range tt.expected {
+				if status := statusOf(t, c, service); status != expected {
+					t.Errorf("expected %q to be %s, got %s", service, expected, status)
+				}
+			}
+		})
+	}
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	c := NewChecker(nil, []Dependency{{Name: "database", Check: hanging}}, 10*time.Millisecond)
+
+	if c.CheckAll(context.Background()) {
+		t.Error("expected a check exceeding its timeout to fail")
+	}
+}
+
+func TestChecker_Shutdown(t *testing.T) {
+	c := NewChecker(nil, []Dependency{{Name: "database", Check: func(ctx context.Context) error { return nil }}}, time.Second)
+	c.CheckAll(context.Background())
+
+	c.Shutdown()
+	if status := statusOf(t, c, ""); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING after shutdown, got %s", status)
+	}
+
+	// Checks after shutdown do not flip the service back to SERVING
+	c.CheckAll(context.Background())
+	if status := statusOf(t, c, ""); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING after a check following shutdown, got %s", status)
+	}
+}
@@ -30,4 +30,19 @@ protoc -I="$PROTO_ROOT" \
   --go_out="$OUT_DIR/analytics-service/proto" --go-grpc_out="$OUT_DIR/analytics-service/proto" \
   "$PROTO_ROOT/analytics-service-api/analytics_service.proto"
 
+echo "Generating Go stubs for bank-card-adapter-api inside bank-card-adapter..."
+protoc -I="$PROTO_ROOT" \
+  --go_out="$OUT_DIR/bank-card-adapter/proto" --go-grpc_out="$OUT_DIR/bank-card-adapter/proto" \
+  "$PROTO_ROOT/bank-card-adapter-api/bank_card_adapter.proto"
+
+echo "Generating Go stubs for bank-service inside bank-card-adapter..."
+protoc -I="$PROTO_ROOT" \
+  --go_out="$OUT_DIR/bank-card-adapter/proto" --go-grpc_out="$OUT_DIR/bank-card-adapter/proto" \
+  "$PROTO_ROOT/bank-service-api/bank_service.proto"
+
+echo "Generating Go stubs for bank-card-adapter-api inside api-gateway..."
+protoc -I="$PROTO_ROOT" \
+  --go_out="$OUT_DIR/api-gateway/proto" --go-grpc_out="$OUT_DIR/api-gateway/proto" \
+  "$PROTO_ROOT/bank-card-adapter-api/bank_card_adapter.proto"
+
 exit 0
@@ -201,6 +201,8 @@ echo ""
 echo -e "${YELLOW}Starting API Gateway on port ${API_GATEWAY_PORT}...${NC}"
 cd "${API_GATEWAY_DIR}"
 export BANK_SERVICE_ADDRESS="localhost:${BANK_SERVICE_PORT}"
+# Bank Card Adapter is not started here, so top-ups go directly to Bank Service
+export TOPUP_MODE="direct"
 export PORT="${API_GATEWAY_PORT}"
 ./bin/api-gateway > logs/api-gateway.log 2>&1 &
 API_PID=$!
@@ -259,6 +261,16 @@ echo -e '    "amount": {"value": "50.00", "currency_code": "RUB"},'
 echo -e '    "idempotency_key": "test-transfer-1"'
 echo -e '  }'"'"
 echo ""
+echo -e "${CYAN}# Top up account${NC}"
+echo -e 'curl -X POST http://localhost:8080/accounts/11111111-1111-1111-1111-111111111111/topups \\'
+echo -e '  -H "Content-Type: application/json" \\'
+echo -e '  -H "X-Idempotency-Key: $(uuidgen)" \\'
+echo -e '  -d '"'"'{'
+echo -e '    "accountId": "11111111-1111-1111-1111-111111111111",'
+echo -e '    "amount": {"value": "100.00", "currencyCode": "RUB"},'
+echo -e '    "method": {"topUpMethod": "BankCard", "cardDetails": {"cardNumber": "4111111111111111", "expiryDate": "12/30", "cvv": "123"}}'
+echo -e '  }'"'"
+echo ""
 echo -e "${CYAN}# Check account balance${NC}"
 echo -e 'curl http://localhost:8080/accounts/11111111-1111-1111-1111-111111111111'
 echo ""